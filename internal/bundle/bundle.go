@@ -0,0 +1,336 @@
+package bundle
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+)
+
+const (
+	// FormatVersion is the bundle layout version written into the manifest.
+	FormatVersion = 1
+
+	manifestName  = "manifest.json"
+	indexName     = "index.json"
+	checksumsName = "SHA256SUMS"
+	archivesDir   = "archives/"
+
+	// maxSignatureSize bounds the size of a bundled detached signature.
+	maxSignatureSize = 64 << 10
+)
+
+// Manifest describes the contents of a bundle.
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Source        string    `json:"source"`
+	Constraint    string    `json:"constraint"`
+	Platforms     []string  `json:"platforms"`
+	Versions      []string  `json:"versions"`
+}
+
+// Write creates a bundle tar at outPath holding the manifest, the release index subset, a SHA256SUMS file,
+// and the archives. archives maps each archive file name listed in releases to its local path; a detached
+// signature next to an archive (<archive>.asc) is bundled with it so imported archives can still be verified.
+// The bundle is written to a temporary file first so a failed write never leaves a truncated bundle behind.
+// Returns an error if an archive is missing or any write fails.
+func Write(outPath string, manifest *Manifest, releases []_golang.Release, archives map[string]string) error {
+	files := bundledFiles(releases)
+	for _, file := range files {
+		if _, ok := archives[file.Filename]; !ok {
+			return fmt.Errorf("archive %s is listed in the index but was not provided", file.Filename)
+		}
+	}
+
+	dir := filepath.Dir(outPath)
+	tmpFile, err := os.CreateTemp(dir, ".govman-bundle-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create bundle file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	tw := tar.NewWriter(tmpFile)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	indexData, err := json.MarshalIndent(releases, "", "  ")
+	if err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to encode release index: %w", err)
+	}
+
+	var sums strings.Builder
+	for _, file := range files {
+		fmt.Fprintf(&sums, "%s  %s\n", file.Sha256, file.Filename)
+	}
+
+	// Metadata goes first so Import knows the expected checksums before it sees any archive
+	entries := []struct {
+		name string
+		data []byte
+	}{
+		{manifestName, manifestData},
+		{indexName, indexData},
+		{checksumsName, []byte(sums.String())},
+	}
+	for _, entry := range entries {
+		if err := writeEntry(tw, entry.name, entry.data, manifest.CreatedAt); err != nil {
+			tmpFile.Close()
+			return err
+		}
+	}
+
+	for _, file := range files {
+		if err := writeArchive(tw, archives[file.Filename], archivesDir+file.Filename); err != nil {
+			tmpFile.Close()
+			return err
+		}
+
+		sigPath := archives[file.Filename] + _golang.ArchiveSignatureSuffix
+		if _, err := os.Stat(sigPath); err == nil {
+			if err := writeArchive(tw, sigPath, archivesDir+file.Filename+_golang.ArchiveSignatureSuffix); err != nil {
+				tmpFile.Close()
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close bundle file: %w", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), outPath); err != nil {
+		return fmt.Errorf("failed to save bundle: %w", err)
+	}
+
+	return nil
+}
+
+// Import reads the bundle at bundlePath, verifies every archive against the bundled checksums,
+// and places the archives and their bundled signatures into cacheDir. Returns the manifest and the bundled releases, or an error
+// if the bundle is malformed, an archive is corrupt, or an archive listed in the index is missing.
+func Import(bundlePath, cacheDir string) (*Manifest, []_golang.Release, error) {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	var (
+		manifest *Manifest
+		releases []_golang.Release
+		expected map[string]string
+		imported = make(map[string]bool)
+	)
+
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+
+		switch {
+		case header.Name == manifestName:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse bundle manifest: %w", err)
+			}
+			if manifest.FormatVersion > FormatVersion {
+				return nil, nil, fmt.Errorf("bundle format %d is newer than supported format %d - upgrade govman", manifest.FormatVersion, FormatVersion)
+			}
+
+		case header.Name == indexName:
+			if err := json.NewDecoder(tr).Decode(&releases); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse bundle index: %w", err)
+			}
+			expected = make(map[string]string)
+			for _, f := range bundledFiles(releases) {
+				expected[f.Filename] = f.Sha256
+			}
+
+		case strings.HasPrefix(header.Name, archivesDir):
+			name := strings.TrimPrefix(header.Name, archivesDir)
+			if name == "" || name != path.Base(name) || strings.Contains(name, "..") {
+				return nil, nil, fmt.Errorf("unsafe path in bundle: %s", header.Name)
+			}
+			if expected == nil {
+				return nil, nil, fmt.Errorf("bundle archive %s appears before the release index", name)
+			}
+			if archive, isSignature := strings.CutSuffix(name, _golang.ArchiveSignatureSuffix); isSignature {
+				if _, ok := expected[archive]; !ok {
+					return nil, nil, fmt.Errorf("bundle signature %s does not belong to an archive in the release index", name)
+				}
+				if err := extractSignature(tr, filepath.Join(cacheDir, name)); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			sum, ok := expected[name]
+			if !ok {
+				return nil, nil, fmt.Errorf("bundle archive %s is not listed in the release index", name)
+			}
+			if err := extractArchive(tr, filepath.Join(cacheDir, name), sum); err != nil {
+				return nil, nil, err
+			}
+			imported[name] = true
+		}
+	}
+
+	if manifest == nil || releases == nil {
+		return nil, nil, fmt.Errorf("not a govman bundle: missing %s or %s", manifestName, indexName)
+	}
+
+	for name := range expected {
+		if !imported[name] {
+			return nil, nil, fmt.Errorf("bundle is incomplete: archive %s is missing", name)
+		}
+	}
+
+	return manifest, releases, nil
+}
+
+// bundledFiles returns every file of releases in a stable order.
+func bundledFiles(releases []_golang.Release) []_golang.File {
+	var files []_golang.File
+	for _, release := range releases {
+		files = append(files, release.Files...)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Filename < files[j].Filename
+	})
+	return files
+}
+
+// writeEntry writes an in-memory file into the tar stream.
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeArchive copies the archive at srcPath into the tar stream under name.
+func writeArchive(tw *tar.Writer, srcPath, name string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer src.Close()
+
+	stat, err := src.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat archive: %w", err)
+	}
+
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    stat.Size(),
+		ModTime: stat.ModTime(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, src); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// extractArchive streams an archive from the bundle to targetPath, verifying its SHA-256 on the way.
+// The archive only appears at targetPath once the checksum matches.
+func extractArchive(r io.Reader, targetPath, expectedSHA256 string) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(targetPath), ".govman-import-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmpFile, hasher), r); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(targetPath), err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close cache file: %w", err)
+	}
+
+	actualSHA256 := fmt.Sprintf("%x", hasher.Sum(nil))
+	if actualSHA256 != expectedSHA256 {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", filepath.Base(targetPath), expectedSHA256, actualSHA256)
+	}
+
+	if err := os.Rename(tmpFile.Name(), targetPath); err != nil {
+		return fmt.Errorf("failed to store %s: %w", filepath.Base(targetPath), err)
+	}
+
+	return nil
+}
+
+// extractSignature stores a detached signature from the bundle at targetPath. Signatures are not listed in
+// SHA256SUMS; they are checked against the trusted keys when the archive is installed.
+func extractSignature(r io.Reader, targetPath string) error {
+	data, err := io.ReadAll(io.LimitReader(r, maxSignatureSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(targetPath), err)
+	}
+	if len(data) > maxSignatureSize {
+		return fmt.Errorf("signature %s is larger than %d bytes", filepath.Base(targetPath), maxSignatureSize)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(targetPath), ".govman-import-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(targetPath), err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close cache file: %w", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), targetPath); err != nil {
+		return fmt.Errorf("failed to store %s: %w", filepath.Base(targetPath), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package bundle
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+)
+
+// createTestArchives writes fake archives into dir and returns matching releases and the archive paths
+func createTestArchives(t *testing.T, dir string) ([]_golang.Release, map[string]string) {
+	t.Helper()
+
+	release := _golang.Release{Version: "go1.21.0", Stable: true}
+	archives := make(map[string]string)
+	for _, name := range []string{"go1.21.0.linux-amd64.tar.gz", "go1.21.0.linux-arm64.tar.gz"} {
+		content := []byte("content of " + name)
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatalf("Failed to write archive: %v", err)
+		}
+		archives[name] = path
+		release.Files = append(release.Files, _golang.File{
+			Filename: name,
+			Version:  "go1.21.0",
+			Sha256:   fmt.Sprintf("%x", sha256.Sum256(content)),
+			Size:     int64(len(content)),
+			Kind:     "archive",
+		})
+	}
+
+	return []_golang.Release{release}, archives
+}
+
+func testManifest() *Manifest {
+	return &Manifest{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		Source:        "godev:https://go.dev/dl/?mode=json&include=all",
+		Constraint:    "1.21.0",
+		Platforms:     []string{"linux/amd64", "linux/arm64"},
+		Versions:      []string{"1.21.0"},
+	}
+}
+
+func TestWriteAndImport(t *testing.T) {
+	dir := t.TempDir()
+	releases, archives := createTestArchives(t, dir)
+	bundlePath := filepath.Join(dir, "go-bundle.tar")
+
+	if err := Write(bundlePath, testManifest(), releases, archives); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cacheDir := filepath.Join(dir, "cache")
+	manifest, imported, err := Import(bundlePath, cacheDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if manifest.Constraint != "1.21.0" || len(manifest.Platforms) != 2 {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+	if len(imported) != 1 || len(imported[0].Files) != 2 {
+		t.Fatalf("Unexpected releases: %+v", imported)
+	}
+
+	for name := range archives {
+		if _, err := os.Stat(filepath.Join(cacheDir, name)); err != nil {
+			t.Errorf("Expected %s in cache: %v", name, err)
+		}
+	}
+}
+
+func TestWriteAndImport_Signatures(t *testing.T) {
+	dir := t.TempDir()
+	releases, archives := createTestArchives(t, dir)
+	signed := "go1.21.0.linux-amd64.tar.gz"
+	signature := "-----BEGIN PGP SIGNATURE-----\nsignature of " + signed + "\n-----END PGP SIGNATURE-----\n"
+	os.WriteFile(archives[signed]+_golang.ArchiveSignatureSuffix, []byte(signature), 0644)
+
+	bundlePath := filepath.Join(dir, "go-bundle.tar")
+	if err := Write(bundlePath, testManifest(), releases, archives); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cacheDir := filepath.Join(dir, "cache")
+	if _, _, err := Import(bundlePath, cacheDir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cacheDir, signed+_golang.ArchiveSignatureSuffix))
+	if err != nil || string(data) != signature {
+		t.Errorf("Expected the bundled signature in the cache, got %q (%v)", data, err)
+	}
+	unsigned := filepath.Join(cacheDir, "go1.21.0.linux-arm64.tar.gz"+_golang.ArchiveSignatureSuffix)
+	if _, err := os.Stat(unsigned); !os.IsNotExist(err) {
+		t.Error("Expected no signature for an archive that was bundled without one")
+	}
+}
+
+func TestWriteMissingArchive(t *testing.T) {
+	dir := t.TempDir()
+	releases, archives := createTestArchives(t, dir)
+	delete(archives, "go1.21.0.linux-arm64.tar.gz")
+
+	bundlePath := filepath.Join(dir, "go-bundle.tar")
+	if err := Write(bundlePath, testManifest(), releases, archives); err == nil {
+		t.Fatal("Expected error for missing archive")
+	}
+	if _, err := os.Stat(bundlePath); !os.IsNotExist(err) {
+		t.Error("Expected no bundle to be written")
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	t.Run("Corrupt archive", func(t *testing.T) {
+		dir := t.TempDir()
+		releases, archives := createTestArchives(t, dir)
+		releases[0].Files[0].Sha256 = strings.Repeat("0", 64)
+
+		bundlePath := filepath.Join(dir, "go-bundle.tar")
+		if err := Write(bundlePath, testManifest(), releases, archives); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		cacheDir := filepath.Join(dir, "cache")
+		_, _, err := Import(bundlePath, cacheDir)
+		if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+			t.Fatalf("Expected checksum mismatch, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(cacheDir, releases[0].Files[0].Filename)); !os.IsNotExist(err) {
+			t.Error("Expected corrupt archive not to be placed in cache")
+		}
+	})
+
+	t.Run("Unsafe path", func(t *testing.T) {
+		dir := t.TempDir()
+		bundlePath := filepath.Join(dir, "evil.tar")
+		writeRawTar(t, bundlePath, map[string]string{
+			manifestName:                      `{"format_version": 1}`,
+			indexName:                         `[]`,
+			archivesDir + "../../evil.tar.gz": "x",
+		})
+
+		_, _, err := Import(bundlePath, filepath.Join(dir, "cache"))
+		if err == nil || !strings.Contains(err.Error(), "unsafe path") {
+			t.Errorf("Expected unsafe path error, got %v", err)
+		}
+	})
+
+	t.Run("Signature without archive", func(t *testing.T) {
+		dir := t.TempDir()
+		bundlePath := filepath.Join(dir, "orphan.tar")
+		writeRawTar(t, bundlePath, map[string]string{
+			manifestName: `{"format_version": 1}`,
+			indexName:    `[]`,
+			archivesDir + "go1.21.0.linux-amd64.tar.gz.asc": "signature",
+		})
+
+		_, _, err := Import(bundlePath, filepath.Join(dir, "cache"))
+		if err == nil || !strings.Contains(err.Error(), "does not belong") {
+			t.Errorf("Expected error for a signature without its archive, got %v", err)
+		}
+	})
+
+	t.Run("Not a bundle", func(t *testing.T) {
+		dir := t.TempDir()
+		bundlePath := filepath.Join(dir, "other.tar")
+		writeRawTar(t, bundlePath, map[string]string{"README": "hello"})
+
+		if _, _, err := Import(bundlePath, filepath.Join(dir, "cache")); err == nil {
+			t.Error("Expected error for a tar without bundle metadata")
+		}
+	})
+
+	t.Run("Missing archive", func(t *testing.T) {
+		dir := t.TempDir()
+		bundlePath := filepath.Join(dir, "partial.tar")
+		writeRawTar(t, bundlePath, map[string]string{
+			manifestName: `{"format_version": 1}`,
+			indexName:    `[{"version": "go1.21.0", "files": [{"filename": "go1.21.0.linux-amd64.tar.gz", "sha256": "abc"}]}]`,
+		})
+
+		_, _, err := Import(bundlePath, filepath.Join(dir, "cache"))
+		if err == nil || !strings.Contains(err.Error(), "incomplete") {
+			t.Errorf("Expected incomplete bundle error, got %v", err)
+		}
+	})
+}
+
+// writeRawTar writes entries into a tar file in a deterministic order (metadata first)
+func writeRawTar(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create tar: %v", err)
+	}
+	defer file.Close()
+
+	tw := tar.NewWriter(file)
+	var names []string
+	for _, name := range []string{manifestName, indexName} {
+		if _, ok := entries[name]; ok {
+			names = append(names, name)
+		}
+	}
+	for name := range entries {
+		if name != manifestName && name != indexName {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		data := entries[name]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}); err != nil {
+			t.Fatalf("Failed to write header: %v", err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatalf("Failed to write entry: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed to close tar: %v", err)
+	}
+}
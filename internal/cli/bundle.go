@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	cobra "github.com/spf13/cobra"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+	_logger "github.com/sijunda/govman/internal/logger"
+	_manager "github.com/sijunda/govman/internal/manager"
+)
+
+// newBundleCmd creates the 'bundle' Cobra command grouping the air-gapped bundle subcommands.
+// Returns a *cobra.Command with 'create' and 'import' registered.
+func newBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Move Go releases to machines without internet access",
+		Long: `Create and import self-contained bundles of Go releases for air-gapped machines.
+
+A bundle is a tar file with the selected archives, the matching part of the
+release index, and their checksums. Importing it seeds the cache and the
+persisted release index, so 'govman install' works unchanged afterwards.
+
+Examples:
+  govman bundle create --versions '>=1.21' --platforms linux/amd64,linux/arm64 -o go-bundle.tar
+  govman bundle import go-bundle.tar`,
+	}
+
+	cmd.AddCommand(newBundleCreateCmd(), newBundleImportCmd())
+
+	return cmd
+}
+
+// newBundleCreateCmd creates the 'bundle create' Cobra command.
+// Flags: --versions (constraint), --platforms, --unstable, and --output. Returns a *cobra.Command.
+func newBundleCreateCmd() *cobra.Command {
+	var (
+		versions  string
+		platforms []string
+		unstable  bool
+		output    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Pack Go archives and release metadata into a bundle",
+		Long: `Download, verify, and pack the Go releases matching a version constraint into a bundle.
+
+Constraints combine comparisons with commas: '>=1.21', '>=1.21, <1.23', '1.22'
+(every 1.22.x release), or '1.22.4' (exactly one release).`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if versions == "" {
+				return fmt.Errorf("--versions is required (e.g. --versions '>=1.21')")
+			}
+
+			targets, err := _manager.ParsePlatforms(platforms)
+			if err != nil {
+				return err
+			}
+
+			mgr := _manager.New(getConfig())
+
+			_logger.Info("Creating bundle for Go %s on %s...", versions, strings.Join(platforms, ","))
+			manifest, err := mgr.CreateBundle(versions, targets, unstable, output)
+			if err != nil {
+				_logger.ErrorWithHelp("Failed to create bundle", "Check the version constraint with 'govman list --remote' and your network connection.")
+				return err
+			}
+
+			_logger.Success("Bundle written to %s", output)
+			_logger.Info("Versions: %s", strings.Join(manifest.Versions, ", "))
+			_logger.Info("Platforms: %s", strings.Join(manifest.Platforms, ", "))
+			_logger.Info("Import it on the target machine with: govman bundle import %s", output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&versions, "versions", "", "Version constraint selecting the releases (e.g. '>=1.21')")
+	cmd.Flags().StringSliceVar(&platforms, "platforms", []string{runtime.GOOS + "/" + runtime.GOARCH}, "Target platforms as os/arch, comma-separated")
+	cmd.Flags().BoolVar(&unstable, "unstable", false, "Include beta/rc releases matching the constraint")
+	cmd.Flags().StringVarP(&output, "output", "o", "go-bundle.tar", "Path of the bundle file to write")
+
+	return cmd
+}
+
+// newBundleImportCmd creates the 'bundle import' Cobra command.
+// Expects the bundle path as its only argument. Returns a *cobra.Command.
+func newBundleImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <bundle>",
+		Short: "Seed the cache and release index from a bundle",
+		Long: `Verify a bundle created with 'govman bundle create' and load it into this machine.
+
+Every archive is checked against the bundled checksums before it is placed in
+the cache. The bundled releases are merged into the persisted release index of
+the configured release source.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr := _manager.New(getConfig())
+
+			_logger.Progress("Importing bundle %s", args[0])
+			manifest, err := mgr.ImportBundle(args[0])
+			if errors.Is(err, _golang.ErrSeedRequiresSignature) {
+				_logger.ErrorWithHelp("Bundles cannot be imported while go_releases.require_signature is enabled",
+					"Bundled release indexes are unsigned and would be ignored. Disable go_releases.require_signature to import the bundle.")
+				return err
+			}
+			if err != nil {
+				_logger.ErrorWithHelp("Failed to import bundle", "Make sure the file was created with 'govman bundle create' and was copied completely.")
+				return err
+			}
+
+			_logger.Success("Imported Go %s for %s", strings.Join(manifest.Versions, ", "), strings.Join(manifest.Platforms, ", "))
+			_logger.Info("Install with: govman install <version>")
+
+			return nil
+		},
+	}
+
+	return cmd
+}
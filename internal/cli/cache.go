@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cobra "github.com/spf13/cobra"
+
+	_logger "github.com/sijunda/govman/internal/logger"
+	_manager "github.com/sijunda/govman/internal/manager"
+	_util "github.com/sijunda/govman/internal/util"
+)
+
+// newCacheCmd creates the 'cache' Cobra command grouping download cache inspection and cleanup.
+// Returns a *cobra.Command with 'list', 'verify', and 'prune' registered.
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and selectively clean the download cache",
+		Long: `Inspect the Go archives in the download cache and remove only the ones you
+no longer need. Use 'govman clean' to empty the whole cache instead.`,
+	}
+
+	cmd.AddCommand(newCacheListCmd(), newCacheVerifyCmd(), newCachePruneCmd())
+
+	return cmd
+}
+
+// newCacheListCmd creates the 'cache list' Cobra command.
+// Returns a *cobra.Command that prints each cached archive with version, platform, size, age, and status.
+func newCacheListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List cached Go archives",
+		Aliases: []string{"ls"},
+		Long: `List the Go archives in the download cache with their version, platform,
+size, age, and status:
+
+  complete      the archive has the size listed in the release index
+  partial       an interrupted download that the next install resumes
+  corrupt       the archive is larger than listed in the release index
+  unknown       the release index does not list the archive
+  unrecognised  neither the name nor the release index gives a version`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr := _manager.New(getConfig())
+
+			entries, err := mgr.CacheEntries()
+			if err != nil {
+				_logger.ErrorWithHelp("Unable to read the download cache", "Verify that ~/.govman/cache exists and is accessible.")
+				return err
+			}
+
+			if len(entries) == 0 {
+				_logger.Info("The download cache is empty")
+				return nil
+			}
+
+			_logger.Info("Cached Go archives (%d total):", len(entries))
+			_logger.Info(strings.Repeat("─", 72))
+
+			var total int64
+			for _, entry := range entries {
+				status := entry.Status()
+				if entry.Installed {
+					status += ", installed"
+				}
+				version := entry.Version
+				if version == "" {
+					version = entry.Filename
+				}
+				_logger.Info("  %-12s %-16s %10s %8s   %s", version, entry.OS+"/"+entry.Arch,
+					_util.FormatBytes(entry.Size), formatAge(entry.Age()), status)
+				total += entry.Size
+			}
+
+			_logger.Info(strings.Repeat("─", 72))
+			_logger.Info("Total cache usage: %s", _util.FormatBytes(total))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// newCacheVerifyCmd creates the 'cache verify' Cobra command.
+// Flag: --dry-run reports corrupt archives without removing them. Returns a *cobra.Command.
+func newCacheVerifyCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Re-hash cached archives against the release index",
+		Long: `Re-hash every complete archive in the download cache against the sha256 in
+the release index and remove the ones that do not match. Partial downloads and
+archives the index does not list are skipped.
+
+Examples:
+  govman cache verify
+  govman cache verify --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr := _manager.New(getConfig())
+
+			_logger.Progress("Verifying cached archives")
+			result, err := mgr.VerifyCache(dryRun)
+			if result != nil {
+				for _, entry := range result.Corrupt {
+					if dryRun {
+						_logger.Warning("Corrupt: %s (would remove)", entry.Filename)
+					} else {
+						_logger.Warning("Corrupt: %s (removed)", entry.Filename)
+					}
+				}
+				for _, entry := range result.Skipped {
+					_logger.Verbose("Skipped %s (%s)", entry.Filename, entry.Status())
+				}
+			}
+			if err != nil {
+				_logger.ErrorWithHelp("Cache verification failed", "Verify that ~/.govman/cache is readable and writable.")
+				return err
+			}
+
+			_logger.Info("Valid: %d, corrupt: %d, skipped: %d", len(result.Valid), len(result.Corrupt), len(result.Skipped))
+			reportFreed(result.Freed, dryRun)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report corrupt archives without removing them")
+
+	return cmd
+}
+
+// newCachePruneCmd creates the 'cache prune' Cobra command.
+// Flags: --older-than, --max-size, --keep-installed, and --dry-run. Returns a *cobra.Command.
+func newCachePruneCmd() *cobra.Command {
+	var (
+		olderThan     string
+		maxSize       string
+		keepInstalled bool
+		dryRun        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove old cached archives or shrink the cache to a size",
+		Long: `Remove cached archives that were not downloaded recently, and then the
+oldest remaining archives until the cache fits the maximum size.
+
+Examples:
+  govman cache prune --older-than 30d
+  govman cache prune --max-size 2GB --keep-installed
+  govman cache prune --older-than 2w --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := _manager.CachePruneOptions{KeepInstalled: keepInstalled, DryRun: dryRun}
+
+			if olderThan == "" && maxSize == "" {
+				return fmt.Errorf("set --older-than and/or --max-size")
+			}
+
+			var err error
+			if olderThan != "" {
+				if opts.OlderThan, err = _util.ParseAge(olderThan); err != nil {
+					return err
+				}
+			}
+			if maxSize != "" {
+				if opts.MaxSize, err = _util.ParseBytes(maxSize); err != nil {
+					return err
+				}
+			}
+
+			mgr := _manager.New(getConfig())
+
+			result, err := mgr.PruneCache(opts)
+			if result != nil {
+				for _, entry := range result.Removed {
+					if dryRun {
+						_logger.Info("Would remove %s (%s, %s old)", entry.Filename, _util.FormatBytes(entry.Size), formatAge(entry.Age()))
+					} else {
+						_logger.Info("Removed %s (%s, %s old)", entry.Filename, _util.FormatBytes(entry.Size), formatAge(entry.Age()))
+					}
+				}
+			}
+			if err != nil {
+				_logger.ErrorWithHelp("Cache prune failed", "Verify that ~/.govman/cache is writable.")
+				return err
+			}
+
+			reportFreed(result.Freed, dryRun)
+			_logger.Info("Cache size: %s", _util.FormatBytes(result.Remaining))
+			if opts.MaxSize > 0 && result.Remaining > opts.MaxSize {
+				_logger.Warning("The cache is still larger than %s because the remaining archives are kept", _util.FormatBytes(opts.MaxSize))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Remove archives older than this age (e.g. 30d, 2w, 12h)")
+	cmd.Flags().StringVar(&maxSize, "max-size", "", "Remove the oldest archives until the cache is at most this size (e.g. 2GB)")
+	cmd.Flags().BoolVar(&keepInstalled, "keep-installed", false, "Never remove archives of installed versions")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be removed without deleting anything")
+
+	return cmd
+}
+
+// reportFreed prints the number of bytes freed, or that would be freed in a dry run.
+func reportFreed(freed int64, dryRun bool) {
+	if dryRun {
+		_logger.Success("Dry run: %s would be freed", _util.FormatBytes(freed))
+		return
+	}
+	_logger.Success("Freed %s", _util.FormatBytes(freed))
+}
+
+// formatAge formats an archive age in the largest whole unit (minutes, hours, or days).
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
@@ -16,7 +16,7 @@ func newCleanCmd() *cobra.Command {
 		Long: `Remove cached download files and temporary data to reclaim disk space.
 
 What gets cleaned:
-  • Downloaded Go archive files (.tar.gz, .zip)
+  • Downloaded Go archive files (.tar.gz, .tar.xz, .tar.zst, .tar, .zip)
   • Temporary extraction directories
   • Incomplete or corrupted downloads
   • Obsolete cache metadata and checksums
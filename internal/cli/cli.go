@@ -9,11 +9,14 @@ import (
 	cobra "github.com/spf13/cobra"
 
 	_config "github.com/sijunda/govman/internal/config"
+	_golang "github.com/sijunda/govman/internal/golang"
+	_httpclient "github.com/sijunda/govman/internal/httpclient"
 	_version "github.com/sijunda/govman/internal/version"
 )
 
 var (
 	cfgFile  string
+	offline  bool
 	cfg      *_config.Config
 	cfgMutex sync.Mutex
 	cfgOnce  sync.Once
@@ -98,7 +101,15 @@ func initConfig() error {
 		cfg, err = _config.Load(cfgFile)
 		if err != nil {
 			initErr = fmt.Errorf("failed to load config: %w", err)
+			return
 		}
+
+		// Network settings are applied on the first request; invalid ones only fail commands that use the network.
+		// go_releases.auth is registered with the release source, for the locations that source actually uses.
+		_httpclient.Configure(cfg.Network)
+
+		_golang.SetIndexDir(cfg.CacheDir)
+		_golang.SetOffline(cfg.Offline || offline)
 	})
 	return initErr
 }
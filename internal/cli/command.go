@@ -14,6 +14,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.govman/config.yaml)")
 	rootCmd.PersistentFlags().Bool("verbose", false, "verbose output")
 	rootCmd.PersistentFlags().Bool("quiet", false, "quiet output (errors only)")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "work only from the cached release index and archives (no network access)")
 
 	if err := viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose")); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: failed to bind verbose flag: %v\n", err)
@@ -34,14 +35,24 @@ func init() {
 func addCommands() {
 	rootCmd.AddCommand(
 		newInitCmd(),
+		newDeinitCmd(),
 		newInstallCmd(),
+		newDownloadCmd(),
+		newBundleCmd(),
+		newMirrorCmd(),
 		newUninstallCmd(),
 		newUseCmd(),
+		newHistoryCmd(),
+		newEnvCmd(),
+		newShellCmd(),
 		newCurrentCmd(),
 		newListCmd(),
 		newInfoCmd(),
 		newCleanCmd(),
+		newCacheCmd(),
 		newSelfUpdateCmd(),
 		newRefreshCmd(),
+		newCompletionCmd(),
+		newHookEnvCmd(),
 	)
 }
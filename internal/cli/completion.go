@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cobra "github.com/spf13/cobra"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+	_manager "github.com/sijunda/govman/internal/manager"
+	_shell "github.com/sijunda/govman/internal/shell"
+)
+
+// newCompletionCmd creates the 'completion' Cobra command to print a shell completion script.
+// Returns a *cobra.Command whose RunE writes the script for the given shell to stdout.
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion <bash|zsh|fish|powershell>",
+		Short: "Generate shell completion scripts",
+		Long: `Print a completion script for the given shell.
+
+'govman init' installs the script automatically when shell.completion is
+enabled in the config. Versions complete dynamically: installed versions for
+'use', 'uninstall', 'info' and 'env', and versions from the cached release
+index for 'install' (no network access is made while completing).
+
+Examples:
+  source <(govman completion bash)
+  govman completion zsh > "${fpath[1]}/_govman"
+  govman completion fish > ~/.config/fish/completions/govman.fish
+  govman completion powershell | Out-String | Invoke-Expression`,
+		Args:                  cobra.ExactArgs(1),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writeCompletion(cmd.Root(), os.Stdout, args[0])
+		},
+	}
+
+	return cmd
+}
+
+// writeCompletion writes the completion script for shellName to w.
+// Returns an error for unsupported shells or if writing fails.
+func writeCompletion(root *cobra.Command, w io.Writer, shellName string) error {
+	switch shellName {
+	case "bash":
+		return root.GenBashCompletionV2(w, true)
+	case "zsh":
+		return root.GenZshCompletion(w)
+	case "fish":
+		return root.GenFishCompletion(w, true)
+	case "powershell", "pwsh":
+		return root.GenPowerShellCompletionWithDesc(w)
+	default:
+		return fmt.Errorf("unsupported shell for completion: %s (supported: bash, zsh, fish, powershell)", shellName)
+	}
+}
+
+// installCompletion writes the completion script for sh where its shell integration sources it.
+// Returns the file written, or an error if the shell has no completion support or writing fails.
+func installCompletion(root *cobra.Command, sh _shell.Shell, binPath string) (string, error) {
+	path := _shell.CompletionFile(sh, binPath)
+	if path == "" {
+		return "", fmt.Errorf("%s does not support completions", sh.DisplayName())
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create completions directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create completion script: %w", err)
+	}
+	defer file.Close()
+
+	if err := writeCompletion(root, file, sh.Name()); err != nil {
+		return "", fmt.Errorf("failed to write completion script: %w", err)
+	}
+
+	return path, nil
+}
+
+// completeInstalledVersions completes the first argument with installed Go versions.
+func completeInstalledVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 || getConfig() == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	versions, err := _manager.New(getConfig()).ListInstalled()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	return filterCompletions(versions, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeUseVersions completes installed Go versions and the "default" alias.
+func completeUseVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	versions, directive := completeInstalledVersions(cmd, args, toComplete)
+	if len(args) == 0 && directive != cobra.ShellCompDirectiveError {
+		versions = append(versions, filterCompletions([]string{"default"}, toComplete)...)
+	}
+	return versions, directive
+}
+
+// completeRemoteVersions completes versions from the cached release index without network access.
+func completeRemoteVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if getConfig() == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Completion runs on every <TAB>, so never wait for the network
+	_golang.SetOffline(true)
+
+	versions, err := _manager.New(getConfig()).ListRemote(true)
+	if err != nil {
+		versions = nil
+	}
+
+	return filterCompletions(append([]string{"latest"}, versions...), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// filterCompletions returns the candidates starting with prefix.
+func filterCompletions(candidates []string, prefix string) []string {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
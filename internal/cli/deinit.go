@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	cobra "github.com/spf13/cobra"
+
+	_logger "github.com/sijunda/govman/internal/logger"
+	_shell "github.com/sijunda/govman/internal/shell"
+)
+
+// newDeinitCmd creates the 'deinit' Cobra command to remove shell integration.
+// Flags: shellName (target shell), all (every supported shell) and dryRun (print a diff instead of writing).
+// Returns a *cobra.Command whose RunE removes the govman block from each known configuration file.
+func newDeinitCmd() *cobra.Command {
+	var (
+		shellName string
+		all       bool
+		dryRun    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "deinit",
+		Short: "Remove govman shell integration",
+		Long: `Remove the shell integration added by 'govman init'.
+
+The GOVMAN block is removed from every configuration file govman may have
+written for the shell (for example .bashrc, .bash_profile and .profile for
+Bash), the Command Prompt wrapper govman.bat is deleted, and the installed
+completion script is removed. Each changed file is first saved next to the
+original as <file>.govman-backup-<timestamp>.
+
+Examples:
+  govman deinit                 # Detected shell
+  govman deinit --shell zsh     # A specific shell
+  govman deinit --all           # Every supported shell
+  govman deinit --all --dry-run # Show what would change`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all && shellName != "" {
+				return fmt.Errorf("--shell and --all cannot be used together")
+			}
+
+			loadShellTemplates()
+
+			var shells []_shell.Shell
+			switch {
+			case all:
+				shells = _shell.All()
+			case shellName != "":
+				sh, err := _shell.ForName(shellName)
+				if err != nil {
+					return err
+				}
+				shells = []_shell.Shell{sh}
+			default:
+				sh := _shell.Detect()
+				_logger.Info("Auto-detected shell: %s", sh.Name())
+				shells = []_shell.Shell{sh}
+			}
+
+			binPath := getConfig().GetBinPath()
+			opts := _shell.Options{DryRun: dryRun}
+
+			var changes []_shell.FileChange
+			for _, sh := range shells {
+				_logger.Verbose("Removing shell integration for %s", sh.DisplayName())
+				changed, err := _shell.DeinitializeShell(sh, binPath, opts)
+				if err != nil {
+					return fmt.Errorf("failed to remove %s integration: %w", sh.DisplayName(), err)
+				}
+				changes = append(changes, changed...)
+
+				if completionFile := _shell.CompletionFile(sh, binPath); completionFile != "" && !dryRun {
+					if err := os.Remove(completionFile); err == nil {
+						_logger.Verbose("Removed completion script %s", completionFile)
+					} else if !os.IsNotExist(err) {
+						_logger.Warning("Failed to remove completion script %s: %v", completionFile, err)
+					}
+				}
+			}
+
+			printFileChanges(changes, dryRun)
+			switch {
+			case len(changes) == 0:
+				_logger.Info("No govman shell integration found")
+			case dryRun:
+				_logger.Info("Dry run: no files were changed")
+			default:
+				_logger.Success("Removed govman shell integration from %d file(s)", len(changes))
+				_logger.Info("Restart your terminal for the changes to take effect")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&shellName, "shell", "", "Target specific shell (bash, zsh, fish, powershell, cmd, nu, xonsh or a template name)")
+	cmd.Flags().BoolVar(&all, "all", false, "Remove the integration for every supported shell")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes as a unified diff without writing any files")
+
+	return cmd
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	cobra "github.com/spf13/cobra"
+
+	_downloader "github.com/sijunda/govman/internal/downloader"
+	_logger "github.com/sijunda/govman/internal/logger"
+	_manager "github.com/sijunda/govman/internal/manager"
+)
+
+// newDownloadCmd creates the 'download' Cobra command to prefetch Go archives into the cache without installing them.
+// Flags: --os and --arch select the target platform (default: current); --limit-rate caps the bandwidth. Returns a *cobra.Command.
+func newDownloadCmd() *cobra.Command {
+	var (
+		goos      string
+		goarch    string
+		limitRate string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "download [version...]",
+		Short: "Prefetch Go archives into the cache without installing",
+		Long: `Download and verify Go archives into the cache directory without extracting them.
+
+Prefetched archives are reused by 'govman install', including in offline mode,
+so caches can be warmed on golden images or before travelling.
+
+Features:
+  • Checksum verification of every archive
+  • Resumes partial downloads and skips archives already cached
+  • Prefetch for other platforms with --os and --arch
+  • Refreshes the persisted release index used by --offline
+
+Examples:
+  govman download latest                         # Latest stable release
+  govman download 1.25.1 1.24.7                  # Multiple versions
+  govman download 1.25.1 --os windows --arch amd64
+  govman download 1.25.1 1.24.7 --limit-rate 5MB/s`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyRateLimit(cmd, limitRate); err != nil {
+				return err
+			}
+
+			mgr := _manager.New(getConfig())
+
+			_logger.Info("Prefetching %d Go version(s) for %s/%s...", len(args), goos, goarch)
+
+			var errors []string
+			var successful []string
+			for i, version := range args {
+				_logger.Info("[%d/%d] Downloading Go %s...", i+1, len(args), version)
+				resolved, archivePath, err := mgr.Download(version, goos, goarch)
+				if err != nil {
+					errors = append(errors, fmt.Sprintf("Go %s: %v", version, err))
+					_logger.Warning("Failed to download Go %s: %v", version, err)
+					continue
+				}
+
+				successful = append(successful, fmt.Sprintf("Go %s (%s)", resolved, filepath.Base(archivePath)))
+			}
+
+			_logger.Info(strings.Repeat("─", 50))
+
+			if len(successful) > 0 {
+				_logger.Success("Cached %d archive(s) in %s:", len(successful), getConfig().CacheDir)
+				for _, entry := range successful {
+					_logger.Info("  • %s", entry)
+				}
+			}
+
+			if len(errors) > 0 {
+				_logger.ErrorWithHelp("Failed to download %d version(s):", "Verify the versions with 'govman list --remote' and that they are published for the requested platform.", len(errors))
+				for _, err := range errors {
+					_logger.Info("  %s", err)
+				}
+				return fmt.Errorf("failed to download %d version(s)", len(errors))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&goos, "os", runtime.GOOS, "Target operating system of the archives (e.g. linux, darwin, windows)")
+	cmd.Flags().StringVar(&goarch, "arch", runtime.GOARCH, "Target architecture of the archives (e.g. amd64, arm64)")
+	cmd.Flags().StringVar(&limitRate, "limit-rate", "", "Limit download bandwidth (e.g. 5MB/s, 500KB/s); overrides download.rate_limit")
+
+	return cmd
+}
+
+// applyRateLimit validates the --limit-rate flag and, when it was given, makes it override download.rate_limit
+// for this run. Returns an error if the value is not a valid rate.
+func applyRateLimit(cmd *cobra.Command, limitRate string) error {
+	if !cmd.Flags().Changed("limit-rate") {
+		return nil
+	}
+
+	if _, err := _downloader.ParseRateLimit(limitRate); err != nil {
+		return err
+	}
+	getConfig().Download.RateLimit = limitRate
+	return nil
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cobra "github.com/spf13/cobra"
+
+	_logger "github.com/sijunda/govman/internal/logger"
+	_manager "github.com/sijunda/govman/internal/manager"
+	_shell "github.com/sijunda/govman/internal/shell"
+)
+
+// newEnvCmd creates the 'env' Cobra command to print the environment for a Go version.
+// Flag: shellName selects the output syntax or JSON. Returns a *cobra.Command whose RunE resolves the version
+// (argument, project file, or default) and prints the PATH, GOROOT, GOTOOLCHAIN and GOVMAN_VERSION changes.
+func newEnvCmd() *cobra.Command {
+	var shellName string
+
+	cmd := &cobra.Command{
+		Use:   "env [version]",
+		Short: "Print the environment that activates a Go version",
+		Long: `Print the environment changes that activate a Go version in the current shell.
+
+The output sets PATH (with the version's bin directory first and any other
+govman-managed version removed), GOROOT, GOTOOLCHAIN and GOVMAN_VERSION, quoted
+for the selected shell, plus GOVMAN_ACTIVATION with how it was activated and
+GOVMAN_PREVIOUS with the version being replaced.
+The shell wrapper installed by 'govman init' evaluates it after 'govman use'.
+
+Version selection:
+  • The version argument, 'default' for the system default, or '-' for the
+    version active before the last switch (GOVMAN_PREVIOUS)
+  • Without an argument: the project version file, else the system default
+
+Examples:
+  eval "$(govman env 1.25.1 --shell bash)"
+  govman env --shell fish | source
+  govman env 1.25.1 --shell pwsh | Out-String | Invoke-Expression
+  govman env default --shell json`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeUseVersions,
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr := _manager.New(getConfig())
+
+			version := "default"
+			if len(args) == 1 {
+				version = args[0]
+			} else if dir, err := os.Getwd(); err == nil {
+				if projectVersion, file := mgr.ProjectVersion(dir); projectVersion != "" {
+					_logger.Verbose("Using Go %s from %s", projectVersion, file)
+					version = projectVersion
+				}
+			}
+
+			if version == "-" {
+				previous, err := mgr.PreviousVersion(os.Getenv)
+				if err != nil {
+					return err
+				}
+				version = previous
+			}
+
+			if version == "default" {
+				defaultVersion, err := mgr.CurrentGlobal()
+				if err != nil {
+					return fmt.Errorf("failed to get default version: %w", err)
+				}
+				version = defaultVersion
+			}
+
+			changes, err := mgr.Env(version, os.Getenv)
+			if err != nil {
+				return err
+			}
+
+			if strings.EqualFold(shellName, "json") {
+				output, err := _shell.EnvJSON(changes)
+				if err != nil {
+					return err
+				}
+				fmt.Println(output)
+				return nil
+			}
+
+			sh := _shell.Detect()
+			if shellName != "" {
+				if sh, err = _shell.ForName(shellName); err != nil {
+					return err
+				}
+			}
+
+			commands, err := _shell.EnvCommands(sh, changes)
+			if err != nil {
+				return err
+			}
+			fmt.Println(strings.Join(commands, "\n"))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&shellName, "shell", "", "output syntax: bash, zsh, fish, pwsh, cmd, nu, xonsh or json (default: detected shell)")
+
+	return cmd
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	cobra "github.com/spf13/cobra"
+
+	_logger "github.com/sijunda/govman/internal/logger"
+	_manager "github.com/sijunda/govman/internal/manager"
+)
+
+// newHistoryCmd creates the 'history' Cobra command to show recent version switches.
+// Flag: limit sets how many entries to show. Returns a *cobra.Command whose RunE prints the
+// 'use' and auto-switch events from the history file, oldest first.
+func newHistoryCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show recent Go version switches",
+		Long: `Show recent 'govman use' and automatic switching events with timestamps.
+
+Each entry shows when the switch happened, what caused it, the version that
+was activated and the version it replaced. Auto-switch entries also show the
+project directory.
+
+Examples:
+  govman history          # Last 20 switches
+  govman history -n 50    # Last 50 switches
+  govman history -n 0     # Everything recorded`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr := _manager.New(getConfig())
+
+			entries, err := mgr.History(limit)
+			if err != nil {
+				return err
+			}
+
+			if len(entries) == 0 {
+				_logger.Info("No version switches recorded yet")
+				return nil
+			}
+
+			_logger.Info("Recent Go version switches:")
+			_logger.Info(strings.Repeat("─", 60))
+			for _, entry := range entries {
+				line := fmt.Sprintf("%s  %-13s %-10s", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Event, entry.Version)
+				if entry.Previous != "" {
+					line += fmt.Sprintf(" (from %s)", entry.Previous)
+				}
+				if entry.Dir != "" {
+					line += "  " + entry.Dir
+				}
+				_logger.Info("%s", strings.TrimRight(line, " "))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "Number of entries to show (0 for all)")
+
+	return cmd
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cobra "github.com/spf13/cobra"
+
+	_manager "github.com/sijunda/govman/internal/manager"
+	_shell "github.com/sijunda/govman/internal/shell"
+)
+
+// newHookEnvCmd creates the hidden 'hook-env' Cobra command evaluated by shell prompt hooks.
+// Flag: shellName selects the output syntax. Returns a *cobra.Command whose RunE prints the environment
+// changes needed to activate the project version for the current directory.
+func newHookEnvCmd() *cobra.Command {
+	var shellName string
+
+	cmd := &cobra.Command{
+		Use:   "hook-env",
+		Short: "Print environment changes for automatic version switching",
+		Long: `Resolve the Go version for the current directory and print the environment
+changes needed to activate it, in the syntax of the given shell.
+
+This command is run by the shell integration installed with 'govman init' on
+every prompt. It prints nothing when the directory and its project version
+file have not changed since the last prompt.
+
+Examples:
+  eval "$(govman hook-env --shell bash)"
+  govman hook-env --shell fish | source
+  govman hook-env --shell powershell | Out-String | Invoke-Expression`,
+		Hidden:       true,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sh := _shell.Detect()
+			if shellName != "" {
+				var err error
+				if sh, err = _shell.ForName(shellName); err != nil {
+					return err
+				}
+			}
+
+			dir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+
+			mgr := _manager.New(getConfig())
+			changes, err := mgr.HookEnv(dir, os.Getenv)
+			if err != nil {
+				return err
+			}
+
+			commands, err := _shell.EnvCommands(sh, changes)
+			if err != nil {
+				return err
+			}
+			if len(commands) > 0 {
+				fmt.Println(strings.Join(commands, "\n"))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&shellName, "shell", "", "shell syntax to print: bash, zsh, fish, powershell, nu or xonsh (default: detected)")
+
+	return cmd
+}
@@ -29,7 +29,8 @@ Information includes:
   • Release notes and changelog links (when available)
 
 Perfect for debugging installation issues and verifying setups.`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstalledVersions,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			version := args[0]
 			mgr := _manager.New(getConfig())
@@ -11,11 +11,14 @@ import (
 )
 
 // newInitCmd creates the 'init' Cobra command to set up shell integration.
-// Flags: force (overwrite existing configuration) and shellName (target shell).
-// Returns a *cobra.Command whose RunE detects or uses the specified shell and initializes integration.
+// Flags: force (overwrite existing configuration), dryRun (print a diff instead of writing), all (every available
+// shell, including login-shell files) and shellName (target shell).
+// Returns a *cobra.Command whose RunE detects or uses the specified shells and initializes integration.
 func newInitCmd() *cobra.Command {
 	var (
 		force     bool
+		dryRun    bool
+		all       bool
 		shellName string
 	)
 
@@ -27,8 +30,9 @@ func newInitCmd() *cobra.Command {
 Integration Features:
   • Automatic Go version switching based on .govman-version files
   • Smart PATH management and environment variable handling
-  • Support for bash, zsh, fish, and PowerShell
-  • Non-intrusive configuration with easy removal
+  • Support for bash, zsh, fish, PowerShell, Nushell and xonsh
+  • Non-intrusive configuration with easy removal ('govman deinit')
+  • Timestamped backup of every file changed
   • Project-aware version detection
   • Seamless integration with existing shell setups
 
@@ -37,41 +41,104 @@ Supported Shells:
   • Zsh (.zshrc)
   • Fish (config.fish)
   • PowerShell (profile)
+  • Nushell (config.nu)
+  • Xonsh (.xonshrc, rc.xsh)
+  • Any shell described by a template in ~/.govman/shells/<name>.tmpl
+
+With --all, every shell found on the system is configured, and the govman
+PATH is also added to login-shell files (.bash_profile or .profile, and
+.zprofile) so that non-interactive sessions such as 'ssh host go build' and
+new tmux panes find Go.
 
 After initialization, govman will automatically activate the correct
 Go version when you navigate to different projects.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var sh _shell.Shell
+			if all && shellName != "" {
+				return fmt.Errorf("--shell and --all cannot be used together")
+			}
+
+			loadShellTemplates()
 
-			if shellName != "" {
-				sh = getShellByName(shellName)
+			var shells []_shell.Shell
+			switch {
+			case all:
+				shells = _shell.DetectAll()
+				if len(shells) == 0 {
+					_logger.ErrorWithHelp("No supported shells found", "Use --shell to configure a specific shell.", "")
+					return fmt.Errorf("no supported shells found")
+				}
+				names := make([]string, len(shells))
+				for i, sh := range shells {
+					names[i] = sh.Name()
+				}
+				_logger.Info("Detected shells: %s", strings.Join(names, ", "))
+			case shellName != "":
+				sh := getShellByName(shellName)
 				if sh == nil {
-					_logger.ErrorWithHelp("Unsupported shell: %s", "Supported shells: bash, zsh, fish, powershell. Use --shell flag to specify.", shellName)
+					_logger.ErrorWithHelp("Unsupported shell: %s", "Supported shells: bash, zsh, fish, powershell, nu, xonsh, or a template in ~/.govman/shells/<name>.tmpl. Use --shell flag to specify.", shellName)
 					return fmt.Errorf("unsupported shell: %s", shellName)
 				}
 				_logger.Info("Using manually specified shell: %s", sh.Name())
-			} else {
-				sh = _shell.Detect()
+				shells = []_shell.Shell{sh}
+			default:
+				sh := _shell.Detect()
 				_logger.Info("Auto-detected shell: %s", sh.Name())
+				shells = []_shell.Shell{sh}
 			}
 
 			cfg := getConfig()
 			binPath := cfg.GetBinPath()
+			opts := _shell.Options{Force: force, DryRun: dryRun, LoginShells: all}
+
+			var changes []_shell.FileChange
+			var configured []_shell.Shell
+			for _, sh := range shells {
+				_logger.Info("Initializing shell integration for %s...", sh.Name())
+				_logger.Progress("Configuring PATH and environment variables")
+
+				_logger.Verbose("Setting up shell integration with binary path: %s", binPath)
+				shellChanges, err := _shell.InitializeShell(sh, binPath, opts)
+				changes = append(changes, shellChanges...)
+				if err != nil {
+					if all {
+						_logger.Warning("Skipping %s: %v", sh.DisplayName(), err)
+						continue
+					}
+					_logger.ErrorWithHelp("Failed to configure shell integration", "Ensure you have write permissions to your shell configuration file and try again.", "")
+					return err
+				}
+				configured = append(configured, sh)
+
+				if !dryRun && cfg.Shell.Completion && _shell.CompletionFile(sh, binPath) != "" {
+					_logger.Progress("Installing shell completions")
+					if path, err := installCompletion(cmd.Root(), sh, binPath); err != nil {
+						_logger.Warning("Failed to install shell completions: %v", err)
+					} else {
+						_logger.Verbose("Completion script written to %s", path)
+					}
+				}
+			}
 
-			_logger.Info("Initializing shell integration for %s...", sh.Name())
-			_logger.Progress("Configuring PATH and environment variables")
-
-			_logger.Verbose("Setting up shell integration with binary path: %s", binPath)
-			if err := _shell.InitializeShell(sh, binPath, force); err != nil {
-				_logger.ErrorWithHelp("Failed to configure shell integration", "Ensure you have write permissions to your shell configuration file and try again.", "")
-				return err
+			if all || dryRun {
+				printFileChanges(changes, dryRun)
+			}
+			if dryRun {
+				_logger.Info("Dry run: no files were changed")
+				return nil
+			}
+			if len(configured) == 0 {
+				return fmt.Errorf("no shell could be configured")
 			}
 
 			_logger.Success("Shell integration configured successfully!")
-			_logger.Info("Configuration file: %s", sh.ConfigFile())
+			reload := "Restart your terminal"
+			if len(configured) == 1 {
+				_logger.Info("Configuration file: %s", configured[0].ConfigFile())
+				reload = fmt.Sprintf("Restart your terminal or run: source %s", configured[0].ConfigFile())
+			}
 			_logger.Info(strings.Repeat("─", 50))
 			_logger.Info("Next Steps:")
-			_logger.Info("  1. Restart your terminal or run: source %s", sh.ConfigFile())
+			_logger.Info("  1. %s", reload)
 			_logger.Info("  2. Navigate to a project directory")
 			_logger.Info("  3. Create a .govman-version file with your desired Go version")
 			_logger.Info("  4. govman will automatically switch versions for you!")
@@ -83,14 +150,50 @@ Go version when you navigate to different projects.`,
 	}
 
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force re-initialization (overwrite existing configuration)")
-	cmd.Flags().StringVar(&shellName, "shell", "", "Target specific shell (bash, zsh, fish, powershell)")
+	cmd.Flags().BoolVar(&all, "all", false, "Configure every available shell, including login-shell files")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes as a unified diff without writing any files")
+	cmd.Flags().StringVar(&shellName, "shell", "", "Target specific shell (bash, zsh, fish, powershell, nu, xonsh or a template name)")
 
 	return cmd
 }
 
-// getShellByName maps a shell name to its Shell implementation.
-// Supported values: bash, zsh, fish, powershell/pwsh. Returns nil if unsupported.
+// printFileChanges prints one line per file changed by init or deinit, with its backup if one was made.
+func printFileChanges(changes []_shell.FileChange, dryRun bool) {
+	if len(changes) == 0 {
+		return
+	}
+
+	_logger.Info("Files:")
+	for _, change := range changes {
+		action := change.Action
+		if dryRun && action != "unchanged" {
+			action = "would be " + action
+		}
+
+		if change.Backup != "" {
+			_logger.Info("  %-18s %s (backup: %s)", action, change.Path, change.Backup)
+		} else {
+			_logger.Info("  %-18s %s", action, change.Path)
+		}
+	}
+}
+
+// loadShellTemplates registers the user-defined shell templates next to the govman bin directory.
+// Invalid templates are reported as a warning and skipped.
+func loadShellTemplates() {
+	dir := _shell.TemplateDir(getConfig().GetBinPath())
+	if err := _shell.RegisterTemplates(dir); err != nil {
+		_logger.Warning("Ignoring invalid shell templates: %v", err)
+	}
+}
+
+// getShellByName maps a shell name to its Shell implementation, preferring a user-defined template.
+// Supported values: bash, zsh, fish, powershell/pwsh, nu/nushell, xonsh and registered templates. Returns nil if unsupported.
 func getShellByName(name string) _shell.Shell {
+	if sh := _shell.LookupTemplate(name); sh != nil {
+		return sh
+	}
+
 	switch name {
 	case "bash":
 		return &_shell.BashShell{}
@@ -100,6 +203,10 @@ func getShellByName(name string) _shell.Shell {
 		return &_shell.FishShell{}
 	case "powershell", "pwsh":
 		return &_shell.PowerShell{}
+	case "nu", "nushell":
+		return &_shell.NuShell{}
+	case "xonsh":
+		return &_shell.XonshShell{}
 	default:
 		return nil
 	}
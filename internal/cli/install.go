@@ -6,14 +6,17 @@ import (
 
 	cobra "github.com/spf13/cobra"
 
+	_golang "github.com/sijunda/govman/internal/golang"
 	_logger "github.com/sijunda/govman/internal/logger"
 	_manager "github.com/sijunda/govman/internal/manager"
 	_util "github.com/sijunda/govman/internal/util"
 )
 
 // newInstallCmd creates the 'install' Cobra command to download and install one or more Go versions.
-// Versions are provided as positional args (e.g., latest, 1.25.1). Returns a *cobra.Command that installs each version and reports results.
+// Versions are provided as positional args (e.g., latest, 1.25.1). Flag: --limit-rate caps the download bandwidth. Returns a *cobra.Command that installs each version and reports results.
 func newInstallCmd() *cobra.Command {
+	var limitRate string
+
 	cmd := &cobra.Command{
 		Use:   "install [version...]",
 		Short: "Install Go versions with intelligent download management",
@@ -31,9 +34,15 @@ Examples:
   govman install latest              # Latest stable release
   govman install 1.25.1              # Specific version
   govman install 1.25.1 1.20.12      # Multiple versions
-  govman install 1.22rc1             # Pre-release version`,
-		Args: cobra.MinimumNArgs(1),
+  govman install 1.22rc1             # Pre-release version
+  govman install 1.25.1 --limit-rate 5MB/s  # Cap download bandwidth`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRemoteVersions,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyRateLimit(cmd, limitRate); err != nil {
+				return err
+			}
+
 			mgr := _manager.New(getConfig())
 
 			_logger.Info("Starting installation of %d Go version(s)...", len(args))
@@ -68,6 +77,9 @@ Examples:
 					_logger.Info("  %s", err)
 				}
 				_logger.Info("Common solutions:")
+				if _golang.IsOffline() {
+					_logger.Info("  • Prefetch archives with 'govman download <version>' while online, or drop --offline")
+				}
 				_logger.Info("  • Check your internet connection")
 				_logger.Info("  • Verify version exists with 'govman list --remote'")
 				_logger.Info("  • Try again with verbose mode: govman install <version> --verbose")
@@ -88,6 +100,8 @@ Examples:
 		},
 	}
 
+	cmd.Flags().StringVar(&limitRate, "limit-rate", "", "Limit download bandwidth (e.g. 5MB/s, 500KB/s); overrides download.rate_limit")
+
 	return cmd
 }
 
@@ -107,14 +121,14 @@ Safety features:
   • Preserves other installed versions safely
 
 The uninstalled version will no longer appear in 'govman list'.`,
-		Aliases: []string{"remove", "rm"},
-		Args:    cobra.ExactArgs(1),
+		Aliases:           []string{"remove", "rm"},
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstalledVersions,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			version := args[0]
 			mgr := _manager.New(getConfig())
 
-			current, _ := mgr.Current()
-			if current == version {
+			if mgr.IsActive(version) {
 				_logger.ErrorWithHelp("Cannot uninstall currently active Go version %s", "Switch to a different version first with 'govman use <other-version>', then try uninstalling again.", version)
 				return fmt.Errorf("cannot uninstall active version")
 			}
@@ -7,6 +7,7 @@ import (
 
 	cobra "github.com/spf13/cobra"
 
+	_golang "github.com/sijunda/govman/internal/golang"
 	_logger "github.com/sijunda/govman/internal/logger"
 	_manager "github.com/sijunda/govman/internal/manager"
 	_util "github.com/sijunda/govman/internal/util"
@@ -134,6 +135,10 @@ func listRemoteVersions(mgr *_manager.Manager, includeUnstable bool, pattern str
 	_logger.Verbose("Fetching available versions from Go's official release API")
 	versions, err := mgr.ListRemote(includeUnstable)
 	if err != nil {
+		if _golang.IsOffline() {
+			_logger.ErrorWithHelp("No cached release index is available in offline mode", "Run 'govman list --remote' once while online to prefetch the release index.", "")
+			return fmt.Errorf("failed to list remote versions: %w", err)
+		}
 		_logger.ErrorWithHelp("Unable to fetch remote Go versions", "Check your internet connection and verify that golang.org is accessible.", "")
 		return fmt.Errorf("failed to list remote versions: %w", err)
 	}
@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	cobra "github.com/spf13/cobra"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+	_logger "github.com/sijunda/govman/internal/logger"
+	_manager "github.com/sijunda/govman/internal/manager"
+	_mirror "github.com/sijunda/govman/internal/mirror"
+)
+
+// newMirrorCmd creates the 'mirror' Cobra command grouping the LAN mirror subcommands.
+// Returns a *cobra.Command with 'serve', 'sync', and 'keygen' registered.
+func newMirrorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Share Go releases with other machines on your network",
+		Long: `Maintain and run a go.dev/dl compatible mirror so other govman clients can
+install Go releases from one team machine.`,
+	}
+
+	cmd.AddCommand(newMirrorServeCmd(), newMirrorSyncCmd(), newMirrorKeygenCmd())
+
+	return cmd
+}
+
+// newMirrorServeCmd creates the 'mirror serve' Cobra command.
+// Flags: --addr, --dir (required), and --access-log. Returns a *cobra.Command that serves until interrupted.
+func newMirrorServeCmd() *cobra.Command {
+	var (
+		addr      string
+		dir       string
+		accessLog string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a directory of Go archives over HTTP",
+		Long: `Serve a directory of Go archives with the same layout as go.dev/dl.
+
+Archives are available under /dl/<filename> with Range support, so interrupted
+downloads resume. The release index is generated from the archives, including
+sha256 and size, at /dl/?mode=json&include=all. In a directory maintained by
+'govman mirror sync', only archives matching the upstream checksums in its
+index.json are listed, so partial downloads are never published.
+
+A signed mirror (see 'govman mirror sync --sign-key') serves that index.json
+unchanged at /dl/?mode=json&include=all and its index.json.sig at
+/dl/.sig?mode=json&include=all. The server never signs anything itself, so the
+signing key can stay on the machine that runs the sync.
+
+Point other clients at the mirror with:
+  go_releases:
+    api_url: http://<host>:8080/dl/?mode=json&include=all
+    download_url: http://<host>:8080/dl/%s
+
+Examples:
+  govman mirror serve --dir /srv/go-mirror         # Serve a synced mirror on :8080
+  govman mirror serve --dir /srv/go-mirror --addr :9000
+  govman mirror serve --dir /srv/go-mirror --access-log /var/log/govman-mirror.log`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dir == "" {
+				return fmt.Errorf("--dir is required (e.g. a directory maintained by 'govman mirror sync')")
+			}
+			if stat, err := os.Stat(dir); err != nil || !stat.IsDir() {
+				return fmt.Errorf("mirror directory %s does not exist", dir)
+			}
+
+			var logWriter io.Writer = os.Stdout
+			if accessLog != "" && accessLog != "-" {
+				file, err := os.OpenFile(accessLog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+				if err != nil {
+					return fmt.Errorf("failed to open access log: %w", err)
+				}
+				defer file.Close()
+				logWriter = file
+			}
+
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", addr, err)
+			}
+
+			server := &http.Server{
+				Handler:           _mirror.NewServer(dir, logWriter),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			go func() {
+				<-ctx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				server.Shutdown(shutdownCtx)
+			}()
+
+			baseURL := "http://" + mirrorHost(listener.Addr())
+			_logger.Success("Serving %s on %s", dir, baseURL)
+			_logger.Info("Configure clients with:")
+			_logger.Info("  api_url: %s/dl/?mode=json&include=all", baseURL)
+			_logger.Info("  download_url: %s/dl/%%s", baseURL)
+			_logger.Info("Press Ctrl+C to stop")
+
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("mirror server failed: %w", err)
+			}
+
+			_logger.Info("Mirror stopped")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", ":8080", "Address to listen on")
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory containing the Go archives, usually maintained by 'govman mirror sync'")
+	cmd.Flags().StringVar(&accessLog, "access-log", "-", "File to append the access log to ('-' for stdout)")
+
+	return cmd
+}
+
+// newMirrorSyncCmd creates the 'mirror sync' Cobra command.
+// Flags: --dir, --versions, --platforms, --unstable, --prune, and --sign-key. Returns a *cobra.Command.
+func newMirrorSyncCmd() *cobra.Command {
+	var (
+		dir       string
+		versions  string
+		platforms []string
+		unstable  bool
+		prune     bool
+		signKey   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Keep a mirror directory in step with upstream releases",
+		Long: `Synchronise a directory with the configured release source (go_releases).
+
+Only new or changed archives are downloaded, each is verified against the
+upstream sha256, and archives that no longer match the constraint are removed.
+A static index.json in the go.dev schema is written next to the archives, so
+clients can use the directory directly:
+
+  go_releases:
+    api_url: file:///srv/go-mirror/index.json
+
+or over HTTP behind any static web server:
+
+  go_releases:
+    api_url: https://mirror.example.com/go/index.json
+    download_url: https://mirror.example.com/go/%s
+
+The directory can also be served with 'govman mirror serve --dir'.
+
+With --sign-key, an ed25519 signature is written to index.json.sig. Clients
+listing the public key in go_releases.trusted_keys verify it before trusting
+any checksum from the index.
+
+Examples:
+  govman mirror sync --dir /srv/go-mirror --versions '>=1.21' --platforms linux/amd64,darwin/arm64`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dir == "" {
+				return fmt.Errorf("--dir is required")
+			}
+			if versions == "" {
+				return fmt.Errorf("--versions is required (e.g. --versions '>=1.21')")
+			}
+
+			targets, err := _manager.ParsePlatforms(platforms)
+			if err != nil {
+				return err
+			}
+
+			opts := _mirror.SyncOptions{Prune: prune}
+			if signKey != "" {
+				if opts.SigningKey, err = _golang.LoadSigningKey(signKey); err != nil {
+					return err
+				}
+			}
+
+			mgr := _manager.New(getConfig())
+
+			_logger.Info("Syncing Go %s for %s into %s...", versions, strings.Join(platforms, ","), dir)
+			result, err := mgr.SyncMirror(dir, versions, targets, unstable, opts)
+			if result != nil {
+				_logger.Info("Downloaded: %d, unchanged: %d, removed: %d", len(result.Downloaded), len(result.Unchanged), len(result.Removed))
+				for _, name := range result.Removed {
+					_logger.Verbose("Removed %s", name)
+				}
+			}
+			if err != nil {
+				_logger.ErrorWithHelp("Mirror sync failed", "Check the version constraint with 'govman list --remote' and your network connection; rerunning resumes where it stopped.")
+				return err
+			}
+
+			_logger.Success("Mirror is up to date: %s", result.IndexPath)
+			if result.SignaturePath != "" {
+				_logger.Info("Index signed with key %s: %s", _golang.KeyID(opts.SigningKey.Public().(ed25519.PublicKey)), result.SignaturePath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "Mirror directory to keep in sync")
+	cmd.Flags().StringVar(&versions, "versions", "", "Version constraint selecting the releases (e.g. '>=1.21')")
+	cmd.Flags().StringSliceVar(&platforms, "platforms", []string{runtime.GOOS + "/" + runtime.GOARCH}, "Target platforms as os/arch, comma-separated")
+	cmd.Flags().BoolVar(&unstable, "unstable", false, "Include beta/rc releases matching the constraint")
+	cmd.Flags().BoolVar(&prune, "prune", true, "Remove archives that no longer match the constraint")
+	cmd.Flags().StringVar(&signKey, "sign-key", "", "ed25519 private key (PEM) used to sign index.json (see 'govman mirror keygen')")
+
+	return cmd
+}
+
+// newMirrorKeygenCmd creates the 'mirror keygen' Cobra command to create a release index signing key.
+// Flag: --out sets the private key path. Returns a *cobra.Command.
+func newMirrorKeygenCmd() *cobra.Command {
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Create an ed25519 key for signing mirror release indexes",
+		Long: `Generate an ed25519 key pair for 'govman mirror sync --sign-key'.
+
+The private key is written as a PKCS#8 PEM file readable only by you. The
+public key is printed in the form expected by go_releases.trusted_keys.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pub, err := _golang.GenerateSigningKey(out)
+			if err != nil {
+				return err
+			}
+
+			_logger.Success("Private key written to %s (key ID %s)", out, _golang.KeyID(pub))
+			_logger.Info("Add the public key to the clients' configuration:")
+			_logger.Info("  go_releases:")
+			_logger.Info("    trusted_keys:")
+			_logger.Info("      - %s", _golang.EncodePublicKey(pub))
+			_logger.Info("    require_signature: true")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&out, "out", "o", "govman-mirror.key", "Path of the private key file to create")
+
+	return cmd
+}
+
+// mirrorHost returns a host:port for addr that clients can use, replacing an unspecified IP with the hostname.
+func mirrorHost(addr net.Addr) string {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok || !tcpAddr.IP.IsUnspecified() {
+		return addr.String()
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, fmt.Sprint(tcpAddr.Port))
+}
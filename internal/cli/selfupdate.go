@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"runtime"
 	"strings"
@@ -12,6 +11,8 @@ import (
 
 	cobra "github.com/spf13/cobra"
 
+	_golang "github.com/sijunda/govman/internal/golang"
+	_httpclient "github.com/sijunda/govman/internal/httpclient"
 	_logger "github.com/sijunda/govman/internal/logger"
 	_version "github.com/sijunda/govman/internal/version"
 )
@@ -72,6 +73,11 @@ Examples:
 // Parameters: checkOnly (perform a dry run and do not install), force (reinstall even if already on latest),
 // prerelease (include pre-release versions when checking). Returns nil on success or an error if any step fails.
 func runSelfUpdate(checkOnly, force, prerelease bool) error {
+	if _golang.IsOffline() {
+		_logger.ErrorWithHelp("Self-update is not available in offline mode", "Run 'govman selfupdate' again without --offline once you are connected.", "")
+		return fmt.Errorf("selfupdate requires network access")
+	}
+
 	_logger.Info("Checking for govman updates...")
 	_logger.Progress("Contacting GitHub API for latest release information")
 
@@ -139,7 +145,7 @@ func runSelfUpdate(checkOnly, force, prerelease bool) error {
 	_logger.Download("Downloading %s...", latest.TagName)
 
 	_logger.Verbose("Downloading binary")
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := _httpclient.New(30 * time.Second)
 	resp, err := client.Get(downloadURL)
 	if err != nil {
 		_logger.ErrorWithHelp("Failed to download binary", "Check your internet connection and try again.", "")
@@ -208,7 +214,7 @@ func getLatestRelease(includePrerelease bool) (*GitHubRelease, error) {
 		url = cfg.SelfUpdate.GitHubReleasesURL
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := _httpclient.New(30 * time.Second)
 	resp, err := client.Get(url)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	cobra "github.com/spf13/cobra"
+
+	_logger "github.com/sijunda/govman/internal/logger"
+	_manager "github.com/sijunda/govman/internal/manager"
+	_shell "github.com/sijunda/govman/internal/shell"
+)
+
+// newShellCmd creates the 'shell' Cobra command to start a subshell pinned to a Go version.
+// Flag: shellName overrides the detected shell. Returns a *cobra.Command whose RunE starts the shell
+// with the version's environment and waits for it to exit.
+func newShellCmd() *cobra.Command {
+	var shellName string
+
+	cmd := &cobra.Command{
+		Use:   "shell <version>",
+		Short: "Start a subshell that uses a specific Go version",
+		Long: `Start a new interactive shell in which the given Go version is active.
+
+The subshell gets the version's PATH, GOROOT, GOTOOLCHAIN and GOVMAN_VERSION,
+and its prompt is prefixed with the version (for bash, zsh, fish, PowerShell
+and Command Prompt). GOVMAN_SHELL is set to the version so that automatic
+switching leaves the session alone and custom prompts can show it. Type
+'exit' to return to the previous shell, whose environment is unchanged.
+
+This works without the shell integration from 'govman init'.
+
+Examples:
+  govman shell 1.25.1
+  govman shell default
+  govman shell 1.24.0 --shell zsh`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeUseVersions,
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr := _manager.New(getConfig())
+
+			version := args[0]
+			if version == "default" {
+				defaultVersion, err := mgr.CurrentGlobal()
+				if err != nil {
+					return fmt.Errorf("failed to get default version: %w", err)
+				}
+				version = defaultVersion
+			}
+
+			if !mgr.IsInstalled(version) {
+				helpMsg := fmt.Sprintf("Install it first with 'govman install %s'", version)
+				_logger.ErrorWithHelp("Go version %s is not installed", helpMsg, version)
+				return fmt.Errorf("version %s not installed", version)
+			}
+
+			changes, err := mgr.Env(version, os.Getenv)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, _shell.EnvChange{Name: _manager.ShellVersionVar, Value: version})
+
+			loadShellTemplates()
+
+			sh := _shell.Detect()
+			if shellName != "" {
+				if sh, err = _shell.ForName(shellName); err != nil {
+					return err
+				}
+			}
+
+			if current := os.Getenv(_manager.ShellVersionVar); current != "" {
+				_logger.Warning("Already in a govman shell for Go %s; starting a nested shell", current)
+			}
+
+			sub, err := _shell.NewSubshell(sh, fmt.Sprintf("(go%s) ", version))
+			if err != nil {
+				return err
+			}
+			defer sub.Cleanup()
+
+			subshell := exec.Command(sub.Path, sub.Args...)
+			subshell.Env = _shell.ApplyEnv(os.Environ(), append(changes, sub.Env...))
+			subshell.Stdin = os.Stdin
+			subshell.Stdout = os.Stdout
+			subshell.Stderr = os.Stderr
+
+			_logger.Info("Starting %s with Go %s. Type 'exit' to return.", sh.DisplayName(), version)
+			_logger.Verbose("Running %s %v", sub.Path, sub.Args)
+
+			if err := subshell.Run(); err != nil {
+				// The exit status of the last command in the subshell is not a failure of govman
+				var exitErr *exec.ExitError
+				if !errors.As(err, &exitErr) {
+					return fmt.Errorf("failed to start %s: %w", sh.DisplayName(), err)
+				}
+			}
+
+			_logger.Info("Left the Go %s shell", version)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&shellName, "shell", "", "Shell to start (bash, zsh, fish, powershell, cmd, nu, xonsh or a template name; default: detected shell)")
+
+	return cmd
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"os"
 
 	cobra "github.com/spf13/cobra"
 
@@ -46,16 +47,29 @@ Smart Features:
   • Shell integration with PATH management
   • Project-specific .govman-version file support
   • Seamless switching between versions
+  • 'govman use -' toggles back to the previous version, like 'cd -'
 
 Examples:
   govman use 1.25.1                 # Session-only activation
   govman use 1.25.1 --default       # Set as system default
-  govman use 1.25.1 --local         # Project-specific version`,
-		Args: cobra.ExactArgs(1),
+  govman use 1.25.1 --local         # Project-specific version
+  govman use -                      # Switch back to the previous version`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeUseVersions,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			version := args[0]
 			mgr := _manager.New(getConfig())
 
+			if version == "-" {
+				previous, err := mgr.PreviousVersion(os.Getenv)
+				if err != nil {
+					_logger.ErrorWithHelp("No previous Go version in this session", "'govman use -' needs the shell integration from 'govman init' to remember versions.")
+					return err
+				}
+				_logger.Verbose("Switching back to Go %s", previous)
+				version = previous
+			}
+
 			if version != "default" {
 				if !mgr.IsInstalled(version) {
 					helpMsg := fmt.Sprintf("Install it first with 'govman install %s', or check available versions with 'govman list'.", version)
@@ -16,6 +16,7 @@ type Config struct {
 	CacheDir       string           `mapstructure:"cache_dir"`
 	DefaultVersion string           `mapstructure:"default_version"`
 	Download       DownloadConfig   `mapstructure:"download"`
+	Network        NetworkConfig    `mapstructure:"network"`
 	Mirror         MirrorConfig     `mapstructure:"mirror"`
 	AutoSwitch     AutoSwitchConfig `mapstructure:"auto_switch"`
 	Shell          ShellConfig      `mapstructure:"shell"`
@@ -23,15 +24,29 @@ type Config struct {
 	SelfUpdate     SelfUpdateConfig `mapstructure:"self_update"`
 	Quiet          bool             `mapstructure:"quiet"`
 	Verbose        bool             `mapstructure:"verbose"`
+	Offline        bool             `mapstructure:"offline"`
 	configPath     string
 }
 
 type DownloadConfig struct {
-	Parallel       bool          `mapstructure:"parallel"`
-	MaxConnections int           `mapstructure:"max_connections"`
-	Timeout        time.Duration `mapstructure:"timeout"`
-	RetryCount     int           `mapstructure:"retry_count"`
-	RetryDelay     time.Duration `mapstructure:"retry_delay"`
+	Parallel        bool          `mapstructure:"parallel"`
+	MaxConnections  int           `mapstructure:"max_connections"`
+	Timeout         time.Duration `mapstructure:"timeout"`
+	RetryCount      int           `mapstructure:"retry_count"`
+	RetryDelay      time.Duration `mapstructure:"retry_delay"`
+	RateLimit       string        `mapstructure:"rate_limit"`
+	VerifySignature bool          `mapstructure:"verify_signature"`
+	Keyring         string        `mapstructure:"keyring"`
+}
+
+type NetworkConfig struct {
+	HTTPProxy     string `mapstructure:"http_proxy"`
+	HTTPSProxy    string `mapstructure:"https_proxy"`
+	NoProxy       string `mapstructure:"no_proxy"`
+	CABundle      string `mapstructure:"ca_bundle"`
+	ClientCert    string `mapstructure:"client_cert"`
+	ClientKey     string `mapstructure:"client_key"`
+	TLSMinVersion string `mapstructure:"tls_min_version"`
 }
 
 type MirrorConfig struct {
@@ -50,9 +65,24 @@ type ShellConfig struct {
 }
 
 type GoReleasesConfig struct {
-	APIURL      string        `mapstructure:"api_url"`
-	DownloadURL string        `mapstructure:"download_url"`
-	CacheExpiry time.Duration `mapstructure:"cache_expiry"`
+	Source           string        `mapstructure:"source"`
+	APIURL           string        `mapstructure:"api_url"`
+	DownloadURL      string        `mapstructure:"download_url"`
+	CacheExpiry      time.Duration `mapstructure:"cache_expiry"`
+	Repository       string        `mapstructure:"repository"`
+	Path             string        `mapstructure:"path"`
+	TrustedKeys      []string      `mapstructure:"trusted_keys"`
+	RequireSignature bool          `mapstructure:"require_signature"`
+	Auth             AuthConfig    `mapstructure:"auth"`
+	GoSum            string        `mapstructure:"go_sum"`
+	SumDB            string        `mapstructure:"sumdb"`
+}
+
+type AuthConfig struct {
+	Netrc    bool              `mapstructure:"netrc"`
+	TokenEnv string            `mapstructure:"token_env"`
+	Headers  map[string]string `mapstructure:"headers"`
+	Hosts    []string          `mapstructure:"hosts"`
 }
 
 type SelfUpdateConfig struct {
@@ -107,7 +137,7 @@ func Load(configFile string) (*Config, error) {
 }
 
 // setDefaults initializes default values for all Config fields:
-// install/cache directories, download behavior, mirror, autoswitch, shell, releases API, and self-update endpoints.
+// install/cache directories, offline mode, download behavior, mirror, autoswitch, shell, releases API, and self-update endpoints.
 func (c *Config) setDefaults() {
 	homeDir, err := getHomeDir()
 	if err != nil {
@@ -120,6 +150,7 @@ func (c *Config) setDefaults() {
 	c.DefaultVersion = ""
 	c.Quiet = false
 	c.Verbose = false
+	c.Offline = false
 
 	c.Download = DownloadConfig{
 		Parallel:       true,
@@ -145,6 +176,7 @@ func (c *Config) setDefaults() {
 	}
 
 	c.GoReleases = GoReleasesConfig{
+		Source:      "auto",
 		APIURL:      "https://go.dev/dl/?mode=json&include=all",
 		DownloadURL: "https://go.dev/dl/%s",
 		CacheExpiry: 10 * time.Minute,
@@ -171,6 +203,25 @@ func (c *Config) expandPaths() error {
 		return fmt.Errorf("failed to expand cache_dir: %w", err)
 	}
 
+	optionalPaths := []struct {
+		name string
+		path *string
+	}{
+		{"download.keyring", &c.Download.Keyring},
+		{"go_releases.go_sum", &c.GoReleases.GoSum},
+		{"network.ca_bundle", &c.Network.CABundle},
+		{"network.client_cert", &c.Network.ClientCert},
+		{"network.client_key", &c.Network.ClientKey},
+	}
+	for _, p := range optionalPaths {
+		if *p.path == "" {
+			continue
+		}
+		if *p.path, err = expandPath(*p.path); err != nil {
+			return fmt.Errorf("failed to expand %s: %w", p.name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -201,7 +252,9 @@ func (c *Config) Save() error {
 	viper.Set("cache_dir", c.CacheDir)
 	viper.Set("quiet", c.Quiet)
 	viper.Set("verbose", c.Verbose)
+	viper.Set("offline", c.Offline)
 	viper.Set("download", c.Download)
+	viper.Set("network", c.Network)
 	viper.Set("mirror", c.Mirror)
 	viper.Set("auto_switch", c.AutoSwitch)
 	viper.Set("shell", c.Shell)
@@ -230,6 +283,11 @@ func (c *Config) GetBinPath() string {
 	return filepath.Join(homeDir, ".govman", "bin")
 }
 
+// GetHistoryFile returns the path of the version switch history, typically ~/.govman/history.log.
+func (c *Config) GetHistoryFile() string {
+	return filepath.Join(filepath.Dir(c.GetBinPath()), "history.log")
+}
+
 // GetCurrentSymlink returns the path to the global "go" symlink inside the bin directory.
 func (c *Config) GetCurrentSymlink() string {
 	return filepath.Join(c.GetBinPath(), "go")
@@ -240,6 +240,10 @@ func TestSetDefaults(t *testing.T) {
 	if cfg.GoReleases.CacheExpiry != 10*time.Minute {
 		t.Errorf("Expected cache expiry 10m, got %v", cfg.GoReleases.CacheExpiry)
 	}
+
+	if cfg.Offline {
+		t.Error("Expected offline mode to be disabled by default")
+	}
 }
 
 func TestExpandPaths(t *testing.T) {
@@ -0,0 +1,93 @@
+package downloader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	zstd "github.com/klauspost/compress/zstd"
+	xz "github.com/ulikunitz/xz"
+)
+
+// Archive formats recognised by detectArchiveFormat.
+const (
+	formatUnknown = ""
+	formatZip     = "zip"
+	formatTar     = "tar"
+	formatTarGz   = "tar.gz"
+	formatTarXz   = "tar.xz"
+	formatTarZst  = "tar.zst"
+)
+
+// tarMagicOffset is the offset of the "ustar" magic in a POSIX or GNU tar header.
+const tarMagicOffset = 257
+
+var (
+	magicGzip = []byte{0x1f, 0x8b}
+	magicXz   = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	magicZstd = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	magicZip  = [][]byte{[]byte("PK\x03\x04"), []byte("PK\x05\x06")}
+	magicTar  = []byte("ustar")
+)
+
+// detectArchiveFormat identifies the archive format of path from its leading magic bytes, so archives from
+// custom sources are extracted correctly regardless of their file name.
+// Returns one of the format constants (formatUnknown if not recognised) or an error if the file cannot be read.
+func detectArchiveFormat(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return formatUnknown, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, tarMagicOffset+len(magicTar))
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return formatUnknown, fmt.Errorf("failed to read archive header: %w", err)
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, magicGzip):
+		return formatTarGz, nil
+	case bytes.HasPrefix(header, magicXz):
+		return formatTarXz, nil
+	case bytes.HasPrefix(header, magicZstd):
+		return formatTarZst, nil
+	case bytes.HasPrefix(header, magicZip[0]), bytes.HasPrefix(header, magicZip[1]):
+		return formatZip, nil
+	case len(header) == tarMagicOffset+len(magicTar) && bytes.Equal(header[tarMagicOffset:], magicTar):
+		return formatTar, nil
+	}
+	return formatUnknown, nil
+}
+
+// decompressReader wraps r with the decompressor for a tar-based format.
+// Returns the reader of the uncompressed tar stream or an error if the compressed header is invalid.
+func decompressReader(r io.Reader, format string) (io.ReadCloser, error) {
+	switch format {
+	case formatTar:
+		return io.NopCloser(r), nil
+	case formatTarGz:
+		gzReader, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return gzReader, nil
+	case formatTarXz:
+		xzReader, err := xz.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create xz reader: %w", err)
+		}
+		return io.NopCloser(xzReader), nil
+	case formatTarZst:
+		zstdReader, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		return zstdReader.IOReadCloser(), nil
+	}
+	return nil, fmt.Errorf("unsupported archive format: %s", format)
+}
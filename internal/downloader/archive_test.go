@@ -0,0 +1,251 @@
+package downloader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	zstd "github.com/klauspost/compress/zstd"
+	xz "github.com/ulikunitz/xz"
+	dirhash "golang.org/x/mod/sumdb/dirhash"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+)
+
+// buildTar returns a tar stream with the given files (name -> content)
+func buildTar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("Failed to write tar header: %v", err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	return buf.Bytes()
+}
+
+// compress compresses data in the given archive format
+func compress(t *testing.T, data []byte, format string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+	switch format {
+	case formatTar:
+		return data
+	case formatTarGz:
+		w = gzip.NewWriter(&buf)
+	case formatTarXz:
+		w, err = xz.NewWriter(&buf)
+	case formatTarZst:
+		w, err = zstd.NewWriter(&buf)
+	}
+	if err != nil {
+		t.Fatalf("Failed to create %s writer: %v", format, err)
+	}
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestDetectArchiveFormat(t *testing.T) {
+	tarData := buildTar(t, map[string]string{"go/bin/go": "binary"})
+
+	testCases := []struct {
+		name     string
+		content  []byte
+		expected string
+	}{
+		{"Gzip", compress(t, tarData, formatTarGz), formatTarGz},
+		{"Xz", compress(t, tarData, formatTarXz), formatTarXz},
+		{"Zstd", compress(t, tarData, formatTarZst), formatTarZst},
+		{"Plain tar", tarData, formatTar},
+		{"Zip", []byte("PK\x03\x04rest of the zip"), formatZip},
+		{"Empty zip", []byte("PK\x05\x06"), formatZip},
+		{"Unknown", []byte("dummy"), formatUnknown},
+		{"Empty file", nil, formatUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "archive")
+			os.WriteFile(path, tc.content, 0644)
+
+			format, err := detectArchiveFormat(path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if format != tc.expected {
+				t.Errorf("Expected format %q, got %q", tc.expected, format)
+			}
+		})
+	}
+
+	if _, err := detectArchiveFormat(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected error for a missing archive")
+	}
+}
+
+func TestDownloader_extractArchive_Formats(t *testing.T) {
+	tarData := buildTar(t, map[string]string{"go/bin/go": "go binary", "go/VERSION": "go1.22.1"})
+
+	testCases := []struct {
+		name        string
+		archiveName string
+		format      string
+	}{
+		{"Tar.xz", "go1.22.1.linux-amd64.tar.xz", formatTarXz},
+		{"Tar.zst", "go1.22.1.linux-amd64.tar.zst", formatTarZst},
+		{"Plain tar", "go1.22.1.linux-amd64.tar", formatTar},
+		{"Zstd with a misleading suffix", "go1.22.1.linux-amd64.tar.gz", formatTarZst},
+		{"Gzip without a suffix", "toolchain", formatTarGz},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := createTestConfig(t)
+			downloader := createTestDownloader(t, config)
+
+			archivePath := filepath.Join(config.CacheDir, tc.archiveName)
+			os.WriteFile(archivePath, compress(t, tarData, tc.format), 0644)
+
+			installDir := filepath.Join(config.InstallDir, "go1.22.1")
+			if err := downloader.extractArchive(archivePath, installDir); err != nil {
+				t.Fatalf("extractArchive failed: %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(installDir, "bin", "go"))
+			if err != nil || string(content) != "go binary" {
+				t.Errorf("Expected extracted binary, got %q (%v)", content, err)
+			}
+		})
+	}
+}
+
+func TestDownloader_extractArchive_FormatsPathTraversal(t *testing.T) {
+	tarData := buildTar(t, map[string]string{"go/../../etc/passwd": "malicious"})
+
+	for _, format := range []string{formatTar, formatTarXz, formatTarZst} {
+		t.Run(format, func(t *testing.T) {
+			config := createTestConfig(t)
+			downloader := createTestDownloader(t, config)
+
+			archivePath := filepath.Join(config.CacheDir, "malicious."+format)
+			os.WriteFile(archivePath, compress(t, tarData, format), 0644)
+
+			err := downloader.extractArchive(archivePath, filepath.Join(config.InstallDir, "malicious"))
+			if err == nil || !strings.Contains(err.Error(), "unsafe path") {
+				t.Errorf("Expected unsafe path error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDownloader_extractArchive_CorruptCompressed(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       []byte
+		errorContains string
+	}{
+		{"Truncated xz header", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00, 0x01}, "xz"},
+		{"Corrupt zstd stream", append([]byte{0x28, 0xb5, 0x2f, 0xfd}, bytes.Repeat([]byte{0xff}, 32)...), "tar"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := createTestConfig(t)
+			downloader := createTestDownloader(t, config)
+
+			archivePath := filepath.Join(config.CacheDir, "corrupt")
+			os.WriteFile(archivePath, tc.content, 0644)
+
+			err := downloader.extractArchive(archivePath, filepath.Join(config.InstallDir, "corrupt"))
+			if err == nil || !strings.Contains(err.Error(), tc.errorContains) {
+				t.Errorf("Expected error containing %q, got %v", tc.errorContains, err)
+			}
+		})
+	}
+}
+
+// writeToolchainZip writes a toolchain module zip for version into dir and returns its path
+func writeToolchainZip(t *testing.T, dir, version string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, version+".zip")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+	for name, content := range map[string]string{"bin/go": "go binary", "pkg/tool/linux_amd64/compile": "compiler", "VERSION": "go1.22.1"} {
+		w, _ := zw.Create(_golang.ToolchainModule + "@" + version + "/" + name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	return path
+}
+
+func TestDownloader_extractZip_ToolchainModule(t *testing.T) {
+	config := createTestConfig(t)
+	downloader := createTestDownloader(t, config)
+
+	archivePath := writeToolchainZip(t, config.CacheDir, "v0.0.1-go1.22.1.linux-amd64")
+	installDir := filepath.Join(config.InstallDir, "go1.22.1")
+	if err := downloader.extractArchive(archivePath, installDir); err != nil {
+		t.Fatalf("extractArchive failed: %v", err)
+	}
+
+	testCases := []struct {
+		path       string
+		executable bool
+	}{
+		{"bin/go", true},
+		{"pkg/tool/linux_amd64/compile", true},
+		{"VERSION", false},
+	}
+	for _, tc := range testCases {
+		info, err := os.Stat(filepath.Join(installDir, filepath.FromSlash(tc.path)))
+		if err != nil {
+			t.Errorf("Expected %s at the root of the install directory: %v", tc.path, err)
+			continue
+		}
+		if runtime.GOOS != "windows" && (info.Mode()&0111 != 0) != tc.executable {
+			t.Errorf("%s: expected executable=%v, got mode %v", tc.path, tc.executable, info.Mode())
+		}
+	}
+}
+
+func TestDownloader_verifyModuleHash(t *testing.T) {
+	config := createTestConfig(t)
+	downloader := createTestDownloader(t, config)
+
+	archivePath := writeToolchainZip(t, config.CacheDir, "v0.0.1-go1.22.1.linux-amd64")
+	hash, err := dirhash.HashZip(archivePath, dirhash.Hash1)
+	if err != nil {
+		t.Fatalf("Failed to hash zip: %v", err)
+	}
+
+	if err := downloader.verifyArchive(archivePath, &_golang.File{ModuleHash: hash}); err != nil {
+		t.Errorf("Expected matching module hash to verify, got %v", err)
+	}
+
+	err = downloader.verifyArchive(archivePath, &_golang.File{ModuleHash: "h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="})
+	if err == nil || !strings.Contains(err.Error(), "module hash mismatch") {
+		t.Errorf("Expected module hash mismatch, got %v", err)
+	}
+}
@@ -3,7 +3,6 @@ package downloader
 import (
 	"archive/tar"
 	"archive/zip"
-	"compress/gzip"
 	"crypto/sha256"
 	"fmt"
 	"io"
@@ -13,10 +12,14 @@ import (
 	"strings"
 	"time"
 
+	dirhash "golang.org/x/mod/sumdb/dirhash"
+
 	_config "github.com/sijunda/govman/internal/config"
 	_golang "github.com/sijunda/govman/internal/golang"
+	_httpclient "github.com/sijunda/govman/internal/httpclient"
 	_logger "github.com/sijunda/govman/internal/logger"
 	_progress "github.com/sijunda/govman/internal/progress"
+	_util "github.com/sijunda/govman/internal/util"
 )
 
 type Downloader struct {
@@ -25,45 +28,72 @@ type Downloader struct {
 }
 
 // New creates a Downloader using the provided configuration.
-// It initializes an HTTP client with the timeout from cfg.Download.Timeout and returns *Downloader.
+// It initializes an HTTP client on the shared network transport with the timeout from cfg.Download.Timeout and returns *Downloader.
 func New(cfg *_config.Config) *Downloader {
 	return &Downloader{
 		config: cfg,
-		client: &http.Client{
-			Timeout: cfg.Download.Timeout,
-		},
+		client: _httpclient.New(cfg.Download.Timeout),
 	}
 }
 
-// Download orchestrates fetching file metadata, downloading the archive, verifying its SHA-256 checksum,
-// and extracting it into installDir for the specified version. Returns an error on any failure.
+// Download orchestrates fetching file metadata, downloading the archive, verifying its SHA-256 checksum
+// (and OpenPGP signature when download.verify_signature is set), and extracting it into installDir
+// for the specified version. Returns an error on any failure.
 func (d *Downloader) Download(url, installDir, version string) error {
 	_logger.InternalProgress("Retrieving file information")
 	timer := _logger.StartTimer("file info retrieval")
-	fileInfo, err := _golang.GetFileInfoWithConfig(version,
-		d.config.GoReleases.APIURL,
-		d.config.GoReleases.CacheExpiry)
+	source, err := _golang.SourceFromConfig(d.config.GoReleases, d.config.CacheDir)
+	if err != nil {
+		_logger.StopTimer(timer)
+		return fmt.Errorf("invalid release source: %w", err)
+	}
+
+	fileInfo, err := _golang.GetFileInfoFromSource(source, version, d.config.GoReleases.CacheExpiry)
 	if err != nil {
 		_logger.StopTimer(timer)
 		return fmt.Errorf("failed to get file info: %w", err)
 	}
 	_logger.StopTimer(timer)
 
+	// Archives and signatures that were already cached (e.g. prefetched with 'govman download')
+	// are kept for the next install; only files fetched by this install are removed afterwards.
+	// Files that fail verification are removed either way, so the next install downloads them again
+	cachePath := filepath.Join(d.config.CacheDir, filepath.Base(url))
+	archiveCached := isCached(cachePath, fileInfo.Size)
+	signatureCached := isCached(cachePath+_golang.ArchiveSignatureSuffix, -1)
+
 	_logger.InternalProgress("Downloading file")
 	archivePath, err := d.downloadFile(url, fileInfo)
 	if err != nil {
 		return fmt.Errorf("failed to download: %w", err)
 	}
-	defer os.Remove(archivePath)
+	if !archiveCached {
+		defer os.Remove(archivePath)
+	}
+	if !signatureCached {
+		defer os.Remove(archivePath + _golang.ArchiveSignatureSuffix)
+	}
 
 	_logger.InternalProgress("Verifying checksum")
 	timer = _logger.StartTimer("checksum verification")
-	if err := d.verifyChecksum(archivePath, fileInfo.Sha256); err != nil {
+	if err := d.verifyArchive(archivePath, fileInfo); err != nil {
 		_logger.StopTimer(timer)
+		removeArchive(archivePath)
 		return fmt.Errorf("checksum verification failed: %w", err)
 	}
 	_logger.StopTimer(timer)
 
+	if d.config.Download.VerifySignature && fileInfo.ModuleHash == "" {
+		_logger.InternalProgress("Verifying signature")
+		timer = _logger.StartTimer("signature verification")
+		if err := d.verifySignature(url, archivePath); err != nil {
+			_logger.StopTimer(timer)
+			removeArchive(archivePath)
+			return fmt.Errorf("signature verification failed: %w", err)
+		}
+		_logger.StopTimer(timer)
+	}
+
 	_logger.InternalProgress("Extracting archive")
 	timer = _logger.StartTimer("archive extraction")
 	if err := d.extractArchive(archivePath, installDir); err != nil {
@@ -75,7 +105,55 @@ func (d *Downloader) Download(url, installDir, version string) error {
 	return nil
 }
 
+// Prefetch downloads the archive for fileInfo into the cache directory and verifies its SHA-256 checksum,
+// and its OpenPGP signature when download.verify_signature is set, without extracting it.
+// A cached archive that fails verification is removed with its signature so the next attempt starts clean.
+// Parameters: url (download URL), fileInfo (expected file metadata). Returns the cached file path or an error.
+func (d *Downloader) Prefetch(url string, fileInfo *_golang.File) (string, error) {
+	if err := os.MkdirAll(d.config.CacheDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	archivePath, err := d.downloadFile(url, fileInfo)
+	if err != nil {
+		return "", fmt.Errorf("failed to download: %w", err)
+	}
+
+	if err := d.verifyArchive(archivePath, fileInfo); err != nil {
+		removeArchive(archivePath)
+		return "", fmt.Errorf("checksum verification failed: %w", err)
+	}
+
+	if d.config.Download.VerifySignature && fileInfo.ModuleHash == "" {
+		if err := d.verifySignature(url, archivePath); err != nil {
+			removeArchive(archivePath)
+			return "", fmt.Errorf("signature verification failed: %w", err)
+		}
+	}
+
+	return archivePath, nil
+}
+
+// isCached reports whether a complete file exists at path. A negative size accepts any size.
+// Parameters: path (cache file path), size (expected size in bytes). Returns true if the file is present.
+func isCached(path string, size int64) bool {
+	stat, err := os.Stat(path)
+	if err != nil || stat.IsDir() {
+		return false
+	}
+	return size < 0 || stat.Size() == size
+}
+
+// removeArchive deletes a cached archive and its signature after a failed verification.
+// Parameters: archivePath (cached archive path).
+func removeArchive(archivePath string) {
+	os.Remove(archivePath)
+	os.Remove(archivePath + _golang.ArchiveSignatureSuffix)
+}
+
 // downloadFile downloads (or resumes) the archive to the cache directory with retries and a progress bar.
+// The body is read through the process-wide limiter for download.rate_limit, so concurrent downloads share it.
+// In offline mode only a complete cached archive is accepted.
 // Parameters: url (download URL), fileInfo (expected file metadata). Returns the cached file path or an error.
 func (d *Downloader) downloadFile(url string, fileInfo *_golang.File) (string, error) {
 	filename := filepath.Base(url)
@@ -86,11 +164,31 @@ func (d *Downloader) downloadFile(url string, fileInfo *_golang.File) (string, e
 			_logger.Success("Using cached file: %s", filename)
 			return cachePath, nil
 		}
+	}
+
+	if strings.HasPrefix(url, "file://") {
+		return d.copyLocalFile(url, cachePath)
+	}
+
+	if stat, err := os.Stat(cachePath); err == nil {
+		if _golang.IsOffline() {
+			return "", fmt.Errorf("offline mode: cached archive %s is incomplete (%d of %d bytes) - run 'govman download %s' while online to finish it",
+				cachePath, stat.Size(), fileInfo.Size, strings.TrimPrefix(fileInfo.Version, "go"))
+		}
 		_logger.Download("Resuming download: %s", filename)
 	} else {
+		if _golang.IsOffline() {
+			return "", fmt.Errorf("offline mode: archive %s is not in the cache - run 'govman download %s' while online to prefetch it",
+				filename, strings.TrimPrefix(fileInfo.Version, "go"))
+		}
 		_logger.Download("Downloading: %s", filename)
 	}
 
+	rateLimit, err := ParseRateLimit(d.config.Download.RateLimit)
+	if err != nil {
+		return "", fmt.Errorf("invalid download.rate_limit: %w", err)
+	}
+
 	file, err := os.OpenFile(cachePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return "", fmt.Errorf("failed to create cache file: %w", err)
@@ -143,11 +241,12 @@ func (d *Downloader) downloadFile(url string, fileInfo *_golang.File) (string, e
 		progressBar.Set(currentSize)
 	}
 
-	var reader io.Reader
+	reader := limitReader(resp.Body, sharedLimiter(rateLimit))
 	if progressBar != nil {
-		reader = io.TeeReader(resp.Body, progressBar)
-	} else {
-		reader = resp.Body
+		reader = io.TeeReader(reader, progressBar)
+	}
+	if rateLimit > 0 {
+		_logger.Verbose("Download bandwidth limited to %s/s", _util.FormatBytes(rateLimit))
 	}
 
 	if _, err := io.Copy(file, reader); err != nil {
@@ -160,6 +259,63 @@ func (d *Downloader) downloadFile(url string, fileInfo *_golang.File) (string, e
 	return cachePath, nil
 }
 
+// copyLocalFile copies an archive referenced by a file:// URL into cachePath.
+// Returns the cached file path or an error if the source cannot be read or the copy fails.
+func (d *Downloader) copyLocalFile(fileURL, cachePath string) (string, error) {
+	srcPath, err := _golang.FilePath(fileURL)
+	if err != nil {
+		return "", err
+	}
+
+	_logger.Download("Copying: %s", srcPath)
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open local archive: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(cachePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return "", fmt.Errorf("failed to copy local archive: %w", err)
+	}
+
+	return cachePath, nil
+}
+
+// verifyArchive checks archivePath against the checksum in fileInfo: the go.sum hash of toolchain module zips,
+// or the SHA-256 of regular archives. Module zips are authenticated by the checksum database, so they have no
+// OpenPGP signature to verify. Returns an error on mismatch or I/O failure.
+func (d *Downloader) verifyArchive(archivePath string, fileInfo *_golang.File) error {
+	if fileInfo.ModuleHash != "" {
+		return d.verifyModuleHash(archivePath, fileInfo.ModuleHash)
+	}
+	return d.verifyChecksum(archivePath, fileInfo.Sha256)
+}
+
+// verifyModuleHash computes the go.sum hash (h1:) of the module zip at filePath and compares it to expectedHash.
+// Returns an error on mismatch or when the zip cannot be read.
+func (d *Downloader) verifyModuleHash(filePath, expectedHash string) error {
+	_logger.Verify("Verifying module hash...")
+
+	actualHash, err := dirhash.HashZip(filePath, dirhash.Hash1)
+	if err != nil {
+		return fmt.Errorf("failed to hash module zip: %w", err)
+	}
+
+	if actualHash != expectedHash {
+		return fmt.Errorf("module hash mismatch: expected %s, got %s", expectedHash, actualHash)
+	}
+
+	_logger.Success("Module hash verified")
+	return nil
+}
+
 // verifyChecksum computes the SHA-256 of filePath and compares it to expectedSHA256.
 // Returns an error on mismatch or I/O failure; nil when the checksum matches.
 func (d *Downloader) verifyChecksum(filePath, expectedSHA256 string) error {
@@ -186,7 +342,8 @@ func (d *Downloader) verifyChecksum(filePath, expectedSHA256 string) error {
 	return nil
 }
 
-// extractArchive ensures installDir exists and extracts archivePath based on its extension (.tar.gz or .zip).
+// extractArchive ensures installDir exists and extracts archivePath based on its format, detected from
+// magic bytes rather than the file name: .tar.gz, .tar.xz, .tar.zst, plain .tar, or .zip.
 // Returns an error for unsupported formats or extraction failures.
 func (d *Downloader) extractArchive(archivePath, installDir string) error {
 	_logger.Extract("Extracting archive...")
@@ -195,31 +352,38 @@ func (d *Downloader) extractArchive(archivePath, installDir string) error {
 		return fmt.Errorf("failed to create install directory: %w", err)
 	}
 
-	if strings.HasSuffix(archivePath, ".tar.gz") {
-		return d.extractTarGz(archivePath, installDir)
-	} else if strings.HasSuffix(archivePath, ".zip") {
+	format, err := detectArchiveFormat(archivePath)
+	if err != nil {
+		return err
+	}
+	_logger.Verbose("Detected archive format: %s", format)
+
+	switch format {
+	case formatZip:
 		return d.extractZip(archivePath, installDir)
+	case formatTar, formatTarGz, formatTarXz, formatTarZst:
+		return d.extractTar(archivePath, installDir, format)
 	}
 
 	return fmt.Errorf("unsupported archive format")
 }
 
-// extractTarGz extracts a .tar.gz archive into installDir with path safety checks and file permissions preserved.
-// Returns an error on I/O issues or unsafe paths.
-func (d *Downloader) extractTarGz(archivePath, installDir string) error {
+// extractTar extracts a tar archive, compressed as given by format, into installDir with path safety checks
+// and file permissions preserved. Returns an error on I/O issues or unsafe paths.
+func (d *Downloader) extractTar(archivePath, installDir, format string) error {
 	file, err := os.Open(archivePath)
 	if err != nil {
 		return fmt.Errorf("failed to open archive: %w", err)
 	}
 	defer file.Close()
 
-	gzReader, err := gzip.NewReader(file)
+	stream, err := decompressReader(file, format)
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
+		return err
 	}
-	defer gzReader.Close()
+	defer stream.Close()
 
-	tarReader := tar.NewReader(gzReader)
+	tarReader := tar.NewReader(stream)
 
 	for {
 		header, err := tarReader.Next()
@@ -292,8 +456,14 @@ func (d *Downloader) extractZip(archivePath, installDir string) error {
 
 	for _, file := range reader.File {
 		path := file.Name
+		module := strings.HasPrefix(path, _golang.ToolchainModule+"@")
 		if strings.HasPrefix(path, "go/") || strings.HasPrefix(path, "go\\") {
 			path = path[3:]
+		} else if module {
+			// Toolchain module zips root the tree at golang.org/toolchain@<version>/
+			if i := strings.Index(path[len(_golang.ToolchainModule):], "/"); i >= 0 {
+				path = path[len(_golang.ToolchainModule)+i+1:]
+			}
 		}
 
 		if path == "" {
@@ -330,7 +500,13 @@ func (d *Downloader) extractZip(archivePath, installDir string) error {
 			return fmt.Errorf("failed to open file in archive: %w", err)
 		}
 
-		dstFile, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		// Module zips carry no permissions; the go command marks bin/ and pkg/tool/ executable the same way
+		mode := os.FileMode(0644)
+		if file.Mode()&0111 != 0 || (module && (strings.HasPrefix(path, "bin/") || strings.HasPrefix(path, "pkg/tool/"))) {
+			mode = 0755
+		}
+
+		dstFile, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
 		if err != nil {
 			srcFile.Close()
 			return fmt.Errorf("failed to create file %s: %w", targetPath, err)
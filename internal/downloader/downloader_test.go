@@ -11,6 +11,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -623,8 +624,8 @@ func TestDownloader_extractArchive(t *testing.T) {
 	}
 }
 
-// TestDownloader_extractTarGz tests tar.gz extraction
-func TestDownloader_extractTarGz(t *testing.T) {
+// TestDownloader_extractArchive_TarGz tests tar.gz extraction
+func TestDownloader_extractArchive_TarGz(t *testing.T) {
 	testCases := []struct {
 		name        string
 		fileName    string
@@ -698,7 +699,7 @@ func TestDownloader_extractTarGz(t *testing.T) {
 			defer os.Remove(tarFile)
 
 			// Extract
-			err = downloader.extractTarGz(tarFile, installDir)
+			err = downloader.extractArchive(tarFile, installDir)
 
 			if tc.expectError {
 				if err == nil {
@@ -708,7 +709,7 @@ func TestDownloader_extractTarGz(t *testing.T) {
 			}
 
 			if err != nil {
-				t.Fatalf("extractTarGz failed: %v", err)
+				t.Fatalf("extractArchive failed: %v", err)
 			}
 
 			// Verify extracted file/directory
@@ -849,8 +850,8 @@ func TestDownloader_extractZip(t *testing.T) {
 	}
 }
 
-// TestDownloader_extractTarGz_PathTraversal tests path traversal protection in tar extraction
-func TestDownloader_extractTarGz_PathTraversal(t *testing.T) {
+// TestDownloader_extractArchive_TarGzPathTraversal tests path traversal protection in tar.gz extraction
+func TestDownloader_extractArchive_TarGzPathTraversal(t *testing.T) {
 	testCases := []struct {
 		name     string
 		fileName string
@@ -912,7 +913,7 @@ func TestDownloader_extractTarGz_PathTraversal(t *testing.T) {
 			defer os.Remove(tarFile)
 
 			// Attempt extraction - should fail
-			err = downloader.extractTarGz(tarFile, installDir)
+			err = downloader.extractArchive(tarFile, installDir)
 			if err == nil || !strings.Contains(err.Error(), tc.expected) {
 				t.Errorf("Expected error containing %q, got: %v", tc.expected, err)
 			}
@@ -1037,8 +1038,8 @@ func TestDownloader_Download_ErrorPaths(t *testing.T) {
 	}
 }
 
-// TestDownloader_extractTarGz_ErrorHandling tests error handling in tar.gz extraction
-func TestDownloader_extractTarGz_ErrorHandling(t *testing.T) {
+// TestDownloader_extractArchive_TarGzErrorHandling tests error handling in tar.gz extraction
+func TestDownloader_extractArchive_TarGzErrorHandling(t *testing.T) {
 	testCases := []struct {
 		name          string
 		setupArchive  func() ([]byte, error)
@@ -1046,9 +1047,9 @@ func TestDownloader_extractTarGz_ErrorHandling(t *testing.T) {
 		errorContains string
 	}{
 		{
-			name: "Invalid gzip data",
+			name: "Plain tar named .tar.gz",
 			setupArchive: func() ([]byte, error) {
-				// Create a tar file without gzip compression
+				// The format is detected from the content, so a tar file without gzip compression still extracts
 				var buf bytes.Buffer
 				tarWriter := tar.NewWriter(&buf)
 				header := &tar.Header{
@@ -1061,6 +1062,13 @@ func TestDownloader_extractTarGz_ErrorHandling(t *testing.T) {
 				tarWriter.Close()
 				return buf.Bytes(), nil
 			},
+			expectError: false,
+		},
+		{
+			name: "Truncated gzip header",
+			setupArchive: func() ([]byte, error) {
+				return []byte{0x1f, 0x8b, 0x08, 0x00}, nil
+			},
 			expectError:   true,
 			errorContains: "failed to create gzip reader",
 		},
@@ -1099,7 +1107,7 @@ func TestDownloader_extractTarGz_ErrorHandling(t *testing.T) {
 			defer os.Remove(tarFile)
 
 			// Attempt extraction
-			err = downloader.extractTarGz(tarFile, installDir)
+			err = downloader.extractArchive(tarFile, installDir)
 
 			if tc.expectError {
 				if err == nil {
@@ -1635,3 +1643,213 @@ func TestDownloader_extractArchive_UnsupportedFormat(t *testing.T) {
 		})
 	}
 }
+
+// TestDownloader_downloadFile_Offline tests that offline mode only accepts complete cached archives
+func TestDownloader_downloadFile_Offline(t *testing.T) {
+	_golang.SetOffline(true)
+	defer _golang.SetOffline(false)
+
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte("remote content"))
+	}))
+	defer server.Close()
+
+	t.Run("Complete cached archive is used", func(t *testing.T) {
+		config := createTestConfig(t)
+		downloader := createTestDownloader(t, config)
+
+		content := "cached content"
+		cachePath := filepath.Join(config.CacheDir, "offline.tar.gz")
+		if err := os.WriteFile(cachePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create cached file: %v", err)
+		}
+
+		fileInfo := mockFileInfo()
+		fileInfo.Size = int64(len(content))
+
+		path, err := downloader.downloadFile(server.URL+"/offline.tar.gz", fileInfo)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if path != cachePath {
+			t.Errorf("Expected %s, got %s", cachePath, path)
+		}
+	})
+
+	t.Run("Missing archive", func(t *testing.T) {
+		config := createTestConfig(t)
+		downloader := createTestDownloader(t, config)
+
+		_, err := downloader.downloadFile(server.URL+"/missing.tar.gz", mockFileInfo())
+		if err == nil || !strings.Contains(err.Error(), "offline mode") {
+			t.Errorf("Expected offline mode error, got %v", err)
+		}
+	})
+
+	t.Run("Partial archive", func(t *testing.T) {
+		config := createTestConfig(t)
+		downloader := createTestDownloader(t, config)
+
+		cachePath := filepath.Join(config.CacheDir, "partial.tar.gz")
+		if err := os.WriteFile(cachePath, []byte("part"), 0644); err != nil {
+			t.Fatalf("Failed to create cached file: %v", err)
+		}
+
+		_, err := downloader.downloadFile(server.URL+"/partial.tar.gz", mockFileInfo())
+		if err == nil || !strings.Contains(err.Error(), "incomplete") {
+			t.Errorf("Expected incomplete archive error, got %v", err)
+		}
+	})
+
+	if hits != 0 {
+		t.Errorf("Expected no network requests in offline mode, got %d", hits)
+	}
+}
+
+// TestDownloader_Prefetch tests that prefetching caches and verifies an archive without extracting it
+func TestDownloader_Prefetch(t *testing.T) {
+	content := []byte("archive content")
+	sum := sha256.Sum256(content)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	t.Run("Valid archive is kept in cache", func(t *testing.T) {
+		config := createTestConfig(t)
+		downloader := createTestDownloader(t, config)
+
+		fileInfo := mockFileInfo()
+		fileInfo.Size = int64(len(content))
+		fileInfo.Sha256 = fmt.Sprintf("%x", sum)
+
+		path, err := downloader.Prefetch(server.URL+"/go1.20.0.linux-amd64.tar.gz", fileInfo)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if path != filepath.Join(config.CacheDir, "go1.20.0.linux-amd64.tar.gz") {
+			t.Errorf("Unexpected cache path %s", path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected archive to remain in cache: %v", err)
+		}
+
+		entries, _ := os.ReadDir(config.InstallDir)
+		if len(entries) != 0 {
+			t.Error("Expected nothing to be extracted")
+		}
+	})
+
+	t.Run("Checksum mismatch removes archive", func(t *testing.T) {
+		config := createTestConfig(t)
+		downloader := createTestDownloader(t, config)
+
+		fileInfo := mockFileInfo()
+		fileInfo.Size = int64(len(content))
+
+		_, err := downloader.Prefetch(server.URL+"/go1.20.0.linux-amd64.tar.gz", fileInfo)
+		if err == nil || !strings.Contains(err.Error(), "checksum") {
+			t.Fatalf("Expected checksum error, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(config.CacheDir, "go1.20.0.linux-amd64.tar.gz")); !os.IsNotExist(err) {
+			t.Error("Expected corrupt archive to be removed")
+		}
+	})
+}
+
+// TestDownloader_Download_CacheCleanup tests that Download keeps archives that were cached before the install
+// and removes only the ones it downloaded itself
+func TestDownloader_Download_CacheCleanup(t *testing.T) {
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzWriter)
+	content := "test file content"
+	tarWriter.WriteHeader(&tar.Header{Name: "test.txt", Size: int64(len(content)), Mode: 0644})
+	tarWriter.Write([]byte(content))
+	tarWriter.Close()
+	gzWriter.Close()
+	archiveData := buf.Bytes()
+
+	filename := fmt.Sprintf("go1.21.0.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+
+	corrupt := bytes.Repeat([]byte{'x'}, len(archiveData))
+
+	testCases := []struct {
+		name        string
+		cached      []byte
+		expectKept  bool
+		expectError bool
+	}{
+		{
+			name:       "Prefetched archive is kept",
+			cached:     archiveData,
+			expectKept: true,
+		},
+		{
+			name:       "Downloaded archive is removed",
+			expectKept: false,
+		},
+		{
+			name:        "Prefetched archive failing verification is removed",
+			cached:      corrupt,
+			expectKept:  false,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := createTestConfig(t)
+			downloader := createTestDownloader(t, config)
+
+			downloads := 0
+			downloadServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				downloads++
+				w.Write(archiveData)
+			}))
+			defer downloadServer.Close()
+
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `[{"version":"go1.21.0","stable":true,"files":[{"filename":"%s","os":"%s","arch":"%s","version":"go1.21.0","sha256":"%x","size":%d,"kind":"archive"}]}]`,
+					filename, runtime.GOOS, runtime.GOARCH, sha256.Sum256(archiveData), len(archiveData))
+			}))
+			defer apiServer.Close()
+			config.GoReleases.APIURL = apiServer.URL
+			_golang.ClearReleasesCache()
+
+			cachePath := filepath.Join(config.CacheDir, filename)
+			if tc.cached != nil {
+				if err := os.WriteFile(cachePath, tc.cached, 0644); err != nil {
+					t.Fatalf("Failed to seed cache: %v", err)
+				}
+				os.WriteFile(cachePath+_golang.ArchiveSignatureSuffix, []byte("signature"), 0644)
+			}
+
+			err := downloader.Download(downloadServer.URL+"/"+filename, filepath.Join(config.InstallDir, "go1.21.0"), "1.21.0")
+			if tc.expectError && err == nil {
+				t.Fatal("Expected error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tc.cached != nil && downloads != 0 {
+				t.Errorf("Expected the cached archive to be used, got %d downloads", downloads)
+			}
+			_, err = os.Stat(cachePath)
+			if kept := err == nil; kept != tc.expectKept {
+				t.Errorf("Expected archive kept=%v, got kept=%v", tc.expectKept, kept)
+			}
+			if tc.cached != nil {
+				_, err = os.Stat(cachePath + _golang.ArchiveSignatureSuffix)
+				if kept := err == nil; kept != tc.expectKept {
+					t.Errorf("Expected signature kept=%v, got kept=%v", tc.expectKept, kept)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	_util "github.com/sijunda/govman/internal/util"
+)
+
+// maxBurst bounds how much unused bandwidth a limiter saves up while idle.
+const maxBurst = 250 * time.Millisecond
+
+// minChunk is the smallest read size handed to a limiter, so very low limits still make progress smoothly.
+const minChunk = 1024
+
+var (
+	limitersMutex sync.Mutex
+	limiters      = map[int64]*rateLimiter{}
+)
+
+// rateLimiter is a token bucket that paces reads to a number of bytes per second.
+// Tokens may go negative: each reader reserves what it read and sleeps off its share of the debt,
+// so concurrent downloads split the bandwidth between them.
+type rateLimiter struct {
+	mutex  sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// sharedLimiter returns the process-wide limiter for bytesPerSecond, so every download with the same
+// limit draws from one budget. Returns nil when bytesPerSecond is not positive (unlimited).
+func sharedLimiter(bytesPerSecond int64) *rateLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	limitersMutex.Lock()
+	defer limitersMutex.Unlock()
+
+	limiter, ok := limiters[bytesPerSecond]
+	if !ok {
+		limiter = &rateLimiter{rate: float64(bytesPerSecond), last: time.Now()}
+		limiters[bytesPerSecond] = limiter
+	}
+	return limiter
+}
+
+// chunkSize returns the largest read that keeps the pacing smooth: a tenth of a second of bandwidth.
+func (l *rateLimiter) chunkSize() int {
+	chunk := int(l.rate / 10)
+	if chunk < minChunk {
+		return minChunk
+	}
+	return chunk
+}
+
+// wait accounts for n bytes and sleeps until the limiter's budget allows them.
+func (l *rateLimiter) wait(n int) {
+	if n <= 0 {
+		return
+	}
+
+	l.mutex.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if burst := maxBurst.Seconds() * l.rate; l.tokens > burst {
+		l.tokens = burst
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	deficit := -l.tokens
+	l.mutex.Unlock()
+
+	if deficit > 0 {
+		time.Sleep(time.Duration(deficit / l.rate * float64(time.Second)))
+	}
+}
+
+// limitedReader paces reads from r through a rateLimiter.
+type limitedReader struct {
+	r       io.Reader
+	limiter *rateLimiter
+}
+
+// Read implements io.Reader, reading at most one chunk at a time and waiting for its bandwidth.
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if chunk := lr.limiter.chunkSize(); len(p) > chunk {
+		p = p[:chunk]
+	}
+
+	n, err := lr.r.Read(p)
+	lr.limiter.wait(n)
+	return n, err
+}
+
+// limitReader wraps r with the limiter, or returns r unchanged when limiter is nil.
+func limitReader(r io.Reader, limiter *rateLimiter) io.Reader {
+	if limiter == nil {
+		return r
+	}
+	return &limitedReader{r: r, limiter: limiter}
+}
+
+// ParseRateLimit parses a bandwidth limit such as "5MB/s", "500KB", or "1.5M" into bytes per second.
+// An empty value, "0", or "unlimited" means no limit. Returns the rate or an error.
+func ParseRateLimit(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" || strings.EqualFold(value, "unlimited") {
+		return 0, nil
+	}
+
+	rate, err := _util.ParseBytes(strings.TrimSuffix(strings.TrimSuffix(value, "/s"), "ps"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate limit %q: expected a value such as 5MB/s or 500KB/s", value)
+	}
+	return rate, nil
+}
@@ -0,0 +1,105 @@
+package downloader
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    int64
+		expectError bool
+	}{
+		{input: "", expected: 0},
+		{input: "0", expected: 0},
+		{input: "unlimited", expected: 0},
+		{input: "5MB/s", expected: 5 * 1024 * 1024},
+		{input: "500KB/s", expected: 500 * 1024},
+		{input: "1.5M", expected: 1536 * 1024},
+		{input: "2MBps", expected: 2 * 1024 * 1024},
+		{input: "fast", expectError: true},
+		{input: "-5MB/s", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseRateLimit(tc.input)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("ParseRateLimit(%q) = %d; want %d", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSharedLimiter(t *testing.T) {
+	if sharedLimiter(0) != nil {
+		t.Error("Expected no limiter for an unlimited rate")
+	}
+	if sharedLimiter(1000) != sharedLimiter(1000) {
+		t.Error("Expected downloads with the same limit to share one limiter")
+	}
+}
+
+func TestDownloader_downloadFile_RateLimit(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 32*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	fileInfo := mockFileInfo()
+	fileInfo.Size = int64(len(content))
+
+	t.Run("Parallel downloads share the limit", func(t *testing.T) {
+		// Two 32KB downloads at 64KB/s take about a second in total, minus the idle burst of 16KB.
+		start := time.Now()
+		var wg sync.WaitGroup
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				config := createTestConfig(t)
+				config.Download.RateLimit = "64KB/s"
+				if _, err := createTestDownloader(t, config).downloadFile(server.URL+"/go1.20.0.linux-amd64.tar.gz", fileInfo); err != nil {
+					errs <- err
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Fatalf("downloadFile failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 700*time.Millisecond {
+			t.Errorf("Expected the shared limit to slow both downloads down, took %v", elapsed)
+		}
+	})
+
+	t.Run("Invalid limit", func(t *testing.T) {
+		config := createTestConfig(t)
+		config.Download.RateLimit = "fast"
+		_, err := createTestDownloader(t, config).downloadFile(server.URL+"/go1.20.0.linux-amd64.tar.gz", &_golang.File{Size: 1})
+		if err == nil || !strings.Contains(err.Error(), "rate_limit") {
+			t.Errorf("Expected rate_limit error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package downloader
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	openpgp "github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+	_logger "github.com/sijunda/govman/internal/logger"
+)
+
+// bundledKeys holds the OpenPGP public keys shipped with govman (see keys/README.md).
+//
+//go:embed keys
+var bundledKeys embed.FS
+
+// maxSignatureSize bounds the size of a downloaded detached signature.
+const maxSignatureSize = 64 << 10
+
+// goReleaseKeyFingerprints are the primary key fingerprints a bundled key must have. Go release archives are
+// signed by a subkey of Google's Linux packages signing key; pinning the primary key keeps subkey rotations working.
+var goReleaseKeyFingerprints = []string{
+	"EB4C1BFD4F042F6DDDCCEC917721F63BD38B4796",
+}
+
+// verifySignature checks archivePath against the detached OpenPGP signature published next to url
+// (url + ".asc"), using the configured keyring or the bundled keys. The signature is cached next to the
+// archive so offline installs can verify it too.
+// Returns an error if the signature is missing, malformed, made by an unknown key, or does not match.
+func (d *Downloader) verifySignature(url, archivePath string) error {
+	_logger.Verify("Verifying signature...")
+
+	keyring, err := d.loadKeyring()
+	if err != nil {
+		return err
+	}
+
+	sigPath := archivePath + _golang.ArchiveSignatureSuffix
+	sig, err := d.fetchSignature(url, sigPath)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	var signer *openpgp.Entity
+	if bytes.HasPrefix(bytes.TrimSpace(sig), []byte("-----BEGIN")) {
+		signer, err = openpgp.CheckArmoredDetachedSignature(keyring, file, bytes.NewReader(sig), nil)
+	} else {
+		signer, err = openpgp.CheckDetachedSignature(keyring, file, bytes.NewReader(sig), nil)
+	}
+	if err != nil {
+		// Fetch the signature again next time instead of trusting a cached bad copy
+		os.Remove(sigPath)
+		if errors.Is(err, pgperrors.ErrUnknownIssuer) {
+			return fmt.Errorf("archive is signed by a key that is not in the keyring")
+		}
+		return fmt.Errorf("bad signature: %w", err)
+	}
+
+	_logger.Success("Signature verified (key %X)", signer.PrimaryKey.Fingerprint)
+	return nil
+}
+
+// loadKeyring reads the keyring configured in download.keyring, or the keys bundled with govman.
+// Both armored and binary keyrings are accepted. Returns an error if no key is available or a bundled key
+// is not one of goReleaseKeyFingerprints.
+func (d *Downloader) loadKeyring() (openpgp.EntityList, error) {
+	if d.config.Download.Keyring != "" {
+		data, err := os.ReadFile(d.config.Download.Keyring)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keyring: %w", err)
+		}
+
+		keyring, err := parseKeyring(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse keyring %s: %w", d.config.Download.Keyring, err)
+		}
+		return keyring, nil
+	}
+
+	var keyring openpgp.EntityList
+	entries, err := fs.ReadDir(bundledKeys, "keys")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundled keys: %w", err)
+	}
+
+	for _, entry := range entries {
+		if ext := path.Ext(entry.Name()); ext != ".asc" && ext != ".gpg" {
+			continue
+		}
+
+		data, err := bundledKeys.ReadFile(path.Join("keys", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundled key %s: %w", entry.Name(), err)
+		}
+
+		keys, err := parseKeyring(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bundled key %s: %w", entry.Name(), err)
+		}
+		for _, key := range keys {
+			if !isGoReleaseKey(key) {
+				return nil, fmt.Errorf("bundled key %s has fingerprint %X, which is not a known Go release signing key", entry.Name(), key.PrimaryKey.Fingerprint)
+			}
+		}
+		keyring = append(keyring, keys...)
+	}
+
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("no OpenPGP keys are bundled with this build; set download.keyring to a keyring containing the Go release signing key")
+	}
+
+	return keyring, nil
+}
+
+// isGoReleaseKey reports whether the primary key of entity is one of goReleaseKeyFingerprints.
+func isGoReleaseKey(entity *openpgp.Entity) bool {
+	fingerprint := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+	for _, known := range goReleaseKeyFingerprints {
+		if fingerprint == known {
+			return true
+		}
+	}
+	return false
+}
+
+// parseKeyring decodes an armored or binary OpenPGP keyring.
+func parseKeyring(data []byte) (openpgp.EntityList, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN")) {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	}
+	return openpgp.ReadKeyRing(bytes.NewReader(data))
+}
+
+// fetchSignature returns the detached signature for the archive at url, downloading it into cachePath.
+// In offline mode, or when the signature was cached by an earlier download, the cached copy is used.
+// Returns the signature bytes or an error.
+func (d *Downloader) fetchSignature(url, cachePath string) ([]byte, error) {
+	if sig, err := os.ReadFile(cachePath); err == nil {
+		return sig, nil
+	}
+
+	sigURL := url + _golang.ArchiveSignatureSuffix
+
+	var sig []byte
+	if strings.HasPrefix(url, "file://") {
+		srcPath, err := _golang.FilePath(sigURL)
+		if err != nil {
+			return nil, err
+		}
+		if sig, err = os.ReadFile(srcPath); err != nil {
+			return nil, fmt.Errorf("signature %s not found: %w", srcPath, err)
+		}
+	} else {
+		if _golang.IsOffline() {
+			return nil, fmt.Errorf("offline mode: signature %s is not in the cache - run 'govman download' while online to prefetch it",
+				filepath.Base(cachePath))
+		}
+
+		resp, err := d.client.Get(sigURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download signature: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to download signature %s: %s", sigURL, resp.Status)
+		}
+
+		if sig, err = io.ReadAll(io.LimitReader(resp.Body, maxSignatureSize)); err != nil {
+			return nil, fmt.Errorf("failed to read signature: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(cachePath, sig, 0644); err != nil {
+		return nil, fmt.Errorf("failed to cache signature: %w", err)
+	}
+
+	return sig, nil
+}
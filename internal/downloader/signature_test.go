@@ -0,0 +1,242 @@
+package downloader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	openpgp "github.com/ProtonMail/go-crypto/openpgp"
+	armor "github.com/ProtonMail/go-crypto/openpgp/armor"
+	packet "github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// createTestKey creates an OpenPGP key pair and writes its armored public key to a keyring file
+func createTestKey(t *testing.T, name string) (*openpgp.Entity, string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("Failed to armor key: %v", err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatalf("Failed to serialize key: %v", err)
+	}
+	w.Close()
+
+	path := filepath.Join(t.TempDir(), name+".asc")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write keyring: %v", err)
+	}
+	return entity, path
+}
+
+// signContent returns an armored detached signature of content
+func signContent(t *testing.T, entity *openpgp.Entity, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&buf, entity, bytes.NewReader(content), nil); err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDownloader_verifySignature(t *testing.T) {
+	content := []byte("archive content")
+	sum := sha256.Sum256(content)
+
+	signer, keyring := createTestKey(t, "release")
+	stranger, _ := createTestKey(t, "stranger")
+
+	tests := []struct {
+		name        string
+		signature   []byte
+		keyring     string
+		expectError string
+	}{
+		{
+			name:      "Valid signature",
+			signature: signContent(t, signer, content),
+			keyring:   keyring,
+		},
+		{
+			name:        "Signature over different content",
+			signature:   signContent(t, signer, []byte("tampered")),
+			keyring:     keyring,
+			expectError: "bad signature",
+		},
+		{
+			name:        "Unknown signing key",
+			signature:   signContent(t, stranger, content),
+			keyring:     keyring,
+			expectError: "not in the keyring",
+		},
+		{
+			name:        "Missing signature",
+			keyring:     keyring,
+			expectError: "404",
+		},
+		{
+			name:        "No bundled keys",
+			signature:   signContent(t, signer, content),
+			expectError: "download.keyring",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, ".asc") {
+					if tt.signature == nil {
+						http.NotFound(w, r)
+						return
+					}
+					w.Write(tt.signature)
+					return
+				}
+				w.Write(content)
+			}))
+			defer server.Close()
+
+			config := createTestConfig(t)
+			config.Download.VerifySignature = true
+			config.Download.Keyring = tt.keyring
+			downloader := createTestDownloader(t, config)
+
+			fileInfo := mockFileInfo()
+			fileInfo.Size = int64(len(content))
+			fileInfo.Sha256 = fmt.Sprintf("%x", sum)
+
+			archivePath, err := downloader.Prefetch(server.URL+"/go1.20.0.linux-amd64.tar.gz", fileInfo)
+			if tt.expectError == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if _, err := os.Stat(archivePath + ".asc"); err != nil {
+					t.Errorf("Expected signature to be cached: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Fatalf("Expected error containing %q, got %v", tt.expectError, err)
+			}
+			if _, err := os.Stat(filepath.Join(config.CacheDir, "go1.20.0.linux-amd64.tar.gz")); !os.IsNotExist(err) {
+				t.Error("Expected unverified archive to be removed")
+			}
+		})
+	}
+}
+
+func TestDownloader_verifySignature_LocalFile(t *testing.T) {
+	content := []byte("archive content")
+	signer, keyring := createTestKey(t, "release")
+
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "go1.20.0.linux-amd64.tar.gz")
+	os.WriteFile(archive, content, 0644)
+	os.WriteFile(archive+".asc", signContent(t, signer, content), 0644)
+
+	config := createTestConfig(t)
+	config.Download.VerifySignature = true
+	config.Download.Keyring = keyring
+	downloader := createTestDownloader(t, config)
+
+	fileInfo := mockFileInfo()
+	fileInfo.Size = int64(len(content))
+	fileInfo.Sha256 = fmt.Sprintf("%x", sha256.Sum256(content))
+
+	if _, err := downloader.Prefetch("file://"+filepath.ToSlash(archive), fileInfo); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+// TestBundledKeys tests that the bundled keys are the pinned Go release signing keys and that the real
+// Go release signatures in testdata were made by one of them
+func TestBundledKeys(t *testing.T) {
+	d := New(createTestConfig(t))
+	keyring, err := d.loadKeyring()
+	if err != nil {
+		t.Skipf("No Go release signing key bundled yet (see keys/README.md): %v", err)
+	}
+
+	for _, key := range keyring {
+		if !isGoReleaseKey(key) {
+			t.Errorf("Bundled key %X is not pinned in goReleaseKeyFingerprints", key.PrimaryKey.Fingerprint)
+		}
+	}
+
+	signatures, _ := filepath.Glob(filepath.Join("testdata", "*.asc"))
+	if len(signatures) == 0 {
+		t.Fatal("Expected at least one real Go release signature in testdata")
+	}
+
+	for _, path := range signatures {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read signature: %v", err)
+			}
+
+			block, err := armor.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Failed to decode signature: %v", err)
+			}
+			pkt, err := packet.Read(block.Body)
+			if err != nil {
+				t.Fatalf("Failed to parse signature: %v", err)
+			}
+
+			sig, ok := pkt.(*packet.Signature)
+			if !ok || sig.IssuerKeyId == nil {
+				t.Fatalf("Expected a signature packet with an issuer, got %T", pkt)
+			}
+			if len(keyring.KeysById(*sig.IssuerKeyId)) == 0 {
+				t.Errorf("Signature issuer %X is not a bundled key", *sig.IssuerKeyId)
+			}
+		})
+	}
+}
+
+func TestIsGoReleaseKey(t *testing.T) {
+	// A 2019 export of Google's Linux packages signing key; its signing subkeys have expired since,
+	// so it only serves to check the pinned primary key fingerprint
+	data, err := os.ReadFile(filepath.Join("testdata", "google-linux-signing-key-2019.pub"))
+	if err != nil {
+		t.Fatalf("Failed to read key: %v", err)
+	}
+	google, err := parseKeyring(data)
+	if err != nil || len(google) != 1 {
+		t.Fatalf("Failed to parse key: %v", err)
+	}
+	impostor, _ := createTestKey(t, "impostor")
+
+	testCases := []struct {
+		name     string
+		entity   *openpgp.Entity
+		expected bool
+	}{
+		{name: "Google Linux packages signing key", entity: google[0], expected: true},
+		{name: "Arbitrary key", entity: impostor, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if isGoReleaseKey(tc.entity) != tc.expected {
+				t.Errorf("Expected isGoReleaseKey to be %v for %X", tc.expected, tc.entity.PrimaryKey.Fingerprint)
+			}
+		})
+	}
+}
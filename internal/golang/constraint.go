@@ -0,0 +1,108 @@
+package golang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// constraintOperators lists the supported comparison operators, longest first so prefixes match correctly.
+var constraintOperators = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// Constraint is a set of version conditions that must all hold, such as ">=1.21, <1.23".
+type Constraint struct {
+	terms []constraintTerm
+}
+
+type constraintTerm struct {
+	op      string
+	version string
+}
+
+// ParseConstraint parses a version constraint expression.
+// Terms are separated by commas or spaces and use the operators >=, <=, >, <, =, == and !=.
+// A bare "1.21" matches every 1.21.x release, a bare "1.21.3" matches exactly, and "*" or "all" matches everything.
+// Returns the parsed *Constraint or an error for malformed terms.
+func ParseConstraint(expr string) (*Constraint, error) {
+	fields := strings.FieldsFunc(expr, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	c := &Constraint{}
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if field == "*" || field == "all" {
+			continue
+		}
+
+		op := ""
+		for _, candidate := range constraintOperators {
+			if strings.HasPrefix(field, candidate) {
+				op = candidate
+				break
+			}
+		}
+
+		version := strings.TrimPrefix(field, op)
+		// Allow a space between the operator and the version (">= 1.21")
+		if version == "" && op != "" && i+1 < len(fields) {
+			i++
+			version = fields[i]
+		}
+
+		version = normalizeVersion(version)
+		if !IsValidVersion(version) {
+			return nil, fmt.Errorf("invalid version constraint %q", field)
+		}
+
+		c.terms = append(c.terms, constraintTerm{op: op, version: version})
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+
+	return c, nil
+}
+
+// Match reports whether version (with or without the "go" prefix) satisfies every term of the constraint.
+func (c *Constraint) Match(version string) bool {
+	version = normalizeVersion(version)
+
+	for _, term := range c.terms {
+		cmp := CompareVersions(version, term.version)
+
+		var ok bool
+		switch term.op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "!=":
+			ok = cmp != 0
+		case "=", "==":
+			ok = cmp == 0
+		default:
+			ok = matchesVersionPrefix(version, term.version)
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesVersionPrefix reports whether version equals want or, when want is "major.minor", is one of its releases.
+func matchesVersionPrefix(version, want string) bool {
+	if strings.Count(want, ".") == 1 {
+		return version == want || strings.HasPrefix(version, want+".") || strings.HasPrefix(version, want+"rc") ||
+			strings.HasPrefix(version, want+"beta")
+	}
+
+	return CompareVersions(version, want) == 0
+}
@@ -0,0 +1,48 @@
+package golang
+
+import "testing"
+
+func TestConstraintMatch(t *testing.T) {
+	testCases := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{">=1.21", "go1.21.0", true},
+		{">=1.21", "1.22.5", true},
+		{">=1.21", "1.20.14", false},
+		{">=1.21", "1.21rc2", false},
+		{">=1.21, <1.23", "1.22.9", true},
+		{">=1.21, <1.23", "1.23.0", false},
+		{">= 1.21 <1.23", "1.21.3", true},
+		{"1.22", "1.22.4", true},
+		{"1.22", "1.22rc1", true},
+		{"1.22", "1.2.2", false},
+		{"1.22", "1.23.0", false},
+		{"1.22.4", "1.22.4", true},
+		{"1.22.4", "1.22.5", false},
+		{"!=1.22.4", "1.22.5", true},
+		{"=1.22.4", "go1.22.4", true},
+		{"*", "1.10.1", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.constraint+"/"+tc.version, func(t *testing.T) {
+			c, err := ParseConstraint(tc.constraint)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := c.Match(tc.version); got != tc.expected {
+				t.Errorf("Match(%q) = %v, expected %v", tc.version, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, expr := range []string{"", ">=", ">=abc", "1", "~1.21"} {
+		if _, err := ParseConstraint(expr); err == nil {
+			t.Errorf("Expected error for %q", expr)
+		}
+	}
+}
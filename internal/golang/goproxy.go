@@ -0,0 +1,533 @@
+package golang
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	module "golang.org/x/mod/module"
+	sumdb "golang.org/x/mod/sumdb"
+	note "golang.org/x/mod/sumdb/note"
+
+	_httpclient "github.com/sijunda/govman/internal/httpclient"
+	_logger "github.com/sijunda/govman/internal/logger"
+)
+
+// ToolchainModule is the module path Go toolchains are published under since Go 1.21.
+const ToolchainModule = "golang.org/toolchain"
+
+// DefaultSumDB is the checksum database used when go_releases.sumdb is empty.
+const DefaultSumDB = "sum.golang.org"
+
+// sumGolangOrgKey is the verifier key of sum.golang.org, as built into the go command.
+const sumGolangOrgKey = "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8"
+
+// defaultGoProxy is used when neither go_releases.api_url nor GOPROXY name a proxy.
+const defaultGoProxy = "https://proxy.golang.org"
+
+// sumdbDirName is the directory under the cache directory holding checksum database state.
+const sumdbDirName = "sumdb"
+
+var toolchainVersionRegex = regexp.MustCompile(`^v0\.0\.1-go(\d+\.\d+(?:\.\d+)?(?:(?:rc|beta|alpha)\d+)?)\.([a-z0-9]+)-([a-z0-9]+)$`)
+
+// GoProxySource lists and downloads Go toolchains published as golang.org/toolchain module versions
+// from a module proxy (proxy.golang.org, Athens, Artifactory, or a file:// proxy directory).
+// Module zips are verified against pinned go.sum hashes or a checksum database instead of SHA-256 checksums.
+type GoProxySource struct {
+	proxyURL string
+	goSum    string
+	cacheDir string
+	sumdb    *sumdbOps
+
+	clientOnce sync.Once
+	client     *sumdb.Client
+}
+
+// NewGoProxySource creates a source for the module proxy at proxyURL; an empty proxyURL uses the first proxy
+// in GOPROXY. goSum optionally names a go.sum-style file of pinned hashes, and sumdbSetting selects the checksum
+// database in GOSUMDB syntax ("off" disables it). cacheDir is the download cache, where offline lookups find
+// module zips and checksum database state is kept; an empty cacheDir keeps that state in memory only.
+// Returns an error for an invalid checksum database setting.
+func NewGoProxySource(proxyURL, goSum, sumdbSetting, cacheDir string) (*GoProxySource, error) {
+	if proxyURL == "" {
+		proxyURL = proxyFromEnv()
+	}
+
+	src := &GoProxySource{
+		proxyURL: strings.TrimRight(proxyURL, "/"),
+		goSum:    goSum,
+		cacheDir: cacheDir,
+	}
+
+	if strings.TrimSpace(sumdbSetting) != "off" {
+		ops, err := newSumdbOps(sumdbSetting, src.proxyURL, cacheDir)
+		if err != nil {
+			return nil, err
+		}
+		src.sumdb = ops
+	} else if goSum == "" {
+		return nil, fmt.Errorf("go_releases.go_sum is required when go_releases.sumdb is off")
+	}
+
+	return src, nil
+}
+
+// proxyFromEnv returns the first proxy URL listed in GOPROXY, skipping "direct" and "off".
+func proxyFromEnv() string {
+	for _, entry := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		entry = strings.TrimSpace(entry)
+		if entry != "" && entry != "direct" && entry != "off" {
+			return entry
+		}
+	}
+	return defaultGoProxy
+}
+
+// ID returns the identifier of the module proxy source.
+func (s *GoProxySource) ID() string {
+	return "goproxy:" + _httpclient.RedactURL(s.proxyURL)
+}
+
+// isLocal reports whether the proxy is a file:// directory, which is read without the network even in offline mode.
+func (s *GoProxySource) isLocal() bool {
+	return strings.HasPrefix(s.proxyURL, "file://")
+}
+
+// endpoints returns the proxy URL, which serves the version lists, the module zips and a proxied checksum database.
+func (s *GoProxySource) endpoints() []string {
+	return []string{s.proxyURL + "/"}
+}
+
+// Fetch reads the proxy's version list of the toolchain module and groups the versions into releases.
+func (s *GoProxySource) Fetch(prev *ReleaseIndex) (*ReleaseIndex, error) {
+	data, err := s.read(s.moduleURL("list"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+
+	var files []File
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if file, ok := parseToolchainVersion(strings.TrimSpace(scanner.Text())); ok {
+			files = append(files, file)
+		}
+	}
+
+	return &ReleaseIndex{
+		Source:    s.ID(),
+		FetchedAt: time.Now(),
+		Releases:  ReleasesFromFiles(files),
+	}, nil
+}
+
+// DownloadURL returns the proxy URL of the module zip for file.
+func (s *GoProxySource) DownloadURL(file File) string {
+	return s.moduleURL(file.Filename)
+}
+
+// probeFile checks whether the proxy serves the toolchain for version on goos/goarch. Proxies such as
+// proxy.golang.org only list versions that were requested before, so the version list is not authoritative.
+// In offline mode only a module zip already in the cache or in a file:// proxy is found.
+// Returns nil if the version does not exist.
+func (s *GoProxySource) probeFile(version, goos, goarch string) *File {
+	file, ok := parseToolchainVersion(fmt.Sprintf("v0.0.1-go%s.%s-%s", version, goos, goarch))
+	if !ok {
+		return nil
+	}
+
+	if IsOffline() && !s.isLocal() {
+		if s.cacheDir != "" {
+			if _, err := os.Stat(filepath.Join(s.cacheDir, file.Filename)); err == nil {
+				return &file
+			}
+		}
+		return nil
+	}
+
+	if _, err := s.read(s.moduleURL(strings.TrimSuffix(file.Filename, ".zip") + ".info")); err != nil {
+		_logger.Verbose("Module proxy has no %s: %v", file.Filename, err)
+		return nil
+	}
+	return &file
+}
+
+// resolveFile fills in the size and module hash of file, which the proxy's version list does not carry.
+// The hash comes from the go_sum file when it pins the version, and from the checksum database otherwise.
+func (s *GoProxySource) resolveFile(file File) (File, error) {
+	version := strings.TrimSuffix(file.Filename, ".zip")
+
+	hash, err := s.moduleHash(version)
+	if err != nil {
+		return file, err
+	}
+	file.ModuleHash = hash
+
+	size, err := s.zipSize(file)
+	if err != nil {
+		return file, err
+	}
+	file.Size = size
+
+	return file, nil
+}
+
+// moduleHash returns the h1: hash of the toolchain module at version.
+func (s *GoProxySource) moduleHash(version string) (string, error) {
+	if s.goSum != "" {
+		hash, err := lookupGoSum(s.goSum, ToolchainModule, version)
+		if err != nil {
+			return "", err
+		}
+		if hash != "" {
+			_logger.Verbose("Using pinned hash for %s@%s from %s", ToolchainModule, version, s.goSum)
+			return hash, nil
+		}
+	}
+
+	if s.sumdb == nil {
+		return "", fmt.Errorf("%s@%s is not listed in %s and the checksum database is off", ToolchainModule, version, s.goSum)
+	}
+
+	s.clientOnce.Do(func() {
+		s.client = sumdb.NewClient(s.sumdb)
+	})
+
+	lines, err := s.client.Lookup(ToolchainModule, version)
+	if err != nil {
+		return "", fmt.Errorf("checksum database lookup for %s@%s failed: %w", ToolchainModule, version, err)
+	}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == ToolchainModule && fields[1] == version {
+			_logger.Verbose("Verified %s@%s against %s", ToolchainModule, version, s.sumdb.name)
+			return fields[2], nil
+		}
+	}
+	return "", fmt.Errorf("checksum database has no hash for %s@%s", ToolchainModule, version)
+}
+
+// zipSize returns the size of the module zip: from the filesystem for file:// proxies, from the cached archive
+// in offline mode, and from a HEAD request otherwise.
+func (s *GoProxySource) zipSize(file File) (int64, error) {
+	zipURL := s.DownloadURL(file)
+
+	if strings.HasPrefix(zipURL, "file://") {
+		path, err := FilePath(zipURL)
+		if err != nil {
+			return 0, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to stat module zip: %w", err)
+		}
+		return info.Size(), nil
+	}
+
+	if IsOffline() {
+		if s.cacheDir != "" {
+			if info, err := os.Stat(filepath.Join(s.cacheDir, file.Filename)); err == nil {
+				return info.Size(), nil
+			}
+		}
+		return 0, fmt.Errorf("offline mode: module zip %s is not in the cache", file.Filename)
+	}
+
+	resp, err := newSourceClient().Head(zipURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query module zip: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to query module zip: HTTP %d (%s)", resp.StatusCode, resp.Status)
+	}
+	if resp.ContentLength < 0 {
+		return 0, fmt.Errorf("module proxy did not report the size of %s", file.Filename)
+	}
+	return resp.ContentLength, nil
+}
+
+// moduleURL returns the URL of name (e.g. "list" or a zip file) under the toolchain module's @v directory.
+func (s *GoProxySource) moduleURL(name string) string {
+	escaped, err := module.EscapePath(ToolchainModule)
+	if err != nil {
+		escaped = ToolchainModule
+	}
+	return fmt.Sprintf("%s/%s/@v/%s", s.proxyURL, escaped, name)
+}
+
+// read returns the content at rawURL, which may be a file:// URL.
+func (s *GoProxySource) read(rawURL string) ([]byte, error) {
+	if strings.HasPrefix(rawURL, "file://") {
+		path, err := FilePath(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		return os.ReadFile(path)
+	}
+
+	resp, err := newSourceClient().Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d (%s)", resp.StatusCode, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// ParseToolchainZipName recognises the toolchain zips cached by the goproxy source (v0.0.1-go<version>.<os>-<arch>.zip).
+// Returns the File metadata derivable from the name and whether the name matched.
+func ParseToolchainZipName(name string) (File, bool) {
+	version, ok := strings.CutSuffix(name, ".zip")
+	if !ok {
+		return File{}, false
+	}
+	return parseToolchainVersion(version)
+}
+
+// parseToolchainVersion converts a toolchain module version (v0.0.1-go1.22.1.linux-amd64) into archive metadata.
+// Returns false for versions that do not name a toolchain.
+func parseToolchainVersion(version string) (File, bool) {
+	matches := toolchainVersionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return File{}, false
+	}
+
+	return File{
+		Filename: version + ".zip",
+		OS:       matches[2],
+		Arch:     matches[3],
+		Version:  "go" + matches[1],
+		Kind:     "archive",
+	}, true
+}
+
+// lookupGoSum returns the h1: hash pinned for modulePath@version in a go.sum-style file, or "" if it is not listed.
+func lookupGoSum(path, modulePath, version string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read go_sum file: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == modulePath && fields[1] == version {
+			return fields[2], nil
+		}
+	}
+	return "", nil
+}
+
+// sumdbOps connects a checksum database client to the network and to the persistent sumdb directory.
+// It implements sumdb.ClientOps.
+type sumdbOps struct {
+	name      string
+	key       string
+	directURL string
+	proxyURL  string
+	dir       string
+
+	baseOnce sync.Once
+	baseURL  string
+
+	mutex       sync.Mutex
+	memoryMutex sync.Mutex
+	memory      map[string][]byte
+}
+
+// newSumdbOps parses a GOSUMDB-style setting: "", "sum.golang.org", "name+key", or "name+key url".
+// When the database is reachable through the module proxy (<proxy>/sumdb/<name>), the proxy is used.
+// State is persisted under cacheDir, or kept in memory when cacheDir is empty.
+func newSumdbOps(setting, proxyURL, cacheDir string) (*sumdbOps, error) {
+	fields := strings.Fields(setting)
+	if len(fields) == 0 {
+		fields = []string{DefaultSumDB}
+	}
+	if len(fields) > 2 {
+		return nil, fmt.Errorf("invalid go_releases.sumdb %q: expected \"name+key [url]\"", setting)
+	}
+
+	key := fields[0]
+	if key == DefaultSumDB {
+		key = sumGolangOrgKey
+	}
+	if !strings.Contains(key, "+") {
+		return nil, fmt.Errorf("unknown checksum database %q: set go_releases.sumdb to \"name+key [url]\"", key)
+	}
+
+	verifier, err := note.NewVerifier(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go_releases.sumdb key: %w", err)
+	}
+
+	ops := &sumdbOps{
+		name:      verifier.Name(),
+		key:       key,
+		directURL: "https://" + verifier.Name(),
+		dir:       cacheDir,
+		memory:    make(map[string][]byte),
+	}
+	if len(fields) == 2 {
+		ops.directURL = strings.TrimRight(fields[1], "/")
+	}
+	if strings.HasPrefix(proxyURL, "http://") || strings.HasPrefix(proxyURL, "https://") {
+		ops.proxyURL = proxyURL
+	}
+	return ops, nil
+}
+
+// base returns the URL requests to the checksum database are made against, preferring the module proxy
+// when it reports that it proxies this database.
+func (o *sumdbOps) base() string {
+	o.baseOnce.Do(func() {
+		o.baseURL = o.directURL
+		if o.proxyURL == "" {
+			return
+		}
+
+		proxied := o.proxyURL + "/sumdb/" + o.name
+		resp, err := newSourceClient().Get(proxied + "/supported")
+		if err != nil {
+			_logger.Verbose("Module proxy does not proxy %s: %v", o.name, err)
+			return
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK {
+			o.baseURL = proxied
+		}
+	})
+	return o.baseURL
+}
+
+// ReadRemote fetches path from the checksum database.
+func (o *sumdbOps) ReadRemote(path string) ([]byte, error) {
+	if IsOffline() {
+		return nil, fmt.Errorf("offline mode: cannot reach checksum database %s", o.name)
+	}
+
+	rawURL := o.base() + path
+	resp, err := newSourceClient().Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: HTTP %d (%s)", _httpclient.RedactURL(rawURL), resp.StatusCode, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// ReadConfig returns the verifier key, or the latest signed tree seen from the database (empty if none yet).
+func (o *sumdbOps) ReadConfig(file string) ([]byte, error) {
+	if file == "key" {
+		return []byte(o.key), nil
+	}
+
+	data, err := o.load("config", file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return data, nil
+}
+
+// WriteConfig replaces the stored latest signed tree if it still equals old.
+func (o *sumdbOps) WriteConfig(file string, old, new []byte) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	current, err := o.load("config", file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if !bytes.Equal(current, old) {
+		return sumdb.ErrWriteConflict
+	}
+	return o.store("config", file, new)
+}
+
+// ReadCache returns a cached lookup result or tile.
+func (o *sumdbOps) ReadCache(file string) ([]byte, error) {
+	return o.load("cache", file)
+}
+
+// WriteCache stores a lookup result or tile so later lookups, including offline ones, need no network.
+func (o *sumdbOps) WriteCache(file string, data []byte) {
+	if err := o.store("cache", file, data); err != nil {
+		_logger.Verbose("Failed to cache checksum database file %s: %v", file, err)
+	}
+}
+
+// Log prints a checksum database client message in verbose mode.
+func (o *sumdbOps) Log(msg string) {
+	_logger.Verbose("%s", msg)
+}
+
+// SecurityError reports a checksum database inconsistency; the lookup that triggered it fails.
+func (o *sumdbOps) SecurityError(msg string) {
+	_logger.Error("%s", msg)
+}
+
+// path returns the persistent location of file in kind ("config" or "cache"), or "" when persistence is disabled.
+func (o *sumdbOps) path(kind, file string) string {
+	if o.dir == "" {
+		return ""
+	}
+	return filepath.Join(o.dir, sumdbDirName, kind, filepath.FromSlash(file))
+}
+
+// load reads file from the sumdb directory, or from memory when persistence is disabled.
+func (o *sumdbOps) load(kind, file string) ([]byte, error) {
+	path := o.path(kind, file)
+	if path == "" {
+		o.memoryMutex.Lock()
+		defer o.memoryMutex.Unlock()
+		data, ok := o.memory[kind+"/"+file]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return data, nil
+	}
+	return os.ReadFile(path)
+}
+
+// store atomically writes file to the sumdb directory, or to memory when persistence is disabled.
+func (o *sumdbOps) store(kind, file string, data []byte) error {
+	path := o.path(kind, file)
+	if path == "" {
+		o.memoryMutex.Lock()
+		defer o.memoryMutex.Unlock()
+		o.memory[kind+"/"+file] = append([]byte(nil), data...)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".sumdb-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
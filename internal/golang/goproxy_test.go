@@ -0,0 +1,327 @@
+package golang
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sumdb "golang.org/x/mod/sumdb"
+	dirhash "golang.org/x/mod/sumdb/dirhash"
+	note "golang.org/x/mod/sumdb/note"
+)
+
+// buildToolchainZip returns a toolchain module zip for version and its go.sum hash
+func buildToolchainZip(t *testing.T, version string) ([]byte, string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"bin/go": "go binary", "VERSION": version} {
+		w, _ := zw.Create(ToolchainModule + "@" + version + "/" + name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+
+	path := filepath.Join(t.TempDir(), version+".zip")
+	os.WriteFile(path, buf.Bytes(), 0644)
+	hash, err := dirhash.HashZip(path, dirhash.Hash1)
+	if err != nil {
+		t.Fatalf("Failed to hash zip: %v", err)
+	}
+	return buf.Bytes(), hash
+}
+
+// newTestModuleProxy serves the toolchain module versions in zips, listing only those in listed,
+// and proxies a test checksum database named sum.test. Returns the server and the database's verifier key.
+func newTestModuleProxy(t *testing.T, zips map[string][]byte, hashes map[string]string, listed []string) (*httptest.Server, string) {
+	t.Helper()
+
+	skey, vkey, err := note.GenerateKey(rand.Reader, "sum.test")
+	if err != nil {
+		t.Fatalf("Failed to generate sumdb key: %v", err)
+	}
+	db := sumdb.NewServer(sumdb.NewTestServer(skey, func(path, vers string) ([]byte, error) {
+		hash, ok := hashes[vers]
+		if path != ToolchainModule || !ok {
+			return nil, fmt.Errorf("unknown module %s@%s", path, vers)
+		}
+		return []byte(fmt.Sprintf("%s %s %s\n%s %s/go.mod h1:AAAA\n", path, vers, hash, path, vers)), nil
+	}))
+
+	mux := http.NewServeMux()
+	mux.Handle("/sumdb/sum.test/", http.StripPrefix("/sumdb/sum.test", db))
+	mux.HandleFunc("/sumdb/sum.test/supported", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/golang.org/toolchain/@v/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/golang.org/toolchain/@v/")
+		switch {
+		case name == "list":
+			fmt.Fprintln(w, strings.Join(listed, "\n"))
+			fmt.Fprintln(w, "v0.0.1-notatoolchain")
+		case strings.HasSuffix(name, ".info") && zips[strings.TrimSuffix(name, ".info")] != nil:
+			fmt.Fprintf(w, `{"Version":%q}`, strings.TrimSuffix(name, ".info"))
+		case strings.HasSuffix(name, ".zip") && zips[strings.TrimSuffix(name, ".zip")] != nil:
+			w.Write(zips[strings.TrimSuffix(name, ".zip")])
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, vkey
+}
+
+func TestParseToolchainVersion(t *testing.T) {
+	testCases := []struct {
+		version string
+		matches bool
+		file    File
+	}{
+		{"v0.0.1-go1.22.1.linux-amd64", true, File{Filename: "v0.0.1-go1.22.1.linux-amd64.zip", OS: "linux", Arch: "amd64", Version: "go1.22.1", Kind: "archive"}},
+		{"v0.0.1-go1.23rc2.darwin-arm64", true, File{Filename: "v0.0.1-go1.23rc2.darwin-arm64.zip", OS: "darwin", Arch: "arm64", Version: "go1.23rc2", Kind: "archive"}},
+		{"v0.0.1-go1.21.0.windows-386", true, File{Filename: "v0.0.1-go1.21.0.windows-386.zip", OS: "windows", Arch: "386", Version: "go1.21.0", Kind: "archive"}},
+		{"v0.0.1-notatoolchain", false, File{}},
+		{"v0.0.2-go1.22.1.linux-amd64", false, File{}},
+		{"", false, File{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			file, ok := parseToolchainVersion(tc.version)
+			if ok != tc.matches {
+				t.Fatalf("Expected match=%v, got %v", tc.matches, ok)
+			}
+			if file != tc.file {
+				t.Errorf("Expected %+v, got %+v", tc.file, file)
+			}
+		})
+	}
+}
+
+func TestParseToolchainZipName(t *testing.T) {
+	testCases := []struct {
+		name    string
+		matches bool
+		version string
+	}{
+		{"v0.0.1-go1.22.1.linux-amd64.zip", true, "go1.22.1"},
+		{"v0.0.1-go1.22.1.linux-amd64", false, ""},
+		{"v0.0.1-go1.22.1.linux-amd64.zip.asc", false, ""},
+		{"go1.22.1.linux-amd64.zip", false, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, ok := ParseToolchainZipName(tc.name)
+			if ok != tc.matches {
+				t.Fatalf("Expected match=%v, got %v", tc.matches, ok)
+			}
+			if ok && (file.Filename != tc.name || file.Version != tc.version) {
+				t.Errorf("Unexpected file: %+v", file)
+			}
+		})
+	}
+}
+
+func TestGoProxySource(t *testing.T) {
+	SetIndexDir(t.TempDir())
+	defer SetIndexDir("")
+	ClearReleasesCache()
+	defer ClearReleasesCache()
+
+	zips := map[string][]byte{}
+	hashes := map[string]string{}
+	for _, version := range []string{"v0.0.1-go1.22.0.linux-amd64", "v0.0.1-go1.22.1.linux-amd64", "v0.0.1-go1.23rc1.linux-amd64"} {
+		zips[version], hashes[version] = buildToolchainZip(t, version)
+	}
+	server, vkey := newTestModuleProxy(t, zips, hashes, []string{"v0.0.1-go1.22.0.linux-amd64", "v0.0.1-go1.23rc1.linux-amd64"})
+
+	cacheDir := t.TempDir()
+	src, err := NewGoProxySource(server.URL+"/", "", vkey, cacheDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("Lists toolchain versions", func(t *testing.T) {
+		versions, err := GetAvailableVersionsFromSource(src, true, time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fmt.Sprint(versions) != "[1.23rc1 1.22.0]" {
+			t.Errorf("Unexpected versions: %v", versions)
+		}
+	})
+
+	t.Run("Resolves hash from the checksum database", func(t *testing.T) {
+		file, err := GetPlatformFileInfoFromSource(src, "1.22.0", "linux", "amd64", time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		version := "v0.0.1-go1.22.0.linux-amd64"
+		if file.ModuleHash != hashes[version] || file.Size != int64(len(zips[version])) {
+			t.Errorf("Expected hash %s and size %d, got %s and %d", hashes[version], len(zips[version]), file.ModuleHash, file.Size)
+		}
+		if src.DownloadURL(*file) != server.URL+"/golang.org/toolchain/@v/"+version+".zip" {
+			t.Errorf("Unexpected download URL %s", src.DownloadURL(*file))
+		}
+		if _, err := os.Stat(filepath.Join(cacheDir, sumdbDirName, "config")); err != nil {
+			t.Errorf("Expected checksum database state in the cache directory: %v", err)
+		}
+	})
+
+	t.Run("Finds versions missing from the list", func(t *testing.T) {
+		file, err := GetPlatformFileInfoFromSource(src, "1.22.1", "linux", "amd64", time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if file.ModuleHash != hashes["v0.0.1-go1.22.1.linux-amd64"] {
+			t.Errorf("Unexpected hash %s", file.ModuleHash)
+		}
+	})
+
+	t.Run("Unknown version", func(t *testing.T) {
+		if _, err := GetPlatformFileInfoFromSource(src, "1.19.0", "linux", "amd64", time.Minute); err == nil {
+			t.Error("Expected error for a version the proxy does not serve")
+		}
+	})
+
+	t.Run("Offline uses module zips in the cache directory", func(t *testing.T) {
+		version := "v0.0.1-go1.22.1.linux-amd64"
+		os.WriteFile(filepath.Join(cacheDir, version+".zip"), zips[version], 0644)
+
+		SetOffline(true)
+		defer SetOffline(false)
+
+		if file := src.probeFile("1.22.1", "linux", "amd64"); file == nil {
+			t.Error("Expected cached module zip to be found offline")
+		}
+		if file := src.probeFile("1.23rc1", "linux", "amd64"); file != nil {
+			t.Error("Expected uncached module zip not to be found offline")
+		}
+		size, err := src.zipSize(File{Filename: version + ".zip"})
+		if err != nil || size != int64(len(zips[version])) {
+			t.Errorf("Expected size %d of the cached zip, got %d (%v)", len(zips[version]), size, err)
+		}
+	})
+
+	t.Run("Pinned go.sum without checksum database", func(t *testing.T) {
+		goSum := filepath.Join(t.TempDir(), "go.sum")
+		os.WriteFile(goSum, []byte(ToolchainModule+" v0.0.1-go1.22.0.linux-amd64 h1:pinned=\n"), 0644)
+
+		pinned, err := NewGoProxySource(server.URL, goSum, "off", "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		file, err := GetPlatformFileInfoFromSource(pinned, "1.22.0", "linux", "amd64", time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if file.ModuleHash != "h1:pinned=" {
+			t.Errorf("Expected pinned hash, got %s", file.ModuleHash)
+		}
+
+		if _, err := GetPlatformFileInfoFromSource(pinned, "1.23rc1", "linux", "amd64", time.Minute); err == nil {
+			t.Error("Expected error for a version that is not pinned while the checksum database is off")
+		}
+	})
+}
+
+func TestNewGoProxySource(t *testing.T) {
+	t.Setenv("GOPROXY", "direct")
+
+	testCases := []struct {
+		name        string
+		proxy       string
+		goSum       string
+		sumdb       string
+		expectError bool
+	}{
+		{name: "Default checksum database", proxy: "https://proxy.golang.org"},
+		{name: "Checksum database with URL", proxy: "https://athens.corp", sumdb: sumGolangOrgKey + " https://sum.corp"},
+		{name: "Unknown checksum database name", sumdb: "sum.corp", expectError: true},
+		{name: "Invalid key", sumdb: "sum.corp+abc+def", expectError: true},
+		{name: "Checksum database off without go.sum", sumdb: "off", expectError: true},
+		{name: "Checksum database off with go.sum", sumdb: "off", goSum: "/etc/govman/go.sum"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := NewGoProxySource(tc.proxy, tc.goSum, tc.sumdb, "")
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.proxy == "" && src.proxyURL != defaultGoProxy {
+				t.Errorf("Expected default proxy, got %s", src.proxyURL)
+			}
+		})
+	}
+
+	t.Run("Proxy from GOPROXY", func(t *testing.T) {
+		t.Setenv("GOPROXY", "off,https://athens.corp|direct")
+		src, _ := NewGoProxySource("", "", "", "")
+		if src.proxyURL != "https://athens.corp" {
+			t.Errorf("Expected proxy from GOPROXY, got %s", src.proxyURL)
+		}
+	})
+}
+
+func TestGoProxySource_FileProxyOffline(t *testing.T) {
+	SetIndexDir(t.TempDir())
+	defer SetIndexDir("")
+	ClearReleasesCache()
+	defer ClearReleasesCache()
+
+	proxyDir := t.TempDir()
+	moduleDir := filepath.Join(proxyDir, "golang.org", "toolchain", "@v")
+	os.MkdirAll(moduleDir, 0755)
+
+	version := "v0.0.1-go1.22.1.linux-amd64"
+	zipData, hash := buildToolchainZip(t, version)
+	os.WriteFile(filepath.Join(moduleDir, "list"), []byte(version+"\n"), 0644)
+	os.WriteFile(filepath.Join(moduleDir, version+".info"), []byte(`{"Version":"`+version+`"}`), 0644)
+	os.WriteFile(filepath.Join(moduleDir, version+".zip"), zipData, 0644)
+
+	goSum := filepath.Join(t.TempDir(), "go.sum")
+	os.WriteFile(goSum, []byte(ToolchainModule+" "+version+" "+hash+"\n"), 0644)
+
+	src, err := NewGoProxySource("file://"+filepath.ToSlash(proxyDir), goSum, "off", t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !src.isLocal() {
+		t.Fatal("Expected a file:// proxy to be local")
+	}
+
+	SetOffline(true)
+	defer SetOffline(false)
+
+	versions, err := GetAvailableVersionsFromSource(src, true, time.Minute)
+	if err != nil {
+		t.Fatalf("Expected a file:// proxy to be listed offline without a cached index: %v", err)
+	}
+	if fmt.Sprint(versions) != "[1.22.1]" {
+		t.Errorf("Unexpected versions: %v", versions)
+	}
+
+	file, err := GetPlatformFileInfoFromSource(src, "1.22.1", "linux", "amd64", time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if file.ModuleHash != hash || file.Size != int64(len(zipData)) {
+		t.Errorf("Expected hash %s and size %d, got %s and %d", hash, len(zipData), file.ModuleHash, file.Size)
+	}
+}
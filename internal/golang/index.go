@@ -0,0 +1,196 @@
+package golang
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const indexDirName = "index"
+
+// ErrSeedRequiresSignature is returned by SeedIndex when the release source only accepts signed indexes.
+var ErrSeedRequiresSignature = errors.New("go_releases.require_signature is enabled, so an unsigned seeded release index would be ignored")
+
+var (
+	indexDir   string
+	indexMutex sync.RWMutex
+)
+
+// ReleaseIndex is a fetched release list together with the source it came from
+// and the validators needed to revalidate it with a conditional GET.
+type ReleaseIndex struct {
+	Source       string    `json:"source"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Signed       bool      `json:"signed,omitempty"`
+	Releases     []Release `json:"releases"`
+}
+
+// SetIndexDir sets the directory where release indexes are persisted between runs.
+// An empty dir disables persistence and keeps the index in memory only.
+func SetIndexDir(dir string) {
+	indexMutex.Lock()
+	indexDir = dir
+	indexMutex.Unlock()
+}
+
+// getIndexDir returns the configured index directory, or an empty string when persistence is disabled.
+func getIndexDir() string {
+	indexMutex.RLock()
+	defer indexMutex.RUnlock()
+	return indexDir
+}
+
+// indexPath returns the path of the persisted index for sourceID, or an empty string when persistence is disabled.
+// Each source gets its own file so switching sources never serves another source's releases.
+func indexPath(sourceID string) string {
+	dir := getIndexDir()
+	if dir == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(sourceID))
+	return filepath.Join(dir, indexDirName, hex.EncodeToString(sum[:8])+".json")
+}
+
+// isFresh reports whether the index was fetched within maxAge.
+func (idx *ReleaseIndex) isFresh(maxAge time.Duration) bool {
+	return time.Since(idx.FetchedAt) < maxAge
+}
+
+// loadIndex reads the persisted release index for sourceID.
+// Returns nil when persistence is disabled or the file is missing, unreadable, or belongs to another source.
+func loadIndex(sourceID string) *ReleaseIndex {
+	path := indexPath(sourceID)
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var idx ReleaseIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil
+	}
+
+	if idx.Source != sourceID || idx.Releases == nil {
+		return nil
+	}
+
+	return &idx
+}
+
+// saveIndex atomically writes the release index into the index directory.
+// Returns nil without writing when persistence is disabled.
+func saveIndex(idx *ReleaseIndex) error {
+	path := indexPath(idx.Source)
+	if path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to encode release index: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "index-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary index file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write release index: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close release index: %w", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("failed to save release index: %w", err)
+	}
+
+	return nil
+}
+
+// SeedIndex merges releases into the persisted index of src and marks it as freshly fetched,
+// so later lookups against src are served from it without network access (e.g. after a bundle import).
+// Files are merged per release: seeded files replace indexed files of the same name, and other files are kept.
+// Returns an error if persistence is disabled, src requires a signed index (the seeded index is unsigned
+// and would be ignored), or the index cannot be written.
+func SeedIndex(src ReleaseSource, releases []Release) error {
+	sourceID := src.ID()
+	if indexPath(sourceID) == "" {
+		return fmt.Errorf("release index persistence is not configured")
+	}
+	if RequiresSignature(src) {
+		return ErrSeedRequiresSignature
+	}
+
+	merged := make(map[string]Release)
+	if existing := loadIndex(sourceID); existing != nil {
+		for _, release := range existing.Releases {
+			merged[release.Version] = release
+		}
+	}
+	for _, release := range releases {
+		if existing, ok := merged[release.Version]; ok {
+			release.Files = mergeFiles(existing.Files, release.Files)
+		}
+		merged[release.Version] = release
+	}
+
+	idx := &ReleaseIndex{
+		Source:    sourceID,
+		FetchedAt: time.Now(),
+		Releases:  make([]Release, 0, len(merged)),
+	}
+	for _, release := range merged {
+		idx.Releases = append(idx.Releases, release)
+	}
+	sort.Slice(idx.Releases, func(i, j int) bool {
+		return CompareVersions(idx.Releases[i].Version, idx.Releases[j].Version) > 0
+	})
+
+	if err := saveIndex(idx); err != nil {
+		return err
+	}
+
+	cacheMutex.Lock()
+	delete(releasesCache, sourceID)
+	cacheMutex.Unlock()
+
+	return nil
+}
+
+// mergeFiles returns existing with the entries of seeded added, replacing files of the same name.
+func mergeFiles(existing, seeded []File) []File {
+	files := make([]File, 0, len(existing)+len(seeded))
+	seen := make(map[string]bool, len(seeded))
+	for _, file := range seeded {
+		seen[file.Filename] = true
+	}
+	for _, file := range existing {
+		if !seen[file.Filename] {
+			files = append(files, file)
+		}
+	}
+	return append(files, seeded...)
+}
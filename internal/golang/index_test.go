@@ -0,0 +1,333 @@
+package golang
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func useTempIndexDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	SetIndexDir(dir)
+	ClearReleasesCache()
+	t.Cleanup(func() {
+		SetIndexDir("")
+		ClearReleasesCache()
+	})
+	return dir
+}
+
+func TestSaveAndLoadIndex(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		idx := &ReleaseIndex{
+			Source:    "godev:https://example.com/dl",
+			FetchedAt: time.Now(),
+			ETag:      `"abc"`,
+			Releases:  []Release{{Version: "go1.21.0", Stable: true}},
+		}
+		if err := saveIndex(idx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		loaded := loadIndex("godev:https://example.com/dl")
+		if loaded == nil {
+			t.Fatal("Expected index to be loaded")
+		}
+		if loaded.ETag != `"abc"` || len(loaded.Releases) != 1 {
+			t.Errorf("Loaded index does not match saved index: %+v", loaded)
+		}
+	})
+
+	t.Run("Indexes are kept per source", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		a := &ReleaseIndex{Source: "godev:https://a.example.com", FetchedAt: time.Now(), Releases: []Release{{Version: "go1.21.0"}}}
+		b := &ReleaseIndex{Source: "godev:https://b.example.com", FetchedAt: time.Now(), Releases: []Release{{Version: "go1.22.0"}}}
+		for _, idx := range []*ReleaseIndex{a, b} {
+			if err := saveIndex(idx); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		loaded := loadIndex(a.Source)
+		if loaded == nil || loaded.Releases[0].Version != "go1.21.0" {
+			t.Errorf("Expected index of source a, got %+v", loaded)
+		}
+		if loadIndex("godev:https://c.example.com") != nil {
+			t.Error("Expected no index for an unknown source")
+		}
+	})
+
+	t.Run("Corrupt file is ignored", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		path := indexPath("godev:https://example.com")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create index directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+			t.Fatalf("Failed to write index: %v", err)
+		}
+
+		if loadIndex("godev:https://example.com") != nil {
+			t.Error("Expected corrupt index to be ignored")
+		}
+	})
+
+	t.Run("Signature required", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		pub, _ := generateTestKey(t)
+		verifier, err := NewIndexVerifier([]string{EncodePublicKey(pub)}, true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		src := NewJSONSource("https://example.com/dl", defaultGoDownloadURL)
+		src.verifier = verifier
+
+		if err := SeedIndex(src, []Release{{Version: "go1.22.0", Stable: true}}); !errors.Is(err, ErrSeedRequiresSignature) {
+			t.Errorf("Expected ErrSeedRequiresSignature, got %v", err)
+		}
+		if loadIndex(src.ID()) != nil {
+			t.Error("Expected no index to be written")
+		}
+	})
+
+	t.Run("Persistence disabled", func(t *testing.T) {
+		SetIndexDir("")
+
+		if err := saveIndex(&ReleaseIndex{Source: "x"}); err != nil {
+			t.Errorf("Expected no error when persistence is disabled, got %v", err)
+		}
+		if loadIndex("x") != nil {
+			t.Error("Expected nil index when persistence is disabled")
+		}
+	})
+}
+
+func TestFetchReleasesPersistentIndex(t *testing.T) {
+	t.Run("Fresh index avoids network", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		var hits int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			json.NewEncoder(w).Encode([]Release{{Version: "go1.21.0", Stable: true}})
+		}))
+		defer server.Close()
+
+		if _, err := fetchReleasesWithConfig(server.URL, time.Hour); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Simulate a new process: memory cache is empty, disk index remains
+		ClearReleasesCache()
+
+		releases, err := fetchReleasesWithConfig(server.URL, time.Hour)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(releases) != 1 {
+			t.Errorf("Expected 1 release, got %d", len(releases))
+		}
+		if atomic.LoadInt32(&hits) != 1 {
+			t.Errorf("Expected 1 network request, got %d", hits)
+		}
+	})
+
+	t.Run("Expired index is revalidated", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		var conditional int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				atomic.AddInt32(&conditional, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			json.NewEncoder(w).Encode([]Release{{Version: "go1.21.0", Stable: true}})
+		}))
+		defer server.Close()
+
+		if _, err := fetchReleasesWithConfig(server.URL, time.Millisecond); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+		ClearReleasesCache()
+
+		releases, err := fetchReleasesWithConfig(server.URL, time.Millisecond)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(releases) != 1 {
+			t.Errorf("Expected cached release after 304, got %d", len(releases))
+		}
+		if atomic.LoadInt32(&conditional) != 1 {
+			t.Errorf("Expected 1 conditional request, got %d", conditional)
+		}
+
+		idx := loadIndex(NewJSONSource(server.URL, defaultGoDownloadURL).ID())
+		if idx == nil || time.Since(idx.FetchedAt) > time.Second {
+			t.Error("Expected fetch time to be refreshed after 304")
+		}
+	})
+
+	t.Run("Stale index used when network is down", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		server := createMockServer([]Release{{Version: "go1.21.0", Stable: true}}, http.StatusOK)
+		url := server.URL
+
+		if _, err := fetchReleasesWithConfig(url, time.Millisecond); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		server.Close()
+		time.Sleep(2 * time.Millisecond)
+		ClearReleasesCache()
+
+		releases, err := fetchReleasesWithConfig(url, time.Millisecond)
+		if err != nil {
+			t.Fatalf("Expected stale fallback, got error: %v", err)
+		}
+		if len(releases) != 1 {
+			t.Errorf("Expected 1 stale release, got %d", len(releases))
+		}
+	})
+
+	t.Run("No index and network down", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		server := createMockServer(nil, http.StatusOK)
+		url := server.URL
+		server.Close()
+
+		if _, err := fetchReleasesWithConfig(url, time.Minute); err == nil {
+			t.Error("Expected error without a cached index")
+		}
+	})
+}
+
+func TestFetchReleasesOffline(t *testing.T) {
+	SetOffline(true)
+	defer SetOffline(false)
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		json.NewEncoder(w).Encode([]Release{{Version: "go1.22.0", Stable: true}})
+	}))
+	defer server.Close()
+
+	t.Run("Stale index is used without revalidation", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		idx := &ReleaseIndex{
+			Source:    NewJSONSource(server.URL, defaultGoDownloadURL).ID(),
+			FetchedAt: time.Now().Add(-24 * time.Hour),
+			Releases:  []Release{{Version: "go1.21.0", Stable: true}},
+		}
+		if err := saveIndex(idx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		releases, err := fetchReleasesWithConfig(server.URL, time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(releases) != 1 || releases[0].Version != "go1.21.0" {
+			t.Errorf("Expected cached release, got %+v", releases)
+		}
+	})
+
+	t.Run("Missing index", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		_, err := fetchReleasesWithConfig(server.URL, time.Minute)
+		if err == nil || !strings.Contains(err.Error(), "offline mode") {
+			t.Errorf("Expected offline mode error, got %v", err)
+		}
+	})
+
+	if atomic.LoadInt32(&hits) != 0 {
+		t.Errorf("Expected no network requests in offline mode, got %d", hits)
+	}
+}
+
+func TestSeedIndex(t *testing.T) {
+	t.Run("Merges into existing index and serves offline", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		src := NewJSONSource("https://example.com/dl", defaultGoDownloadURL)
+		existing := &ReleaseIndex{
+			Source:    src.ID(),
+			FetchedAt: time.Now().Add(-time.Hour),
+			Releases:  []Release{{Version: "go1.20.0", Stable: true}, {Version: "go1.21.0", Stable: true}},
+		}
+		if err := saveIndex(existing); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		existing.Releases[1].Files = []File{{Filename: "go1.21.0.darwin-arm64.tar.gz"}, {Filename: "go1.21.0.linux-amd64.tar.gz", Size: 1}}
+		if err := saveIndex(existing); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		seeded := []Release{{Version: "go1.22.0", Stable: true}, {Version: "go1.21.0", Stable: true, Files: []File{{Filename: "go1.21.0.linux-amd64.tar.gz", Size: 2}}}}
+		if err := SeedIndex(src, seeded); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		SetOffline(true)
+		defer SetOffline(false)
+
+		releases, err := fetchReleases(src, time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(releases) != 3 || releases[0].Version != "go1.22.0" || releases[2].Version != "go1.20.0" {
+			t.Fatalf("Expected merged releases newest first, got %+v", releases)
+		}
+		files := releases[1].Files
+		if len(files) != 2 || files[0].Filename != "go1.21.0.darwin-arm64.tar.gz" || files[1].Size != 2 {
+			t.Errorf("Expected seeded files merged into the existing release, got %+v", files)
+		}
+	})
+
+	t.Run("Signature required", func(t *testing.T) {
+		useTempIndexDir(t)
+
+		pub, _ := generateTestKey(t)
+		verifier, err := NewIndexVerifier([]string{EncodePublicKey(pub)}, true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		src := NewJSONSource("https://example.com/dl", defaultGoDownloadURL)
+		src.verifier = verifier
+
+		if err := SeedIndex(src, []Release{{Version: "go1.22.0", Stable: true}}); !errors.Is(err, ErrSeedRequiresSignature) {
+			t.Errorf("Expected ErrSeedRequiresSignature, got %v", err)
+		}
+		if loadIndex(src.ID()) != nil {
+			t.Error("Expected no index to be written")
+		}
+	})
+
+	t.Run("Persistence disabled", func(t *testing.T) {
+		SetIndexDir("")
+
+		if err := SeedIndex(NewJSONSource("https://example.com/dl", defaultGoDownloadURL), nil); err == nil {
+			t.Error("Expected error when persistence is disabled")
+		}
+	})
+}
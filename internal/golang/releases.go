@@ -1,10 +1,7 @@
 package golang
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -13,17 +10,28 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	_logger "github.com/sijunda/govman/internal/logger"
 )
 
 var (
-	releasesCache []Release
+	releasesCache map[string]cachedReleases
 	cacheMutex    sync.RWMutex
-	cacheExpiry   time.Time
+	offlineMode   atomic.Bool
 )
 
+// cachedReleases is an in-memory cache entry for a single release source.
+type cachedReleases struct {
+	releases []Release
+	expiry   time.Time
+}
+
 const (
 	GoDownloadURLTemplate = "%s"
+	// ArchiveSignatureSuffix is appended to an archive URL to find its detached OpenPGP signature.
+	ArchiveSignatureSuffix = ".asc"
 )
 
 var (
@@ -46,6 +54,9 @@ type File struct {
 	Sha256   string `json:"sha256"`
 	Size     int64  `json:"size"`
 	Kind     string `json:"kind"`
+	URL      string `json:"url,omitempty"`
+	// ModuleHash is the go.sum hash (h1:...) of a toolchain module zip; such files have no Sha256.
+	ModuleHash string `json:"module_hash,omitempty"`
 }
 
 type VersionInfo struct {
@@ -66,7 +77,13 @@ func GetAvailableVersions(includeUnstable bool) ([]string, error) {
 // GetAvailableVersionsWithConfig fetches available versions using a specific API URL and cache duration.
 // Parameters: includeUnstable, apiURL, cacheDuration. Returns a sorted slice of version strings or an error.
 func GetAvailableVersionsWithConfig(includeUnstable bool, apiURL string, cacheDuration time.Duration) ([]string, error) {
-	releases, err := fetchReleasesWithConfig(apiURL, cacheDuration)
+	return GetAvailableVersionsFromSource(newAutoSource(apiURL, defaultGoDownloadURL), includeUnstable, cacheDuration)
+}
+
+// GetAvailableVersionsFromSource fetches available versions from a release source.
+// Parameters: src, includeUnstable, cacheDuration. Returns a sorted slice of version strings or an error.
+func GetAvailableVersionsFromSource(src ReleaseSource, includeUnstable bool, cacheDuration time.Duration) ([]string, error) {
+	releases, err := fetchReleases(src, cacheDuration)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +105,12 @@ func GetAvailableVersionsWithConfig(includeUnstable bool, apiURL string, cacheDu
 	return versions, nil
 }
 
+// GetReleasesFromSource returns the full release list of a release source, served from cache when possible.
+// Parameters: src, cacheDuration. Returns []Release or an error.
+func GetReleasesFromSource(src ReleaseSource, cacheDuration time.Duration) ([]Release, error) {
+	return fetchReleases(src, cacheDuration)
+}
+
 // GetDownloadURL returns the archive download URL for a given version using default endpoints.
 // Parameter version is the version string. Returns the URL or an error if unavailable for the platform.
 func GetDownloadURL(version string) (string, error) {
@@ -97,30 +120,23 @@ func GetDownloadURL(version string) (string, error) {
 // GetDownloadURLWithConfig computes the archive download URL using custom API and URL template.
 // Parameters: version, apiURL, cacheDuration, downloadURL (format string). Returns URL or error.
 func GetDownloadURLWithConfig(version string, apiURL string, cacheDuration time.Duration, downloadURL string) (string, error) {
-	releases, err := fetchReleasesWithConfig(apiURL, cacheDuration)
+	return GetDownloadURLFromSource(newAutoSource(apiURL, downloadURL), version, cacheDuration)
+}
+
+// GetDownloadURLFromSource returns the current platform's archive URL for a version from a release source.
+// Parameters: src, version, cacheDuration. Returns URL or error.
+func GetDownloadURLFromSource(src ReleaseSource, version string, cacheDuration time.Duration) (string, error) {
+	releases, err := fetchReleases(src, cacheDuration)
 	if err != nil {
 		return "", err
 	}
 
-	targetVersion := "go" + version
-	goos := runtime.GOOS
-	goarch := runtime.GOARCH
-
-	resolvedArch := resolveArch(version, goos, goarch)
-
-	for _, release := range releases {
-		if release.Version != targetVersion {
-			continue
-		}
-
-		for _, file := range release.Files {
-			if file.OS == goos && file.Arch == resolvedArch && file.Kind == "archive" {
-				return fmt.Sprintf(downloadURL, file.Filename), nil
-			}
-		}
+	file := findSourceArchive(src, releases, version, runtime.GOOS, runtime.GOARCH)
+	if file == nil {
+		return "", fmt.Errorf("no download available for Go %s on %s/%s", version, runtime.GOOS, runtime.GOARCH)
 	}
 
-	return "", fmt.Errorf("no download available for Go %s on %s/%s", version, goos, goarch)
+	return src.DownloadURL(*file), nil
 }
 
 // resolveArch determines the appropriate architecture for downloads (e.g., maps darwin/arm64 to amd64 pre-1.16).
@@ -144,15 +160,43 @@ func GetFileInfo(version string) (*File, error) {
 // GetFileInfoWithConfig returns archive metadata using a specific API URL and cache duration.
 // Parameters: version, apiURL, cacheDuration. Returns *File or an error.
 func GetFileInfoWithConfig(version string, apiURL string, cacheDuration time.Duration) (*File, error) {
-	releases, err := fetchReleasesWithConfig(apiURL, cacheDuration)
+	return GetFileInfoFromSource(newAutoSource(apiURL, defaultGoDownloadURL), version, cacheDuration)
+}
+
+// GetFileInfoFromSource returns the current platform's archive metadata for a version from a release source.
+// Parameters: src, version, cacheDuration. Returns *File or an error.
+func GetFileInfoFromSource(src ReleaseSource, version string, cacheDuration time.Duration) (*File, error) {
+	return GetPlatformFileInfoFromSource(src, version, runtime.GOOS, runtime.GOARCH, cacheDuration)
+}
+
+// GetPlatformFileInfoFromSource returns archive metadata for a version on goos/goarch from a release source.
+// Parameters: src, version, goos, goarch, cacheDuration. Returns *File or an error.
+func GetPlatformFileInfoFromSource(src ReleaseSource, version, goos, goarch string, cacheDuration time.Duration) (*File, error) {
+	releases, err := fetchReleases(src, cacheDuration)
 	if err != nil {
 		return nil, err
 	}
 
-	targetVersion := "go" + version
-	goos := runtime.GOOS
-	goarch := runtime.GOARCH
+	file := findSourceArchive(src, releases, version, goos, goarch)
+	if file == nil {
+		return nil, fmt.Errorf("no file info available for Go %s on %s/%s", version, goos, goarch)
+	}
+
+	if resolver, ok := src.(fileResolver); ok {
+		resolved, err := resolver.resolveFile(*file)
+		if err != nil {
+			return nil, err
+		}
+		return &resolved, nil
+	}
+
+	return file, nil
+}
 
+// FindArchive locates the archive for version (without the "go" prefix) on goos/goarch in releases.
+// Returns nil if the release or a matching archive does not exist.
+func FindArchive(releases []Release, version, goos, goarch string) *File {
+	targetVersion := "go" + version
 	resolvedArch := resolveArch(version, goos, goarch)
 
 	for _, release := range releases {
@@ -162,12 +206,25 @@ func GetFileInfoWithConfig(version string, apiURL string, cacheDuration time.Dur
 
 		for _, file := range release.Files {
 			if file.OS == goos && file.Arch == resolvedArch && file.Kind == "archive" {
-				return &file, nil
+				return &file
 			}
 		}
 	}
 
-	return nil, fmt.Errorf("no file info available for Go %s on %s/%s", version, goos, goarch)
+	return nil
+}
+
+// findSourceArchive locates the archive for version on goos/goarch in releases, falling back to asking the
+// source directly when it can look up files its release list omits. Returns nil if no archive exists.
+func findSourceArchive(src ReleaseSource, releases []Release, version, goos, goarch string) *File {
+	if file := FindArchive(releases, version, goos, goarch); file != nil {
+		return file
+	}
+
+	if prober, ok := src.(fileProber); ok {
+		return prober.probeFile(version, goos, resolveArch(version, goos, goarch))
+	}
+	return nil
 }
 
 // GetVersionInfo collects local installation details (version, path, OS/arch, install date, size).
@@ -348,46 +405,80 @@ func extractPrereleaseNumber(prerelease string) int {
 	return 0
 }
 
-// fetchReleasesWithConfig fetches releases JSON, caches results with expiry, and returns parsed data.
+// fetchReleasesWithConfig fetches releases for apiURL using an automatically selected source.
 // Parameters: apiURL, cacheDuration. Returns []Release or an error.
 func fetchReleasesWithConfig(apiURL string, cacheDuration time.Duration) ([]Release, error) {
+	return fetchReleases(newAutoSource(apiURL, defaultGoDownloadURL), cacheDuration)
+}
+
+// fetchReleases returns releases for src from the in-memory cache, the persisted index, or the source itself.
+// A persisted index older than cacheDuration is revalidated; if the source is unreachable the stale copy is
+// used with a warning. In offline mode only local sources are read and network sources are served from cache.
+// Parameters: src, cacheDuration. Returns []Release or an error.
+func fetchReleases(src ReleaseSource, cacheDuration time.Duration) ([]Release, error) {
+	sourceID := src.ID()
+
 	cacheMutex.RLock()
-	if time.Now().Before(cacheExpiry) && releasesCache != nil {
+	if entry, ok := releasesCache[sourceID]; ok && time.Now().Before(entry.expiry) {
 		defer cacheMutex.RUnlock()
-		return releasesCache, nil
+		return entry.releases, nil
 	}
 	cacheMutex.RUnlock()
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	local, ok := src.(localSource)
+	isLocal := ok && local.isLocal()
+
+	cached := loadIndex(sourceID)
+	if cached != nil && !cached.Signed && RequiresSignature(src) {
+		// Indexes cached before signatures were enforced cannot be trusted
+		cached = nil
 	}
 
-	resp, err := client.Get(apiURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	if IsOffline() && !isLocal {
+		if cached == nil {
+			return nil, fmt.Errorf("offline mode: no cached release index for %s - run 'govman download <version>' or 'govman list --remote' while online to prefetch it", sourceID)
+		}
+
+		storeReleasesCache(sourceID, cached.Releases, cacheDuration)
+		return cached.Releases, nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch releases: HTTP %d (%s)", resp.StatusCode, resp.Status)
+	if cached != nil && cached.isFresh(cacheDuration) {
+		storeReleasesCache(sourceID, cached.Releases, cacheDuration)
+		return cached.Releases, nil
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	idx, err := src.Fetch(cached)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		if cached == nil {
+			return nil, err
+		}
+
+		_logger.Warning("Using cached release index from %s: %v", cached.FetchedAt.Format("2006-01-02 15:04"), err)
+		storeReleasesCache(sourceID, cached.Releases, cacheDuration)
+		return cached.Releases, nil
 	}
+	idx.Source = sourceID
 
-	var releases []Release
-	if err := json.Unmarshal(body, &releases); err != nil {
-		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	if err := saveIndex(idx); err != nil {
+		_logger.Verbose("Failed to persist release index: %v", err)
 	}
 
+	storeReleasesCache(sourceID, idx.Releases, cacheDuration)
+	return idx.Releases, nil
+}
+
+// storeReleasesCache stores releases for sourceID in the in-memory cache for cacheDuration.
+func storeReleasesCache(sourceID string, releases []Release, cacheDuration time.Duration) {
 	cacheMutex.Lock()
-	releasesCache = releases
-	cacheExpiry = time.Now().Add(cacheDuration)
+	if releasesCache == nil {
+		releasesCache = map[string]cachedReleases{}
+	}
+	releasesCache[sourceID] = cachedReleases{
+		releases: releases,
+		expiry:   time.Now().Add(cacheDuration),
+	}
 	cacheMutex.Unlock()
-
-	return releases, nil
 }
 
 // getDirSize walks a directory and sums file sizes.
@@ -408,10 +499,20 @@ func getDirSize(path string) (int64, error) {
 	return size, err
 }
 
-// ClearReleasesCache clears the in-memory releases cache and resets its expiry time.
+// SetOffline enables or disables offline mode. When enabled, releases are served only from the
+// persisted index and no network requests are made.
+func SetOffline(offline bool) {
+	offlineMode.Store(offline)
+}
+
+// IsOffline reports whether offline mode is enabled.
+func IsOffline() bool {
+	return offlineMode.Load()
+}
+
+// ClearReleasesCache clears the in-memory releases cache for all sources.
 func ClearReleasesCache() {
 	cacheMutex.Lock()
 	releasesCache = nil
-	cacheExpiry = time.Time{}
 	cacheMutex.Unlock()
 }
@@ -1196,15 +1196,11 @@ func TestClearReleasesCache(t *testing.T) {
 				// Verify cache is cleared
 				cacheMutex.RLock()
 				isEmpty := releasesCache == nil
-				expiryZero := cacheExpiry.IsZero()
 				cacheMutex.RUnlock()
 
 				if !isEmpty {
 					t.Error("Cache should be nil after clear")
 				}
-				if !expiryZero {
-					t.Error("Cache expiry should be zero after clear")
-				}
 			},
 		},
 		{
@@ -1543,3 +1539,32 @@ func createMockServer(releases []Release, statusCode int) *httptest.Server {
 		json.NewEncoder(w).Encode(releases)
 	}))
 }
+
+func TestGetPlatformFileInfoFromSource(t *testing.T) {
+	ClearReleasesCache()
+	defer ClearReleasesCache()
+
+	server := createMockServer([]Release{{
+		Version: "go1.21.0",
+		Stable:  true,
+		Files: []File{
+			{Filename: "go1.21.0.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Kind: "archive"},
+			{Filename: "go1.21.0.windows-arm64.zip", OS: "windows", Arch: "arm64", Kind: "archive"},
+		},
+	}}, http.StatusOK)
+	defer server.Close()
+
+	src := NewJSONSource(server.URL, defaultGoDownloadURL)
+
+	file, err := GetPlatformFileInfoFromSource(src, "1.21.0", "windows", "arm64", time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if file.Filename != "go1.21.0.windows-arm64.zip" {
+		t.Errorf("Expected windows/arm64 archive, got %s", file.Filename)
+	}
+
+	if _, err := GetPlatformFileInfoFromSource(src, "1.21.0", "plan9", "386", time.Minute); err == nil {
+		t.Error("Expected error for unavailable platform")
+	}
+}
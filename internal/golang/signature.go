@@ -0,0 +1,199 @@
+package golang
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	_logger "github.com/sijunda/govman/internal/logger"
+)
+
+// SignatureSuffix is appended to an index location to find its detached signature (index.json.sig).
+const SignatureSuffix = ".sig"
+
+const signatureAlgorithm = "ed25519"
+
+// IndexSignature is the detached signature file published next to a signed release index.
+type IndexSignature struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id"`
+	Signature string `json:"signature"`
+}
+
+// IndexVerifier checks release index signatures against a set of trusted ed25519 public keys.
+type IndexVerifier struct {
+	keys    map[string]ed25519.PublicKey
+	require bool
+}
+
+// NewIndexVerifier creates a verifier for base64-encoded ed25519 public keys.
+// When require is set, indexes without a valid signature are refused.
+// Returns nil (no verification) when there are no keys and signatures are not required,
+// or an error for malformed keys or when signatures are required without any trusted key.
+func NewIndexVerifier(trustedKeys []string, require bool) (*IndexVerifier, error) {
+	if len(trustedKeys) == 0 {
+		if require {
+			return nil, fmt.Errorf("go_releases.require_signature is enabled but no go_releases.trusted_keys are configured")
+		}
+		return nil, nil
+	}
+
+	v := &IndexVerifier{keys: make(map[string]ed25519.PublicKey), require: require}
+	for _, encoded := range trustedKeys {
+		pub, err := ParsePublicKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		v.keys[KeyID(pub)] = pub
+	}
+
+	return v, nil
+}
+
+// Verify checks sigData (the content of the detached signature file, or nil when none was published)
+// against the raw index bytes. A present but invalid signature is always an error; a missing signature
+// is an error only when signatures are required. Returns whether the index carries a valid signature.
+func (v *IndexVerifier) Verify(index, sigData []byte) (bool, error) {
+	if v == nil {
+		return false, nil
+	}
+
+	if sigData == nil {
+		if v.require {
+			return false, fmt.Errorf("release index is not signed and go_releases.require_signature is enabled")
+		}
+		_logger.Warning("Release index is not signed; checksums are only as trustworthy as the connection to the mirror")
+		return false, nil
+	}
+
+	var sig IndexSignature
+	if err := json.Unmarshal(sigData, &sig); err != nil {
+		return false, fmt.Errorf("failed to parse release index signature: %w", err)
+	}
+
+	if sig.Algorithm != signatureAlgorithm {
+		return false, fmt.Errorf("unsupported release index signature algorithm %q", sig.Algorithm)
+	}
+
+	pub, ok := v.keys[sig.KeyID]
+	if !ok {
+		return false, fmt.Errorf("release index is signed by untrusted key %s", sig.KeyID)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode release index signature: %w", err)
+	}
+
+	if !ed25519.Verify(pub, index, signature) {
+		return false, fmt.Errorf("release index signature verification failed for key %s", sig.KeyID)
+	}
+
+	_logger.Verbose("Release index signature verified (key %s)", sig.KeyID)
+	return true, nil
+}
+
+// required reports whether unsigned indexes are refused.
+func (v *IndexVerifier) required() bool {
+	return v != nil && v.require
+}
+
+// SignIndex signs the raw index bytes with key and returns the content of the detached signature file.
+func SignIndex(index []byte, key ed25519.PrivateKey) ([]byte, error) {
+	pub, ok := key.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid signing key")
+	}
+
+	sig := IndexSignature{
+		Algorithm: signatureAlgorithm,
+		KeyID:     KeyID(pub),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, index)),
+	}
+
+	data, err := json.MarshalIndent(sig, "", " ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signature: %w", err)
+	}
+	return data, nil
+}
+
+// KeyID returns a short identifier for a public key: the hex encoding of the first 8 bytes of its SHA-256.
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// EncodePublicKey returns the base64 form of pub used in go_releases.trusted_keys.
+func EncodePublicKey(pub ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+// ParsePublicKey decodes a base64-encoded ed25519 public key.
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid trusted key %q: expected a base64-encoded ed25519 public key", encoded)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// GenerateSigningKey creates a new ed25519 key pair and writes the private key to path as a
+// PKCS#8 PEM file readable only by the owner. Refuses to overwrite an existing file.
+// Returns the public key or an error.
+func GenerateSigningKey(path string) (ed25519.PublicKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key file: %w", err)
+	}
+	defer file.Close()
+
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	return pub, nil
+}
+
+// LoadSigningKey reads an ed25519 private key from a PKCS#8 PEM file, such as one written by
+// GenerateSigningKey or 'openssl genpkey -algorithm ed25519'.
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", path)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an ed25519 key", path)
+	}
+	return priv, nil
+}
@@ -0,0 +1,212 @@
+package golang
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_config "github.com/sijunda/govman/internal/config"
+)
+
+func generateTestKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return pub, priv
+}
+
+func TestIndexVerifier(t *testing.T) {
+	pub, priv := generateTestKey(t)
+	otherPub, otherPriv := generateTestKey(t)
+	index := []byte(`[{"version": "go1.21.0", "stable": true, "files": []}]`)
+
+	sig, err := SignIndex(index, priv)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	otherSig, _ := SignIndex(index, otherPriv)
+
+	verifier, err := NewIndexVerifier([]string{EncodePublicKey(pub)}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	enforcing, _ := NewIndexVerifier([]string{EncodePublicKey(pub), EncodePublicKey(otherPub)}, true)
+
+	testCases := []struct {
+		name         string
+		verifier     *IndexVerifier
+		index        []byte
+		sig          []byte
+		expectSigned bool
+		expectError  bool
+	}{
+		{name: "Valid signature", verifier: verifier, index: index, sig: sig, expectSigned: true},
+		{name: "Second trusted key", verifier: enforcing, index: index, sig: otherSig, expectSigned: true},
+		{name: "Tampered index", verifier: verifier, index: []byte(strings.Replace(string(index), "1.21.0", "1.21.1", 1)), sig: sig, expectError: true},
+		{name: "Untrusted key", verifier: verifier, index: index, sig: otherSig, expectError: true},
+		{name: "Malformed signature", verifier: verifier, index: index, sig: []byte("garbage"), expectError: true},
+		{name: "Unsigned without enforcement", verifier: verifier, index: index},
+		{name: "Unsigned with enforcement", verifier: enforcing, index: index, expectError: true},
+		{name: "No verifier", verifier: nil, index: index, sig: []byte("garbage")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			signed, err := tc.verifier.Verify(tc.index, tc.sig)
+			if tc.expectError != (err != nil) {
+				t.Fatalf("Expected error=%v, got %v", tc.expectError, err)
+			}
+			if signed != tc.expectSigned {
+				t.Errorf("Expected signed=%v, got %v", tc.expectSigned, signed)
+			}
+		})
+	}
+}
+
+func TestNewIndexVerifierErrors(t *testing.T) {
+	if _, err := NewIndexVerifier(nil, true); err == nil {
+		t.Error("Expected error when signatures are required without trusted keys")
+	}
+	if _, err := NewIndexVerifier([]string{"not-a-key"}, false); err == nil {
+		t.Error("Expected error for malformed key")
+	}
+	if v, err := NewIndexVerifier(nil, false); v != nil || err != nil {
+		t.Errorf("Expected no verifier without keys, got %v, %v", v, err)
+	}
+}
+
+func TestGenerateAndLoadSigningKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mirror.key")
+
+	pub, err := GenerateSigningKey(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	priv, err := LoadSigningKey(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !pub.Equal(priv.Public()) {
+		t.Error("Loaded key does not match generated key")
+	}
+
+	if stat, _ := os.Stat(path); stat.Mode().Perm() != 0600 {
+		t.Errorf("Expected private key mode 0600, got %v", stat.Mode().Perm())
+	}
+
+	if _, err := GenerateSigningKey(path); err == nil {
+		t.Error("Expected existing key file not to be overwritten")
+	}
+}
+
+func TestSignedSources(t *testing.T) {
+	pub, priv := generateTestKey(t)
+	index, _ := json.Marshal([]Release{{Version: "go1.21.0", Stable: true}})
+	sig, _ := SignIndex(index, priv)
+
+	cfg := _config.GoReleasesConfig{TrustedKeys: []string{EncodePublicKey(pub)}, RequireSignature: true}
+
+	t.Run("File source", func(t *testing.T) {
+		useTempIndexDir(t)
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, DefaultIndexName), index, 0644)
+
+		cfg := cfg
+		cfg.Source = SourceFile
+		cfg.APIURL = dir
+		src, err := SourceFromConfig(cfg, "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, err := fetchReleases(src, time.Minute); err == nil || !strings.Contains(err.Error(), "not signed") {
+			t.Fatalf("Expected unsigned index to be refused, got %v", err)
+		}
+
+		os.WriteFile(filepath.Join(dir, DefaultIndexName+SignatureSuffix), sig, 0644)
+		ClearReleasesCache()
+		if _, err := fetchReleases(src, time.Minute); err != nil {
+			t.Errorf("Expected signed index to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("HTTP source", func(t *testing.T) {
+		useTempIndexDir(t)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/go/index.json":
+				w.Write(index)
+			case "/go/index.json.sig":
+				w.Write(sig)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		cfg := cfg
+		cfg.APIURL = server.URL + "/go/index.json"
+		cfg.DownloadURL = server.URL + "/go/%s"
+		src, err := SourceFromConfig(cfg, "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		releases, err := fetchReleases(src, time.Minute)
+		if err != nil || len(releases) != 1 {
+			t.Fatalf("Expected signed index to be accepted, got %v, %v", releases, err)
+		}
+		if idx := loadIndex(src.ID()); idx == nil || !idx.Signed {
+			t.Error("Expected persisted index to be marked as signed")
+		}
+	})
+
+	t.Run("Cached unsigned index is not trusted once enforced", func(t *testing.T) {
+		useTempIndexDir(t)
+		server := createMockServer([]Release{{Version: "go1.21.0", Stable: true}}, http.StatusOK)
+		apiURL := server.URL
+		server.Close()
+
+		cfg := cfg
+		cfg.APIURL = apiURL
+		cfg.DownloadURL = apiURL + "/%s"
+		src, _ := SourceFromConfig(cfg, "")
+		saveIndex(&ReleaseIndex{Source: src.ID(), FetchedAt: time.Now(), Releases: []Release{{Version: "go1.21.0"}}})
+
+		if _, err := fetchReleases(src, time.Minute); err == nil {
+			t.Error("Expected unsigned cached index to be ignored")
+		}
+	})
+
+	t.Run("Signatures unsupported for listing sources", func(t *testing.T) {
+		cfg := cfg
+		cfg.Source = SourceGitHub
+		cfg.APIURL = "https://api.github.com/repos/example/go/releases"
+		if _, err := SourceFromConfig(cfg, ""); err == nil {
+			t.Error("Expected error when requiring signatures from a GitHub source")
+		}
+	})
+}
+
+func TestSignatureURL(t *testing.T) {
+	testCases := map[string]string{
+		"https://mirror.example.com/go/index.json":     "https://mirror.example.com/go/index.json.sig",
+		"http://mirror:8080/dl/?mode=json&include=all": "http://mirror:8080/dl/.sig?mode=json&include=all",
+	}
+
+	for input, expected := range testCases {
+		if got := signatureURL(input); got != expected {
+			t.Errorf("signatureURL(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,867 @@
+package golang
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	_config "github.com/sijunda/govman/internal/config"
+	_httpclient "github.com/sijunda/govman/internal/httpclient"
+	_logger "github.com/sijunda/govman/internal/logger"
+)
+
+// Release source kinds accepted by go_releases.source.
+const (
+	SourceAuto        = "auto"
+	SourceGoDev       = "godev"
+	SourceFile        = "file"
+	SourceArtifactory = "artifactory"
+	SourceNexus       = "nexus"
+	SourceGitHub      = "github"
+	SourceGoProxy     = "goproxy"
+)
+
+// DefaultIndexName is the index file looked up when a file source points at a directory.
+const DefaultIndexName = "index.json"
+
+var archiveNameRegex = regexp.MustCompile(`^go(\d+\.\d+(?:\.\d+)?(?:(?:rc|beta|alpha)\d+)?)\.([a-z0-9]+)-([a-z0-9]+)\.(tar\.gz|tar\.xz|tar\.zst|tar|zip)$`)
+var assetPlatformRegex = regexp.MustCompile(`[.\-_]([a-z0-9]+)[\-_]([a-z0-9]+)\.(tar\.gz|tar\.xz|tar\.zst|tar|zip)$`)
+
+// ReleaseSource provides the list of Go releases and the download location of their archives.
+type ReleaseSource interface {
+	// ID returns a stable identifier for the source, used to key cached release data.
+	ID() string
+	// Fetch retrieves the current release index. prev is the previously cached index (or nil);
+	// sources may use its validators for a conditional request and return it refreshed when unchanged.
+	Fetch(prev *ReleaseIndex) (*ReleaseIndex, error)
+	// DownloadURL returns the URL the archive described by file is downloaded from.
+	DownloadURL(file File) string
+}
+
+// localSource is implemented by sources that read from the local filesystem and never touch the network.
+type localSource interface {
+	isLocal() bool
+}
+
+// fileResolver is implemented by sources whose index lacks the size and checksum of each file;
+// they are looked up when a single file is selected for download.
+type fileResolver interface {
+	resolveFile(file File) (File, error)
+}
+
+// fileProber is implemented by sources whose release list may be incomplete; they can check for a
+// single archive directly.
+type fileProber interface {
+	probeFile(version, goos, goarch string) *File
+}
+
+// signedSource is implemented by sources that can verify a detached signature over their index.
+type signedSource interface {
+	requiresSignature() bool
+}
+
+// RequiresSignature reports whether src refuses release indexes without a valid signature.
+func RequiresSignature(src ReleaseSource) bool {
+	s, ok := src.(signedSource)
+	return ok && s.requiresSignature()
+}
+
+// endpointSource is implemented by sources reached over HTTP. endpoints returns the URLs (or URL templates)
+// below which the source serves its index and archives.
+type endpointSource interface {
+	endpoints() []string
+}
+
+// SourceFromConfig builds the release source selected by cfg.Source and registers cfg.Auth for the locations
+// that source uses, so credentials follow the source (e.g. a proxy taken from GOPROXY) and no other host gets them.
+// Index signatures are verified against cfg.TrustedKeys for the sources serving a static index (godev, file).
+// cacheDir is the download cache (config cache_dir), used by sources that keep state next to the archives.
+// Returns an error for unknown kinds, missing settings, or invalid trusted keys.
+func SourceFromConfig(cfg _config.GoReleasesConfig, cacheDir string) (ReleaseSource, error) {
+	src, err := newSourceFromConfig(cfg, cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	if s, ok := src.(endpointSource); ok {
+		urls = s.endpoints()
+	}
+	_httpclient.SetAuth("go_releases", cfg.Auth, urls...)
+
+	return src, nil
+}
+
+// newSourceFromConfig builds the release source selected by cfg.Source without registering credentials.
+func newSourceFromConfig(cfg _config.GoReleasesConfig, cacheDir string) (ReleaseSource, error) {
+	verifier, err := NewIndexVerifier(cfg.TrustedKeys, cfg.RequireSignature)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Source {
+	case "", SourceAuto:
+		src := newAutoSource(cfg.APIURL, cfg.DownloadURL)
+		switch s := src.(type) {
+		case *JSONSource:
+			s.verifier = verifier
+		case *FileSource:
+			s.verifier = verifier
+		}
+		return src, nil
+	case SourceGoDev:
+		src := NewJSONSource(cfg.APIURL, cfg.DownloadURL)
+		src.verifier = verifier
+		return src, nil
+	case SourceFile:
+		src, err := NewFileSource(cfg.APIURL)
+		if err != nil {
+			return nil, err
+		}
+		src.verifier = verifier
+		return src, nil
+	}
+
+	if cfg.RequireSignature {
+		return nil, fmt.Errorf("go_releases.require_signature is only supported for the godev and file sources")
+	}
+
+	switch cfg.Source {
+	case SourceArtifactory:
+		if cfg.Repository == "" {
+			return nil, fmt.Errorf("go_releases.repository is required for the artifactory source")
+		}
+		return NewArtifactorySource(cfg.APIURL, cfg.Repository, cfg.Path), nil
+	case SourceNexus:
+		if cfg.Repository == "" {
+			return nil, fmt.Errorf("go_releases.repository is required for the nexus source")
+		}
+		return NewNexusSource(cfg.APIURL, cfg.Repository, cfg.Path), nil
+	case SourceGitHub:
+		return NewGitHubSource(cfg.APIURL), nil
+	case SourceGoProxy:
+		return NewGoProxySource(cfg.APIURL, cfg.GoSum, cfg.SumDB, cacheDir)
+	default:
+		return nil, fmt.Errorf("unknown release source %q (supported: auto, godev, file, artifactory, nexus, github, goproxy)", cfg.Source)
+	}
+}
+
+// newAutoSource picks a file source for file:// URLs and local paths, and a go.dev JSON source otherwise.
+func newAutoSource(apiURL, downloadURL string) ReleaseSource {
+	if isLocalLocation(apiURL) {
+		if src, err := NewFileSource(apiURL); err == nil {
+			return src
+		}
+	}
+	return NewJSONSource(apiURL, downloadURL)
+}
+
+// isLocalLocation reports whether location is a file:// URL or a filesystem path rather than a network URL.
+func isLocalLocation(location string) bool {
+	if strings.HasPrefix(location, "file://") {
+		return true
+	}
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme == "" {
+		return location != ""
+	}
+	// Windows drive letters parse as single-letter schemes
+	return len(u.Scheme) == 1
+}
+
+// newSourceClient returns the HTTP client used by release sources, using the shared network transport.
+func newSourceClient() *http.Client {
+	return _httpclient.New(30 * time.Second)
+}
+
+// getJSON performs a GET request against rawURL and decodes the JSON response into v.
+// Returns the response headers or an error for transport failures, non-200 statuses, or invalid JSON.
+func getJSON(client *http.Client, rawURL string, v interface{}) (http.Header, error) {
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch releases: HTTP %d (%s)", resp.StatusCode, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+
+	return resp.Header, nil
+}
+
+// JSONSource reads a go.dev-style JSON release list (https://go.dev/dl/?mode=json&include=all).
+type JSONSource struct {
+	apiURL      string
+	downloadURL string
+	verifier    *IndexVerifier
+}
+
+// NewJSONSource creates a source for a go.dev-compatible JSON endpoint.
+// downloadURL is a format string receiving the archive filename.
+func NewJSONSource(apiURL, downloadURL string) *JSONSource {
+	return &JSONSource{apiURL: apiURL, downloadURL: downloadURL}
+}
+
+// ID returns the identifier of the JSON source.
+func (s *JSONSource) ID() string {
+	return "godev:" + _httpclient.RedactURL(s.apiURL)
+}
+
+// Fetch downloads the release list, issuing a conditional request when prev carries validators.
+func (s *JSONSource) Fetch(prev *ReleaseIndex) (*ReleaseIndex, error) {
+	req, err := http.NewRequest("GET", s.apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if prev != nil {
+		if prev.ETag != "" {
+			req.Header.Set("If-None-Match", prev.ETag)
+		}
+		if prev.LastModified != "" {
+			req.Header.Set("If-Modified-Since", prev.LastModified)
+		}
+	}
+
+	resp, err := newSourceClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && prev != nil {
+		_logger.Verbose("Release index not modified since %s", prev.FetchedAt.Format(time.RFC3339))
+		refreshed := *prev
+		refreshed.FetchedAt = time.Now()
+		return &refreshed, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch releases: HTTP %d (%s)", resp.StatusCode, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	signed, err := s.verifyIndex(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []Release
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+
+	return &ReleaseIndex{
+		Source:       s.ID(),
+		FetchedAt:    time.Now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Signed:       signed,
+		Releases:     releases,
+	}, nil
+}
+
+// verifyIndex fetches the detached signature published next to the index and checks it against body.
+// Returns whether the index is signed, or an error when verification fails.
+func (s *JSONSource) verifyIndex(body []byte) (bool, error) {
+	if s.verifier == nil {
+		return false, nil
+	}
+
+	sigURL := signatureURL(s.apiURL)
+	resp, err := newSourceClient().Get(sigURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch release index signature: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var sigData []byte
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if sigData, err = io.ReadAll(resp.Body); err != nil {
+			return false, fmt.Errorf("failed to read release index signature: %w", err)
+		}
+	case http.StatusNotFound:
+	default:
+		return false, fmt.Errorf("failed to fetch release index signature: HTTP %d (%s)", resp.StatusCode, resp.Status)
+	}
+
+	return s.verifier.Verify(body, sigData)
+}
+
+// requiresSignature reports whether unsigned indexes from this source are refused.
+func (s *JSONSource) requiresSignature() bool {
+	return s.verifier.required()
+}
+
+// signatureURL returns the location of the detached signature for an index URL (index.json -> index.json.sig).
+func signatureURL(indexURL string) string {
+	u, err := url.Parse(indexURL)
+	if err != nil {
+		return indexURL + SignatureSuffix
+	}
+	u.Path += SignatureSuffix
+	if u.RawPath != "" {
+		u.RawPath += SignatureSuffix
+	}
+	return u.String()
+}
+
+// DownloadURL returns the file's own URL when the index provides one, or the templated download URL.
+func (s *JSONSource) DownloadURL(file File) string {
+	if file.URL != "" {
+		return file.URL
+	}
+	return fmt.Sprintf(s.downloadURL, file.Filename)
+}
+
+// endpoints returns the index URL and the archive URL template.
+func (s *JSONSource) endpoints() []string {
+	return []string{s.apiURL, s.downloadURL}
+}
+
+// FileSource reads a static go.dev-style JSON index from the local filesystem.
+// Archives are expected next to the index unless a file carries its own URL.
+type FileSource struct {
+	indexFile string
+	verifier  *IndexVerifier
+}
+
+// NewFileSource creates a source from a file:// URL or a path to an index file or directory.
+// A directory is resolved to the DefaultIndexName file inside it.
+func NewFileSource(location string) (*FileSource, error) {
+	path := location
+	if strings.HasPrefix(location, "file://") {
+		var err error
+		if path, err = FilePath(location); err != nil {
+			return nil, err
+		}
+	}
+
+	if path == "" {
+		return nil, fmt.Errorf("file source requires a path")
+	}
+
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, DefaultIndexName)
+	}
+
+	return &FileSource{indexFile: path}, nil
+}
+
+// ID returns the identifier of the file source.
+func (s *FileSource) ID() string {
+	return "file:" + s.indexFile
+}
+
+// Fetch reads and parses the index file.
+func (s *FileSource) Fetch(prev *ReleaseIndex) (*ReleaseIndex, error) {
+	data, err := os.ReadFile(s.indexFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release index %s: %w", s.indexFile, err)
+	}
+
+	signed, err := s.verifyIndex(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+
+	return &ReleaseIndex{
+		Source:    s.ID(),
+		FetchedAt: time.Now(),
+		Signed:    signed,
+		Releases:  releases,
+	}, nil
+}
+
+// verifyIndex checks the signature file next to the index against data.
+// Returns whether the index is signed, or an error when verification fails.
+func (s *FileSource) verifyIndex(data []byte) (bool, error) {
+	if s.verifier == nil {
+		return false, nil
+	}
+
+	sigData, err := os.ReadFile(s.indexFile + SignatureSuffix)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read release index signature: %w", err)
+	}
+
+	return s.verifier.Verify(data, sigData)
+}
+
+// requiresSignature reports whether unsigned indexes from this source are refused.
+func (s *FileSource) requiresSignature() bool {
+	return s.verifier.required()
+}
+
+// DownloadURL returns the file's own URL, or a file:// URL for the archive next to the index.
+func (s *FileSource) DownloadURL(file File) string {
+	if file.URL != "" {
+		return file.URL
+	}
+	return FileURL(filepath.Join(filepath.Dir(s.indexFile), file.Filename))
+}
+
+// isLocal reports that file sources never use the network.
+func (s *FileSource) isLocal() bool {
+	return true
+}
+
+// FileURL converts a filesystem path into a file:// URL.
+func FileURL(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed
+	}
+	return (&url.URL{Scheme: "file", Path: slashed}).String()
+}
+
+// FilePath converts a file:// URL into a filesystem path.
+func FilePath(fileURL string) (string, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil || u.Scheme != "file" {
+		return "", fmt.Errorf("invalid file URL %s", fileURL)
+	}
+
+	path := filepath.FromSlash(u.Path)
+	// file:///C:/dir yields /C:/dir on Windows
+	if len(path) > 2 && os.IsPathSeparator(path[0]) && path[2] == ':' {
+		path = path[1:]
+	}
+	return path, nil
+}
+
+// ArtifactorySource builds releases from the Go archives stored in an Artifactory generic repository,
+// using the storage list API to obtain sizes and checksums.
+type ArtifactorySource struct {
+	baseURL    string
+	repository string
+	path       string
+}
+
+// NewArtifactorySource creates a source for an Artifactory generic repository.
+// baseURL is the Artifactory root (e.g., https://artifactory.example.com/artifactory).
+func NewArtifactorySource(baseURL, repository, path string) *ArtifactorySource {
+	return &ArtifactorySource{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		repository: strings.Trim(repository, "/"),
+		path:       strings.Trim(path, "/"),
+	}
+}
+
+// ID returns the identifier of the Artifactory source.
+func (s *ArtifactorySource) ID() string {
+	return fmt.Sprintf("artifactory:%s/%s/%s", _httpclient.RedactURL(s.baseURL), s.repository, s.path)
+}
+
+// Fetch lists the repository folder and groups recognised archives into releases.
+func (s *ArtifactorySource) Fetch(prev *ReleaseIndex) (*ReleaseIndex, error) {
+	listURL := fmt.Sprintf("%s/api/storage/%s", s.baseURL, joinURLPath(s.repository, s.path)) + "?list&deep=0&listFolders=0"
+
+	var listing struct {
+		Files []struct {
+			URI    string `json:"uri"`
+			Size   int64  `json:"size"`
+			Folder bool   `json:"folder"`
+			SHA256 string `json:"sha2"`
+		} `json:"files"`
+	}
+	if _, err := getJSON(newSourceClient(), listURL, &listing); err != nil {
+		return nil, err
+	}
+
+	var files []File
+	for _, entry := range listing.Files {
+		if entry.Folder {
+			continue
+		}
+		name := strings.TrimPrefix(entry.URI, "/")
+		file, ok := ParseArchiveName(name)
+		if !ok {
+			continue
+		}
+		file.Sha256 = entry.SHA256
+		file.Size = entry.Size
+		file.URL = fmt.Sprintf("%s/%s", s.baseURL, joinURLPath(s.repository, s.path, name))
+		files = append(files, file)
+	}
+
+	return &ReleaseIndex{
+		Source:    s.ID(),
+		FetchedAt: time.Now(),
+		Releases:  ReleasesFromFiles(files),
+	}, nil
+}
+
+// DownloadURL returns the repository URL recorded for the archive.
+func (s *ArtifactorySource) DownloadURL(file File) string {
+	if file.URL != "" {
+		return file.URL
+	}
+	return fmt.Sprintf("%s/%s", s.baseURL, joinURLPath(s.repository, s.path, file.Filename))
+}
+
+// endpoints returns the Artifactory root, which serves both the storage API and the archives.
+func (s *ArtifactorySource) endpoints() []string {
+	return []string{s.baseURL + "/"}
+}
+
+// NexusSource builds releases from the Go archives stored in a Nexus raw repository,
+// using the search API to obtain sizes and checksums.
+type NexusSource struct {
+	baseURL    string
+	repository string
+	path       string
+}
+
+// NewNexusSource creates a source for a Nexus raw repository.
+// baseURL is the Nexus root (e.g., https://nexus.example.com); path optionally restricts the search to a group.
+func NewNexusSource(baseURL, repository, path string) *NexusSource {
+	return &NexusSource{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		repository: strings.Trim(repository, "/"),
+		path:       strings.Trim(path, "/"),
+	}
+}
+
+// ID returns the identifier of the Nexus source.
+func (s *NexusSource) ID() string {
+	return fmt.Sprintf("nexus:%s/%s/%s", _httpclient.RedactURL(s.baseURL), s.repository, s.path)
+}
+
+// Fetch pages through the repository's assets and groups recognised archives into releases.
+func (s *NexusSource) Fetch(prev *ReleaseIndex) (*ReleaseIndex, error) {
+	client := newSourceClient()
+
+	query := url.Values{}
+	query.Set("repository", s.repository)
+	if s.path != "" {
+		query.Set("group", "/"+s.path)
+	}
+
+	var files []File
+	for {
+		var page struct {
+			Items []struct {
+				DownloadURL string `json:"downloadUrl"`
+				Path        string `json:"path"`
+				FileSize    int64  `json:"fileSize"`
+				Checksum    struct {
+					SHA256 string `json:"sha256"`
+				} `json:"checksum"`
+			} `json:"items"`
+			ContinuationToken string `json:"continuationToken"`
+		}
+
+		if _, err := getJSON(client, s.baseURL+"/service/rest/v1/search/assets?"+query.Encode(), &page); err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			file, ok := ParseArchiveName(pathBase(item.Path))
+			if !ok {
+				continue
+			}
+			file.Sha256 = item.Checksum.SHA256
+			file.Size = item.FileSize
+			file.URL = item.DownloadURL
+			files = append(files, file)
+		}
+
+		if page.ContinuationToken == "" {
+			break
+		}
+		query.Set("continuationToken", page.ContinuationToken)
+	}
+
+	return &ReleaseIndex{
+		Source:    s.ID(),
+		FetchedAt: time.Now(),
+		Releases:  ReleasesFromFiles(files),
+	}, nil
+}
+
+// DownloadURL returns the asset URL reported by Nexus, or the raw repository URL for the archive.
+func (s *NexusSource) DownloadURL(file File) string {
+	if file.URL != "" {
+		return file.URL
+	}
+	return fmt.Sprintf("%s/repository/%s", s.baseURL, joinURLPath(s.repository, s.path, file.Filename))
+}
+
+// endpoints returns the Nexus root, which serves both the search API and the archives.
+func (s *NexusSource) endpoints() []string {
+	return []string{s.baseURL + "/"}
+}
+
+// GitHubSource reads a GitHub-releases-style feed, as used by forked toolchains.
+// Each release tag is a Go version; assets named <anything>.<os>-<arch>.<ext> become archive files.
+type GitHubSource struct {
+	apiURL string
+}
+
+// NewGitHubSource creates a source for a releases endpoint such as https://api.github.com/repos/<owner>/<repo>/releases.
+func NewGitHubSource(apiURL string) *GitHubSource {
+	return &GitHubSource{apiURL: apiURL}
+}
+
+// ID returns the identifier of the GitHub source.
+func (s *GitHubSource) ID() string {
+	return "github:" + _httpclient.RedactURL(s.apiURL)
+}
+
+type gitHubAsset struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	DownloadURL string `json:"browser_download_url"`
+	Digest      string `json:"digest"`
+}
+
+type gitHubRelease struct {
+	TagName    string        `json:"tag_name"`
+	Draft      bool          `json:"draft"`
+	Prerelease bool          `json:"prerelease"`
+	Assets     []gitHubAsset `json:"assets"`
+}
+
+// Fetch walks the paginated releases feed and converts each published release.
+// Checksums come from asset digests or from a SHA256SUMS-style asset in the same release.
+func (s *GitHubSource) Fetch(prev *ReleaseIndex) (*ReleaseIndex, error) {
+	client := newSourceClient()
+
+	var releases []Release
+	next := s.apiURL
+	for next != "" {
+		var page []gitHubRelease
+		header, err := getJSON(client, next, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ghRelease := range page {
+			if ghRelease.Draft {
+				continue
+			}
+			if release, ok := s.convertRelease(client, ghRelease); ok {
+				releases = append(releases, release)
+			}
+		}
+
+		next = nextPageURL(header.Get("Link"))
+	}
+
+	return &ReleaseIndex{
+		Source:    s.ID(),
+		FetchedAt: time.Now(),
+		Releases:  releases,
+	}, nil
+}
+
+// convertRelease maps a GitHub release to a Release. Returns false if it has no usable archives.
+func (s *GitHubSource) convertRelease(client *http.Client, ghRelease gitHubRelease) (Release, bool) {
+	version := normalizeVersion(ghRelease.TagName)
+	if version == "" {
+		return Release{}, false
+	}
+
+	var sums map[string]string
+	release := Release{
+		Version: "go" + version,
+		Stable:  !ghRelease.Prerelease && parseVersion(version).prerelease == "",
+	}
+
+	for _, asset := range ghRelease.Assets {
+		matches := assetPlatformRegex.FindStringSubmatch(asset.Name)
+		if matches == nil {
+			continue
+		}
+
+		checksum := strings.TrimPrefix(asset.Digest, "sha256:")
+		if checksum == "" {
+			if sums == nil {
+				sums = fetchChecksumAsset(client, ghRelease.Assets)
+			}
+			checksum = sums[asset.Name]
+		}
+		if checksum == "" {
+			_logger.Verbose("Skipping %s from %s: no sha256 checksum published", asset.Name, ghRelease.TagName)
+			continue
+		}
+
+		release.Files = append(release.Files, File{
+			Filename: asset.Name,
+			OS:       matches[1],
+			Arch:     matches[2],
+			Version:  release.Version,
+			Sha256:   checksum,
+			Size:     asset.Size,
+			Kind:     "archive",
+			URL:      asset.DownloadURL,
+		})
+	}
+
+	return release, len(release.Files) > 0
+}
+
+// DownloadURL returns the asset download URL recorded for the archive.
+func (s *GitHubSource) DownloadURL(file File) string {
+	return file.URL
+}
+
+// endpoints returns the releases endpoint, which covers its repository's API including asset downloads.
+// Assets redirected to another host need auth.hosts.
+func (s *GitHubSource) endpoints() []string {
+	return []string{s.apiURL}
+}
+
+// fetchChecksumAsset downloads the first SHA256SUMS-style asset and parses "<sha256>  <name>" lines.
+// Returns an empty map when no such asset exists or it cannot be read.
+func fetchChecksumAsset(client *http.Client, assets []gitHubAsset) map[string]string {
+	sums := map[string]string{}
+	for _, asset := range assets {
+		lower := strings.ToLower(asset.Name)
+		if lower != "sha256sums" && lower != "sha256sums.txt" && lower != "checksums.txt" {
+			continue
+		}
+
+		resp, err := client.Get(asset.DownloadURL)
+		if err != nil {
+			return sums
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return sums
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 2 {
+				sums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+			}
+		}
+		return sums
+	}
+	return sums
+}
+
+// nextPageURL extracts the rel="next" target from an RFC 5988 Link header.
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(segments[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+// ParseArchiveName recognises official Go archive names (go<version>.<os>-<arch>.<ext>).
+// Returns the File metadata derivable from the name and whether the name matched.
+func ParseArchiveName(name string) (File, bool) {
+	matches := archiveNameRegex.FindStringSubmatch(name)
+	if matches == nil {
+		return File{}, false
+	}
+
+	return File{
+		Filename: name,
+		OS:       matches[2],
+		Arch:     matches[3],
+		Version:  "go" + matches[1],
+		Kind:     "archive",
+	}, true
+}
+
+// ParseAssetPlatform recognises archive names that end in <os>-<arch>.<ext>, such as GitHub release assets,
+// whose version cannot be derived from the name. Returns the OS, the architecture, and whether the name matched.
+func ParseAssetPlatform(name string) (string, string, bool) {
+	matches := assetPlatformRegex.FindStringSubmatch(name)
+	if matches == nil {
+		return "", "", false
+	}
+	return matches[1], matches[2], true
+}
+
+// ReleasesFromFiles groups archive files into releases sorted newest first; prereleases are marked unstable.
+func ReleasesFromFiles(files []File) []Release {
+	byVersion := map[string]*Release{}
+	for _, file := range files {
+		release, ok := byVersion[file.Version]
+		if !ok {
+			release = &Release{
+				Version: file.Version,
+				Stable:  parseVersion(normalizeVersion(file.Version)).prerelease == "",
+			}
+			byVersion[file.Version] = release
+		}
+		release.Files = append(release.Files, file)
+	}
+
+	releases := make([]Release, 0, len(byVersion))
+	for _, release := range byVersion {
+		releases = append(releases, *release)
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return CompareVersions(releases[i].Version, releases[j].Version) > 0
+	})
+
+	return releases
+}
+
+// joinURLPath joins non-empty URL path segments with slashes.
+func joinURLPath(segments ...string) string {
+	var parts []string
+	for _, segment := range segments {
+		if segment = strings.Trim(segment, "/"); segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// pathBase returns the last element of a slash-separated path.
+func pathBase(p string) string {
+	if i := strings.LastIndex(p, "/"); i >= 0 {
+		return p[i+1:]
+	}
+	return p
+}
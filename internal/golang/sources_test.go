@@ -0,0 +1,400 @@
+package golang
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_config "github.com/sijunda/govman/internal/config"
+	_httpclient "github.com/sijunda/govman/internal/httpclient"
+)
+
+func TestSourceFromConfig(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          _config.GoReleasesConfig
+		expectedType string
+		expectError  bool
+	}{
+		{
+			name:         "Auto with HTTP URL",
+			cfg:          _config.GoReleasesConfig{Source: "auto", APIURL: "https://go.dev/dl/?mode=json", DownloadURL: "https://go.dev/dl/%s"},
+			expectedType: "*golang.JSONSource",
+		},
+		{
+			name:         "Empty kind behaves like auto",
+			cfg:          _config.GoReleasesConfig{APIURL: "https://go.dev/dl/?mode=json"},
+			expectedType: "*golang.JSONSource",
+		},
+		{
+			name:         "Auto with file URL",
+			cfg:          _config.GoReleasesConfig{Source: "auto", APIURL: "file:///srv/go/index.json"},
+			expectedType: "*golang.FileSource",
+		},
+		{
+			name:         "Artifactory",
+			cfg:          _config.GoReleasesConfig{Source: "artifactory", APIURL: "https://artifactory.example.com/artifactory", Repository: "go"},
+			expectedType: "*golang.ArtifactorySource",
+		},
+		{
+			name:        "Artifactory without repository",
+			cfg:         _config.GoReleasesConfig{Source: "artifactory", APIURL: "https://artifactory.example.com/artifactory"},
+			expectError: true,
+		},
+		{
+			name:         "Nexus",
+			cfg:          _config.GoReleasesConfig{Source: "nexus", APIURL: "https://nexus.example.com", Repository: "go"},
+			expectedType: "*golang.NexusSource",
+		},
+		{
+			name:         "GitHub",
+			cfg:          _config.GoReleasesConfig{Source: "github", APIURL: "https://api.github.com/repos/example/go/releases"},
+			expectedType: "*golang.GitHubSource",
+		},
+		{
+			name:        "Unknown kind",
+			cfg:         _config.GoReleasesConfig{Source: "ftp", APIURL: "ftp://example.com"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := SourceFromConfig(tc.cfg, "")
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := fmt.Sprintf("%T", src); got != tc.expectedType {
+				t.Errorf("Expected %s, got %s", tc.expectedType, got)
+			}
+		})
+	}
+}
+
+func TestSourceFromConfig_Auth(t *testing.T) {
+	defer _httpclient.ClearAuth()
+	t.Setenv("PROXY_TOKEN", "s3cret")
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprintln(w, "v0.0.1-go1.22.1.linux-amd64")
+	}))
+	defer server.Close()
+
+	// The proxy comes from GOPROXY, not from go_releases.api_url
+	t.Setenv("GOPROXY", server.URL+",direct")
+	src, err := SourceFromConfig(_config.GoReleasesConfig{
+		Source: SourceGoProxy,
+		SumDB:  "off",
+		GoSum:  "go.sum",
+		Auth:   _config.AuthConfig{TokenEnv: "PROXY_TOKEN"},
+	}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := src.Fetch(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if authorization != "Bearer s3cret" {
+		t.Errorf("Expected the source's credentials on the proxy, got %q", authorization)
+	}
+}
+
+func TestFetchReleasesCacheKeyedBySource(t *testing.T) {
+	ClearReleasesCache()
+	defer ClearReleasesCache()
+
+	serverA := createMockServer([]Release{{Version: "go1.21.0", Stable: true}}, http.StatusOK)
+	defer serverA.Close()
+	serverB := createMockServer([]Release{{Version: "go1.22.0", Stable: true}, {Version: "go1.21.0", Stable: true}}, http.StatusOK)
+	defer serverB.Close()
+
+	versionsA, err := GetAvailableVersionsWithConfig(false, serverA.URL, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	versionsB, err := GetAvailableVersionsWithConfig(false, serverB.URL, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(versionsA) != 1 || len(versionsB) != 2 {
+		t.Errorf("Expected each URL to return its own releases, got %v and %v", versionsA, versionsB)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	dir := t.TempDir()
+	releases := []Release{{
+		Version: "go1.21.0",
+		Stable:  true,
+		Files: []File{{
+			Filename: "go1.21.0.linux-amd64.tar.gz",
+			OS:       "linux",
+			Arch:     "amd64",
+			Version:  "go1.21.0",
+			Kind:     "archive",
+		}},
+	}}
+	data, _ := json.Marshal(releases)
+	if err := os.WriteFile(filepath.Join(dir, DefaultIndexName), data, 0644); err != nil {
+		t.Fatalf("Failed to write index: %v", err)
+	}
+
+	for _, location := range []string{dir, filepath.Join(dir, DefaultIndexName), FileURL(filepath.Join(dir, DefaultIndexName))} {
+		t.Run(location, func(t *testing.T) {
+			src, err := NewFileSource(location)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			idx, err := src.Fetch(nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(idx.Releases) != 1 {
+				t.Fatalf("Expected 1 release, got %d", len(idx.Releases))
+			}
+
+			downloadURL := src.DownloadURL(idx.Releases[0].Files[0])
+			path, err := FilePath(downloadURL)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if path != filepath.Join(dir, "go1.21.0.linux-amd64.tar.gz") {
+				t.Errorf("Unexpected archive path %s", path)
+			}
+		})
+	}
+
+	t.Run("Offline mode still reads local sources", func(t *testing.T) {
+		ClearReleasesCache()
+		SetOffline(true)
+		defer SetOffline(false)
+
+		src, _ := NewFileSource(dir)
+		if _, err := fetchReleases(src, time.Minute); err != nil {
+			t.Errorf("Expected local source to be readable offline, got %v", err)
+		}
+	})
+}
+
+func TestArtifactorySource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/artifactory/api/storage/go-dist/go" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"files": [
+			{"uri": "/go1.21.0.linux-amd64.tar.gz", "size": 100, "sha2": "aaa"},
+			{"uri": "/go1.21.0.windows-amd64.zip", "size": 200, "sha2": "bbb"},
+			{"uri": "/go1.22rc1.linux-amd64.tar.gz", "size": 300, "sha2": "ccc"},
+			{"uri": "/README.md", "size": 1},
+			{"uri": "/old", "folder": true}
+		]}`)
+	}))
+	defer server.Close()
+
+	src := NewArtifactorySource(server.URL+"/artifactory/", "go-dist", "/go/")
+	idx, err := src.Fetch(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(idx.Releases) != 2 {
+		t.Fatalf("Expected 2 releases, got %d", len(idx.Releases))
+	}
+	if idx.Releases[0].Version != "go1.22rc1" || idx.Releases[0].Stable {
+		t.Errorf("Expected newest unstable release first, got %+v", idx.Releases[0])
+	}
+
+	stable := idx.Releases[1]
+	if !stable.Stable || len(stable.Files) != 2 {
+		t.Fatalf("Expected stable release with 2 files, got %+v", stable)
+	}
+
+	file := stable.Files[0]
+	expectedURL := server.URL + "/artifactory/go-dist/go/go1.21.0.linux-amd64.tar.gz"
+	if src.DownloadURL(file) != expectedURL {
+		t.Errorf("Expected download URL %s, got %s", expectedURL, src.DownloadURL(file))
+	}
+	if file.Sha256 != "aaa" || file.Size != 100 {
+		t.Errorf("Unexpected file metadata: %+v", file)
+	}
+}
+
+func TestNexusSource(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("repository") != "go-raw" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("continuationToken") == "" {
+			fmt.Fprintf(w, `{"items": [{"downloadUrl": "%s/repository/go-raw/go1.21.0.linux-amd64.tar.gz", "path": "go1.21.0.linux-amd64.tar.gz", "fileSize": 100, "checksum": {"sha256": "aaa"}}], "continuationToken": "next"}`, server.URL)
+			return
+		}
+		fmt.Fprintf(w, `{"items": [{"downloadUrl": "%s/repository/go-raw/go1.20.0.linux-amd64.tar.gz", "path": "go1.20.0.linux-amd64.tar.gz", "fileSize": 90, "checksum": {"sha256": "bbb"}}]}`, server.URL)
+	}))
+	defer server.Close()
+
+	src := NewNexusSource(server.URL, "go-raw", "")
+	idx, err := src.Fetch(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(idx.Releases) != 2 {
+		t.Fatalf("Expected releases from both pages, got %d", len(idx.Releases))
+	}
+	if !strings.HasSuffix(src.DownloadURL(idx.Releases[0].Files[0]), "/repository/go-raw/go1.21.0.linux-amd64.tar.gz") {
+		t.Errorf("Unexpected download URL %s", src.DownloadURL(idx.Releases[0].Files[0]))
+	}
+}
+
+func TestGitHubSource(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases":
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/releases?page=2>; rel="next", <%s/releases?page=2>; rel="last"`, server.URL, server.URL))
+				fmt.Fprintf(w, `[
+					{"tag_name": "v1.22.1-corp", "assets": [
+						{"name": "go1.22.1-corp.linux-amd64.tar.gz", "size": 10, "browser_download_url": "%[1]s/dl/a.tar.gz", "digest": "sha256:aaa"},
+						{"name": "go1.22.1-corp.darwin-arm64.tar.gz", "size": 11, "browser_download_url": "%[1]s/dl/b.tar.gz"},
+						{"name": "SHA256SUMS", "browser_download_url": "%[1]s/dl/SHA256SUMS"}
+					]},
+					{"tag_name": "v1.23.0", "draft": true, "assets": []}
+				]`, server.URL)
+				return
+			}
+			fmt.Fprintf(w, `[{"tag_name": "go1.21.0rc1", "prerelease": true, "assets": [
+				{"name": "go1.21.0rc1.linux-amd64.tar.gz", "size": 12, "browser_download_url": "%s/dl/c.tar.gz", "digest": "sha256:ccc"}
+			]}]`, server.URL)
+		case "/dl/SHA256SUMS":
+			fmt.Fprint(w, "bbb  go1.22.1-corp.darwin-arm64.tar.gz\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	src := NewGitHubSource(server.URL + "/releases")
+	idx, err := src.Fetch(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(idx.Releases) != 2 {
+		t.Fatalf("Expected 2 releases (draft skipped), got %d", len(idx.Releases))
+	}
+
+	corp := idx.Releases[0]
+	if corp.Version != "go1.22.1-corp" || !corp.Stable || len(corp.Files) != 2 {
+		t.Fatalf("Unexpected release: %+v", corp)
+	}
+	if corp.Files[1].Sha256 != "bbb" || corp.Files[1].OS != "darwin" || corp.Files[1].Arch != "arm64" {
+		t.Errorf("Expected checksum from SHA256SUMS, got %+v", corp.Files[1])
+	}
+	if src.DownloadURL(corp.Files[0]) != server.URL+"/dl/a.tar.gz" {
+		t.Errorf("Unexpected download URL %s", src.DownloadURL(corp.Files[0]))
+	}
+
+	if idx.Releases[1].Stable {
+		t.Error("Expected prerelease to be unstable")
+	}
+}
+
+func TestParseArchiveName(t *testing.T) {
+	testCases := []struct {
+		name    string
+		matches bool
+		os      string
+		arch    string
+		version string
+	}{
+		{"go1.21.0.linux-amd64.tar.gz", true, "linux", "amd64", "go1.21.0"},
+		{"go1.22rc1.windows-arm64.zip", true, "windows", "arm64", "go1.22rc1"},
+		{"go1.22.1.linux-arm64.tar.xz", true, "linux", "arm64", "go1.22.1"},
+		{"go1.22.1.linux-amd64.tar.zst", true, "linux", "amd64", "go1.22.1"},
+		{"go1.22.1.linux-riscv64.tar", true, "linux", "riscv64", "go1.22.1"},
+		{"go1.22.1.linux-amd64.tar.bz2", false, "", "", ""},
+		{"go1.21.0.darwin-arm64.pkg", false, "", "", ""},
+		{"go1.21.0.src.tar.gz", false, "", "", ""},
+		{"notes.txt", false, "", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, ok := ParseArchiveName(tc.name)
+			if ok != tc.matches {
+				t.Fatalf("Expected match=%v, got %v", tc.matches, ok)
+			}
+			if ok && (file.OS != tc.os || file.Arch != tc.arch || file.Version != tc.version) {
+				t.Errorf("Unexpected file: %+v", file)
+			}
+		})
+	}
+}
+
+func TestParseAssetPlatform(t *testing.T) {
+	testCases := []struct {
+		name    string
+		matches bool
+		os      string
+		arch    string
+	}{
+		{"go-fork-linux-amd64.tar.gz", true, "linux", "amd64"},
+		{"toolchain_darwin_arm64.zip", true, "darwin", "arm64"},
+		{"go1.22.1.linux-amd64.tar.gz", true, "linux", "amd64"},
+		{"go1.22.1.linux-amd64.tar.gz.asc", false, "", ""},
+		{"notes.txt", false, "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			osName, arch, ok := ParseAssetPlatform(tc.name)
+			if ok != tc.matches {
+				t.Fatalf("Expected match=%v, got %v", tc.matches, ok)
+			}
+			if osName != tc.os || arch != tc.arch {
+				t.Errorf("Expected %s/%s, got %s/%s", tc.os, tc.arch, osName, arch)
+			}
+		})
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	testCases := []struct {
+		link     string
+		expected string
+	}{
+		{`<https://api.example.com/r?page=2>; rel="next", <https://api.example.com/r?page=5>; rel="last"`, "https://api.example.com/r?page=2"},
+		{`<https://api.example.com/r?page=1>; rel="prev"`, ""},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := nextPageURL(tc.link); got != tc.expected {
+			t.Errorf("nextPageURL(%q) = %q, expected %q", tc.link, got, tc.expected)
+		}
+	}
+}
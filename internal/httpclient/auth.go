@@ -0,0 +1,393 @@
+package httpclient
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	_config "github.com/sijunda/govman/internal/config"
+	_logger "github.com/sijunda/govman/internal/logger"
+)
+
+// maxRedirects matches the redirect limit of http.Client's default policy.
+const maxRedirects = 10
+
+// sensitiveQueryParams are query parameters whose values are hidden by RedactURL.
+var sensitiveQueryParams = map[string]bool{
+	"token":        true,
+	"access_token": true,
+	"api_key":      true,
+	"apikey":       true,
+	"password":     true,
+	"signature":    true,
+	"sig":          true,
+}
+
+// credentials is the authentication configured for one release source.
+// The netrc file is read on the first request that needs it.
+type credentials struct {
+	name      string
+	scopes    []authScope
+	tokenEnv  string
+	headers   map[string]string
+	useNetrc  bool
+	netrcOnce sync.Once
+	netrc     map[string]netrcEntry
+	netrcErr  error
+}
+
+// authScope is a location that receives a set of credentials: a host and a URL path prefix.
+type authScope struct {
+	host string
+	path string
+}
+
+// netrcEntry is a login/password pair from a netrc file.
+type netrcEntry struct {
+	login    string
+	password string
+}
+
+// registeredAuth holds the credentials registered with SetAuth, keyed by name.
+var registeredAuth = map[string]*credentials{}
+
+// SetAuth registers auth under name (the config section it comes from, e.g. "go_releases") for
+// requests below the locations of urls and to any path on auth.Hosts, replacing an earlier registration of name.
+// Each request uses the registration with the most specific matching location, so sources sharing
+// a host can use different credentials. Credentials are added by the shared transport and are never sent to any
+// other host, including after a redirect. URLs that are templates (containing %s) are accepted. A netrc file is
+// only read once a request needs it, and a netrc file that cannot be parsed fails that request.
+func SetAuth(name string, auth _config.AuthConfig, urls ...string) {
+	if !auth.Netrc && auth.TokenEnv == "" && len(auth.Headers) == 0 {
+		transportMutex.Lock()
+		delete(registeredAuth, name)
+		transportMutex.Unlock()
+		return
+	}
+
+	creds := &credentials{
+		name:     name,
+		tokenEnv: auth.TokenEnv,
+		headers:  make(map[string]string),
+		useNetrc: auth.Netrc,
+	}
+
+	for _, rawURL := range urls {
+		if scope, ok := urlScope(rawURL); ok {
+			creds.scopes = append(creds.scopes, scope)
+		}
+	}
+	for _, host := range auth.Hosts {
+		creds.scopes = append(creds.scopes, authScope{host: strings.ToLower(strings.TrimSpace(host)), path: "/"})
+	}
+
+	for name, value := range auth.Headers {
+		creds.headers[http.CanonicalHeaderKey(name)] = value
+	}
+
+	transportMutex.Lock()
+	registeredAuth[name] = creds
+	transportMutex.Unlock()
+}
+
+// ClearAuth removes all credentials registered with SetAuth.
+func ClearAuth() {
+	transportMutex.Lock()
+	registeredAuth = map[string]*credentials{}
+	transportMutex.Unlock()
+}
+
+// credentialsFor returns the registered credentials whose location matches u most specifically, or nil.
+// Ties are broken by name so the choice does not depend on map order.
+func credentialsFor(u *url.URL) *credentials {
+	transportMutex.RLock()
+	defer transportMutex.RUnlock()
+
+	host := strings.ToLower(u.Host)
+	reqPath := u.Path
+	if reqPath == "" {
+		reqPath = "/"
+	}
+
+	var best *credentials
+	bestLen := -1
+	for _, creds := range registeredAuth {
+		for _, scope := range creds.scopes {
+			if scope.host != host || !strings.HasPrefix(reqPath, scope.path) {
+				continue
+			}
+			if len(scope.path) > bestLen || (len(scope.path) == bestLen && creds.name < best.name) {
+				best, bestLen = creds, len(scope.path)
+			}
+		}
+	}
+	return best
+}
+
+// netrcEntries returns the parsed netrc file, reading it on first use.
+func (c *credentials) netrcEntries() (map[string]netrcEntry, error) {
+	c.netrcOnce.Do(func() {
+		c.netrc, c.netrcErr = loadNetrc()
+	})
+	return c.netrc, c.netrcErr
+}
+
+// authTransport adds the registered credentials to requests for their hosts before passing them on
+// to the shared transport.
+type authTransport struct{}
+
+// RoundTrip implements http.RoundTripper. The caller's request is never modified.
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base, err := Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	creds := credentialsFor(req.URL)
+	if creds == nil {
+		return base.RoundTrip(req)
+	}
+
+	authed := req.Clone(req.Context())
+	var applied []string
+
+	for name, value := range creds.headers {
+		if authed.Header.Get(name) == "" {
+			authed.Header.Set(name, os.ExpandEnv(value))
+			applied = append(applied, "header "+name)
+		}
+	}
+
+	if authed.Header.Get("Authorization") == "" && creds.tokenEnv != "" {
+		if token := os.Getenv(creds.tokenEnv); token != "" {
+			authed.Header.Set("Authorization", "Bearer "+token)
+			applied = append(applied, "bearer token from $"+creds.tokenEnv)
+		} else {
+			_logger.Verbose("Environment variable %s is empty; sending request to %s without a token", creds.tokenEnv, req.URL.Host)
+		}
+	}
+
+	if authed.Header.Get("Authorization") == "" && creds.useNetrc {
+		entries, err := creds.netrcEntries()
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s.auth: %w", creds.name, err)
+		}
+		if entry, ok := lookupNetrc(entries, req.URL.Hostname()); ok {
+			authed.SetBasicAuth(entry.login, entry.password)
+			applied = append(applied, "netrc login "+entry.login)
+		}
+	}
+
+	if len(applied) > 0 {
+		sort.Strings(applied)
+		_logger.Verbose("Authenticating request to %s with %s (%s.auth)", RedactURL(req.URL.String()), strings.Join(applied, ", "), creds.name)
+	}
+
+	return base.RoundTrip(authed)
+}
+
+// checkRedirect follows up to maxRedirects redirects and drops credentials when a redirect leaves the
+// original host. Go's default policy still forwards them to subdomains, which is not acceptable for mirrors.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.New("stopped after 10 redirects")
+	}
+
+	if !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		req.Header.Del("Authorization")
+		req.Header.Del("Cookie")
+
+		if creds := credentialsFor(via[0].URL); creds != nil {
+			for name := range creds.headers {
+				req.Header.Del(name)
+			}
+		}
+	}
+	return nil
+}
+
+// RedactURL hides the password and sensitive query parameters of rawURL so it can be logged.
+// Strings that are not URLs are returned unchanged.
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	changed := false
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+		changed = true
+	}
+
+	query := u.Query()
+	for name := range query {
+		if sensitiveQueryParams[strings.ToLower(name)] {
+			query.Set(name, "xxxxx")
+			changed = true
+		}
+	}
+
+	if !changed {
+		return rawURL
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// urlScope returns the location covered by rawURL, which may be a download URL template: its lower-cased
+// host[:port] and the directory part of its path (up to the template's %s). Returns false without a host.
+func urlScope(rawURL string) (authScope, bool) {
+	if i := strings.Index(rawURL, "%s"); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return authScope{}, false
+	}
+
+	dir := u.Path[:strings.LastIndex(u.Path, "/")+1]
+	if dir == "" {
+		dir = "/"
+	}
+	return authScope{host: strings.ToLower(u.Host), path: dir}, true
+}
+
+// netrcPath returns the netrc file to read: $NETRC, or ~/.netrc (~/_netrc on Windows).
+func netrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "_netrc")
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// loadNetrc reads the netrc file. A missing file yields no entries.
+// Returns the entries keyed by machine name ("" for the default entry) or a parse error.
+func loadNetrc() (map[string]netrcEntry, error) {
+	path := netrcPath()
+	if path == "" {
+		return map[string]netrcEntry{}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			_logger.Verbose("No netrc file at %s", path)
+			return map[string]netrcEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read netrc: %w", err)
+	}
+	defer file.Close()
+
+	entries, err := parseNetrc(bufio.NewScanner(file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse netrc %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// parseNetrc parses netrc content: "machine", "default", "login", "password", and "account" tokens.
+// Macro definitions ("macdef") are skipped up to the next blank line.
+func parseNetrc(scanner *bufio.Scanner) (map[string]netrcEntry, error) {
+	entries := make(map[string]netrcEntry)
+
+	var (
+		machine string
+		current *netrcEntry
+		inMacro bool
+	)
+	flush := func() {
+		if current != nil {
+			if _, exists := entries[machine]; !exists {
+				entries[machine] = *current
+			}
+		}
+		current = nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacro {
+			if strings.TrimSpace(line) == "" {
+				inMacro = false
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			token := fields[i]
+			if strings.HasPrefix(token, "#") {
+				break
+			}
+
+			switch token {
+			case "machine", "login", "password", "account", "macdef":
+				if i+1 >= len(fields) {
+					return nil, fmt.Errorf("missing value after %q", token)
+				}
+				value := fields[i+1]
+				i++
+
+				switch token {
+				case "machine":
+					flush()
+					machine = strings.ToLower(value)
+					current = &netrcEntry{}
+				case "login":
+					if current != nil {
+						current.login = value
+					}
+				case "password":
+					if current != nil {
+						current.password = value
+					}
+				case "macdef":
+					inMacro = true
+				}
+			case "default":
+				flush()
+				machine = ""
+				current = &netrcEntry{}
+			default:
+				return nil, fmt.Errorf("unexpected token %q", token)
+			}
+
+			if inMacro {
+				break
+			}
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// lookupNetrc returns the entry for host, falling back to the default entry.
+func lookupNetrc(entries map[string]netrcEntry, host string) (netrcEntry, bool) {
+	if entry, ok := entries[strings.ToLower(host)]; ok {
+		return entry, true
+	}
+	entry, ok := entries[""]
+	return entry, ok
+}
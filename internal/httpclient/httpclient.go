@@ -0,0 +1,171 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	httpproxy "golang.org/x/net/http/httpproxy"
+
+	_config "github.com/sijunda/govman/internal/config"
+)
+
+var (
+	transportMutex  sync.RWMutex
+	networkConfig   _config.NetworkConfig
+	sharedTransport http.RoundTripper
+	transportErr    error
+)
+
+// tlsVersions maps the accepted network.tls_min_version values to TLS versions.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Configure records cfg as the network settings of every client created with New. The transport is built
+// from it on the first request, so commands that never use the network neither load certificates nor fail
+// on invalid settings.
+func Configure(cfg _config.NetworkConfig) {
+	transportMutex.Lock()
+	networkConfig = cfg
+	sharedTransport = nil
+	transportErr = nil
+	transportMutex.Unlock()
+}
+
+// New returns an HTTP client with the given timeout that uses the shared transport and adds the
+// credentials registered with SetAuth.
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport:     &authTransport{},
+		CheckRedirect: checkRedirect,
+		Timeout:       timeout,
+	}
+}
+
+// Transport returns the shared transport, building it from the settings passed to Configure on first use.
+// Returns an error for unreadable CA bundles or client certificates, invalid proxy URLs, or unknown TLS versions.
+func Transport() (http.RoundTripper, error) {
+	transportMutex.Lock()
+	defer transportMutex.Unlock()
+
+	if sharedTransport == nil && transportErr == nil {
+		transport, err := NewTransport(networkConfig)
+		if err != nil {
+			transportErr = fmt.Errorf("invalid network configuration: %w", err)
+		} else {
+			sharedTransport = transport
+		}
+	}
+	return sharedTransport, transportErr
+}
+
+// NewDefaultTransport returns a transport with Go's defaults, honouring the proxy environment variables.
+func NewDefaultTransport() *http.Transport {
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
+
+// NewTransport creates a transport with the proxy, CA bundle, client certificate, and minimum TLS version from cfg.
+// Settings left empty keep Go's defaults; proxy settings fall back to HTTP_PROXY, HTTPS_PROXY, and NO_PROXY.
+// Returns the transport or an error for invalid settings.
+func NewTransport(cfg _config.NetworkConfig) (*http.Transport, error) {
+	transport := NewDefaultTransport()
+
+	proxy, err := proxyFunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxy
+
+	tlsConfig := &tls.Config{}
+
+	if cfg.CABundle != "" {
+		pool, err := loadCABundle(cfg.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, fmt.Errorf("network.client_cert and network.client_key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.TLSMinVersion != "" {
+		version, ok := tlsVersions[cfg.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid network.tls_min_version %q: expected 1.0, 1.1, 1.2, or 1.3", cfg.TLSMinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// proxyFunc returns the proxy selection for cfg. Each setting left empty falls back to its environment
+// variable, and https_proxy defaults to http_proxy so one corporate proxy only has to be set once.
+func proxyFunc(cfg _config.NetworkConfig) (func(*http.Request) (*url.URL, error), error) {
+	if cfg.HTTPProxy == "" && cfg.HTTPSProxy == "" && cfg.NoProxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyConfig := httpproxy.FromEnvironment()
+	if cfg.HTTPProxy != "" {
+		proxyConfig.HTTPProxy = cfg.HTTPProxy
+		proxyConfig.HTTPSProxy = cfg.HTTPProxy
+	}
+	if cfg.HTTPSProxy != "" {
+		proxyConfig.HTTPSProxy = cfg.HTTPSProxy
+	}
+	if cfg.NoProxy != "" {
+		proxyConfig.NoProxy = cfg.NoProxy
+	}
+
+	for name, value := range map[string]string{"http_proxy": proxyConfig.HTTPProxy, "https_proxy": proxyConfig.HTTPSProxy} {
+		if value == "" {
+			continue
+		}
+		if _, err := url.Parse(value); err != nil {
+			return nil, fmt.Errorf("invalid network.%s: %w", name, err)
+		}
+	}
+
+	proxy := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+// loadCABundle returns the system certificate pool extended with the PEM certificates in path.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
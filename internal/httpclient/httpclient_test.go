@@ -0,0 +1,262 @@
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_config "github.com/sijunda/govman/internal/config"
+)
+
+// writeClientCert creates a self-signed client certificate and key in dir and returns their paths and the certificate
+func writeClientCert(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "govman-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	certPath := filepath.Join(dir, "client.crt")
+	keyPath := filepath.Join(dir, "client.key")
+	os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+
+	return certPath, keyPath, cert
+}
+
+// writeServerCA writes the certificate of a TLS test server to a PEM bundle and returns its path
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+	return path
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("HTTPS_PROXY", "")
+	t.Setenv("NO_PROXY", "")
+
+	testCases := []struct {
+		name     string
+		config   _config.NetworkConfig
+		url      string
+		expected string
+	}{
+		{
+			name:     "HTTP proxy",
+			config:   _config.NetworkConfig{HTTPProxy: "http://proxy.corp:3128"},
+			url:      "http://go.dev/dl/",
+			expected: "http://proxy.corp:3128",
+		},
+		{
+			name:     "HTTPS defaults to the HTTP proxy",
+			config:   _config.NetworkConfig{HTTPProxy: "http://proxy.corp:3128"},
+			url:      "https://go.dev/dl/",
+			expected: "http://proxy.corp:3128",
+		},
+		{
+			name:     "Separate HTTPS proxy",
+			config:   _config.NetworkConfig{HTTPProxy: "http://proxy.corp:3128", HTTPSProxy: "http://secure.corp:3129"},
+			url:      "https://go.dev/dl/",
+			expected: "http://secure.corp:3129",
+		},
+		{
+			name:   "NO_PROXY domain",
+			config: _config.NetworkConfig{HTTPProxy: "http://proxy.corp:3128", NoProxy: "internal.corp,.example.com"},
+			url:    "https://mirror.example.com/go/index.json",
+		},
+		{
+			name:   "No proxy configured",
+			config: _config.NetworkConfig{},
+			url:    "https://go.dev/dl/",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport, err := NewTransport(tc.config)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+			proxyURL, err := transport.Proxy(req)
+			if err != nil {
+				t.Fatalf("Unexpected proxy error: %v", err)
+			}
+
+			got := ""
+			if proxyURL != nil {
+				got = proxyURL.String()
+			}
+			if got != tc.expected {
+				t.Errorf("Expected proxy %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewTransport_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	t.Run("Untrusted server is rejected", func(t *testing.T) {
+		transport, _ := NewTransport(_config.NetworkConfig{})
+		client := &http.Client{Transport: transport}
+		if _, err := client.Get(server.URL); err == nil {
+			t.Error("Expected certificate error without CA bundle")
+		}
+	})
+
+	t.Run("CA bundle trusts the server", func(t *testing.T) {
+		transport, err := NewTransport(_config.NetworkConfig{CABundle: writeServerCA(t, server)})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	})
+
+	t.Run("Invalid bundle", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "empty.pem")
+		os.WriteFile(path, []byte("not a certificate"), 0644)
+		if _, err := NewTransport(_config.NetworkConfig{CABundle: path}); err == nil {
+			t.Error("Expected error for bundle without certificates")
+		}
+	})
+}
+
+func TestNewTransport_ClientCertificate(t *testing.T) {
+	certPath, keyPath, cert := writeClientCert(t, t.TempDir())
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caBundle := writeServerCA(t, server)
+
+	t.Run("Without certificate", func(t *testing.T) {
+		transport, _ := NewTransport(_config.NetworkConfig{CABundle: caBundle})
+		if _, err := (&http.Client{Transport: transport}).Get(server.URL); err == nil {
+			t.Error("Expected handshake failure without client certificate")
+		}
+	})
+
+	t.Run("With certificate", func(t *testing.T) {
+		transport, err := NewTransport(_config.NetworkConfig{CABundle: caBundle, ClientCert: certPath, ClientKey: keyPath})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	})
+
+	t.Run("Key without certificate", func(t *testing.T) {
+		if _, err := NewTransport(_config.NetworkConfig{ClientKey: keyPath}); err == nil {
+			t.Error("Expected error when only the key is set")
+		}
+	})
+}
+
+func TestNewTransport_TLSMinVersion(t *testing.T) {
+	transport, err := NewTransport(_config.NetworkConfig{TLSMinVersion: "1.3"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transport.TLSClientConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("Expected TLS 1.3 minimum, got %x", transport.TLSClientConfig.MinVersion)
+	}
+
+	if _, err := NewTransport(_config.NetworkConfig{TLSMinVersion: "1.4"}); err == nil {
+		t.Error("Expected error for unknown TLS version")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	defer Configure(_config.NetworkConfig{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	Configure(_config.NetworkConfig{TLSMinVersion: "1.2"})
+
+	client := New(5 * time.Second)
+	if _, ok := client.Transport.(*authTransport); !ok {
+		t.Fatalf("Expected clients to add credentials, got %T", client.Transport)
+	}
+	if client.Timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s, got %v", client.Timeout)
+	}
+
+	base, err := Transport()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok || transport.TLSClientConfig.MinVersion != tls.VersionTLS12 {
+		t.Error("Expected clients to use the configured transport")
+	}
+	if again, _ := Transport(); again != base {
+		t.Error("Expected the transport to be built once and shared")
+	}
+
+	// Invalid settings only fail requests, not Configure
+	Configure(_config.NetworkConfig{TLSMinVersion: "bogus"})
+	if _, err := New(5 * time.Second).Get(server.URL); err == nil || !strings.Contains(err.Error(), "invalid network configuration") {
+		t.Errorf("Expected request to fail with the configuration error, got %v", err)
+	}
+
+	Configure(_config.NetworkConfig{})
+	resp, err := New(5 * time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected a valid configuration to replace the invalid one, got %v", err)
+	}
+	resp.Body.Close()
+}
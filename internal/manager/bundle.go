@@ -0,0 +1,183 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	_bundle "github.com/sijunda/govman/internal/bundle"
+	_golang "github.com/sijunda/govman/internal/golang"
+	_logger "github.com/sijunda/govman/internal/logger"
+)
+
+// Platform is a GOOS/GOARCH pair such as linux/amd64.
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// String returns the platform in "os/arch" form.
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// ParsePlatforms parses a list of "os/arch" strings, also accepting comma-separated entries.
+// Returns the platforms or an error for malformed entries.
+func ParsePlatforms(values []string) ([]Platform, error) {
+	var platforms []Platform
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+
+			goos, goarch, ok := strings.Cut(entry, "/")
+			if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+				return nil, fmt.Errorf("invalid platform %q: expected os/arch such as linux/amd64", entry)
+			}
+			platforms = append(platforms, Platform{OS: goos, Arch: goarch})
+		}
+	}
+
+	return platforms, nil
+}
+
+// SelectReleases returns the releases of the configured source matching constraint, reduced to the
+// archives of the requested platforms. Unstable releases are only considered when includeUnstable is set.
+// A version that is not published for some of the platforms is kept with the archives that exist.
+// Module proxy sources are refused: mirrors and bundles are indexed by SHA-256, which module zips do not have.
+// Returns the selected releases (newest first) or an error if nothing matches.
+func (m *Manager) SelectReleases(constraint string, platforms []Platform, includeUnstable bool) ([]_golang.Release, error) {
+	if m.config.GoReleases.Source == _golang.SourceGoProxy {
+		return nil, fmt.Errorf("the goproxy source cannot be mirrored or bundled; mirror the module proxy itself instead")
+	}
+
+	c, err := _golang.ParseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := _golang.SourceFromConfig(m.config.GoReleases, m.config.CacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release source: %w", err)
+	}
+
+	releases, err := _golang.GetReleasesFromSource(source, m.config.GoReleases.CacheExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+
+	var selected []_golang.Release
+	for _, release := range releases {
+		if !release.Stable && !includeUnstable {
+			continue
+		}
+		if !c.Match(release.Version) {
+			continue
+		}
+
+		version := strings.TrimPrefix(release.Version, "go")
+		subset := _golang.Release{Version: release.Version, Stable: release.Stable}
+		for _, platform := range platforms {
+			file := _golang.FindArchive(releases, version, platform.OS, platform.Arch)
+			if file == nil {
+				_logger.Warning("Go %s is not available for %s, skipping", version, platform)
+				continue
+			}
+			if !containsFile(subset.Files, file.Filename) {
+				subset.Files = append(subset.Files, *file)
+			}
+		}
+
+		if len(subset.Files) > 0 {
+			selected = append(selected, subset)
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no releases match %q for the requested platforms", constraint)
+	}
+
+	return selected, nil
+}
+
+// CreateBundle downloads and verifies the archives of the releases matching constraint for platforms
+// and packs them, with the matching release index subset and checksums, into a bundle at outPath.
+// Returns the bundle manifest or an error.
+func (m *Manager) CreateBundle(constraint string, platforms []Platform, includeUnstable bool, outPath string) (*_bundle.Manifest, error) {
+	releases, err := m.SelectReleases(constraint, platforms, includeUnstable)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := _golang.SourceFromConfig(m.config.GoReleases, m.config.CacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release source: %w", err)
+	}
+
+	manifest := &_bundle.Manifest{
+		FormatVersion: _bundle.FormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		Source:        source.ID(),
+		Constraint:    constraint,
+	}
+	for _, platform := range platforms {
+		manifest.Platforms = append(manifest.Platforms, platform.String())
+	}
+
+	archives := make(map[string]string)
+	for _, release := range releases {
+		manifest.Versions = append(manifest.Versions, strings.TrimPrefix(release.Version, "go"))
+		for _, file := range release.Files {
+			_logger.Info("Fetching %s...", file.Filename)
+			archivePath, err := m.downloader.Prefetch(source.DownloadURL(file), &file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch %s: %w", file.Filename, err)
+			}
+			archives[file.Filename] = archivePath
+		}
+	}
+
+	_logger.Progress("Writing bundle %s", outPath)
+	if err := _bundle.Write(outPath, manifest, releases, archives); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// ImportBundle verifies and unpacks the archives of a bundle into the cache directory and merges its
+// releases into the persisted index of the configured release source, so installs work without network access.
+// Returns the bundle manifest, or _golang.ErrSeedRequiresSignature when go_releases.require_signature is enabled.
+func (m *Manager) ImportBundle(bundlePath string) (*_bundle.Manifest, error) {
+	source, err := _golang.SourceFromConfig(m.config.GoReleases, m.config.CacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release source: %w", err)
+	}
+	// Check before unpacking so a bundle that cannot be used leaves the cache untouched
+	if _golang.RequiresSignature(source) {
+		return nil, _golang.ErrSeedRequiresSignature
+	}
+
+	manifest, releases, err := _bundle.Import(bundlePath, m.config.CacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import bundle: %w", err)
+	}
+
+	if err := _golang.SeedIndex(source, releases); err != nil {
+		return nil, fmt.Errorf("failed to seed release index: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// containsFile reports whether files already holds an entry named filename.
+func containsFile(files []_golang.File, filename string) bool {
+	for _, file := range files {
+		if file.Filename == filename {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       []string
+		expected    []Platform
+		expectError bool
+	}{
+		{
+			name:     "Comma separated",
+			input:    []string{"linux/amd64,linux/arm64"},
+			expected: []Platform{{"linux", "amd64"}, {"linux", "arm64"}},
+		},
+		{
+			name:     "Repeated flag",
+			input:    []string{"darwin/arm64", " windows/amd64 "},
+			expected: []Platform{{"darwin", "arm64"}, {"windows", "amd64"}},
+		},
+		{name: "Missing arch", input: []string{"linux"}, expectError: true},
+		{name: "Too many parts", input: []string{"linux/amd64/v3"}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			platforms, err := ParsePlatforms(tc.input)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if fmt.Sprint(platforms) != fmt.Sprint(tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, platforms)
+			}
+		})
+	}
+}
+
+func TestManager_BundleRoundTrip(t *testing.T) {
+	archives := map[string][]byte{}
+	var releases []_golang.Release
+	for _, version := range []string{"1.22.1", "1.21.5", "1.20.14"} {
+		release := _golang.Release{Version: "go" + version, Stable: true}
+		for _, platform := range []string{"linux-amd64", "linux-arm64", "darwin-arm64"} {
+			name := fmt.Sprintf("go%s.%s.tar.gz", version, platform)
+			content := []byte("archive " + name)
+			archives[name] = content
+			goos, goarch, _ := strings.Cut(platform, "-")
+			release.Files = append(release.Files, _golang.File{
+				Filename: name,
+				OS:       goos,
+				Arch:     goarch,
+				Version:  "go" + version,
+				Sha256:   fmt.Sprintf("%x", sha256.Sum256(content)),
+				Size:     int64(len(content)),
+				Kind:     "archive",
+			})
+		}
+		releases = append(releases, release)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index.json" {
+			json.NewEncoder(w).Encode(releases)
+			return
+		}
+		content, ok := archives[strings.TrimPrefix(r.URL.Path, "/dl/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	_golang.ClearReleasesCache()
+	defer _golang.ClearReleasesCache()
+	defer _golang.SetIndexDir("")
+
+	// Online machine creates the bundle
+	online := createTestConfig(t)
+	online.GoReleases.APIURL = server.URL + "/index.json"
+	online.GoReleases.DownloadURL = server.URL + "/dl/%s"
+	online.Download.RetryCount = 1
+	_golang.SetIndexDir(online.CacheDir)
+
+	platforms := []Platform{{"linux", "amd64"}, {"linux", "arm64"}}
+	bundlePath := filepath.Join(t.TempDir(), "go-bundle.tar")
+	manifest, err := createTestManager(t, online).CreateBundle(">=1.21", platforms, false, bundlePath)
+	if err != nil {
+		t.Fatalf("Unexpected error creating bundle: %v", err)
+	}
+	if strings.Join(manifest.Versions, ",") != "1.22.1,1.21.5" {
+		t.Errorf("Unexpected bundled versions: %v", manifest.Versions)
+	}
+
+	// Disconnected machine imports it
+	offline := createTestConfig(t)
+	offline.GoReleases.APIURL = server.URL + "/index.json"
+	offline.GoReleases.DownloadURL = server.URL + "/dl/%s"
+	_golang.SetIndexDir(offline.CacheDir)
+	_golang.ClearReleasesCache()
+	_golang.SetOffline(true)
+	defer _golang.SetOffline(false)
+
+	if _, err := createTestManager(t, offline).ImportBundle(bundlePath); err != nil {
+		t.Fatalf("Unexpected error importing bundle: %v", err)
+	}
+
+	for _, name := range []string{"go1.22.1.linux-amd64.tar.gz", "go1.21.5.linux-arm64.tar.gz"} {
+		if _, err := os.Stat(filepath.Join(offline.CacheDir, name)); err != nil {
+			t.Errorf("Expected %s in cache: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(offline.CacheDir, "go1.22.1.darwin-arm64.tar.gz")); !os.IsNotExist(err) {
+		t.Error("Expected archives of unselected platforms to be left out")
+	}
+
+	source, err := _golang.SourceFromConfig(offline.GoReleases, offline.CacheDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	file, err := _golang.GetPlatformFileInfoFromSource(source, "1.21.5", "linux", "amd64", offline.GoReleases.CacheExpiry)
+	if err != nil {
+		t.Fatalf("Expected imported index to be served offline: %v", err)
+	}
+	if file.Filename != "go1.21.5.linux-amd64.tar.gz" {
+		t.Errorf("Unexpected file: %+v", file)
+	}
+	if _, err := _golang.GetPlatformFileInfoFromSource(source, "1.20.14", "linux", "amd64", offline.GoReleases.CacheExpiry); err == nil {
+		t.Error("Expected versions outside the constraint to be absent")
+	}
+}
@@ -0,0 +1,314 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+	_logger "github.com/sijunda/govman/internal/logger"
+)
+
+// Cache entry states reported by CacheEntry.Status.
+const (
+	CacheComplete = "complete"
+	CachePartial  = "partial"
+	CacheCorrupt  = "corrupt"
+	CacheUnknown  = "unknown"
+	// CacheUnrecognised marks an archive whose version can be derived neither from its name nor from the index.
+	CacheUnrecognised = "unrecognised"
+)
+
+// CacheEntry describes a Go archive in the download cache.
+type CacheEntry struct {
+	Filename string
+	Path     string
+	Version  string
+	OS       string
+	Arch     string
+	Size     int64
+	ModTime  time.Time
+	// Expected is the archive as listed by the release index, or nil when the index does not list it.
+	Expected  *_golang.File
+	Installed bool
+}
+
+// Status reports whether the archive is complete, partial (an interrupted download that can be resumed),
+// corrupt (larger than expected), unknown (not listed in the release index), or unrecognised (no known version).
+func (e CacheEntry) Status() string {
+	switch {
+	case e.Version == "":
+		return CacheUnrecognised
+	case e.Expected == nil:
+		return CacheUnknown
+	case e.Size == e.Expected.Size:
+		return CacheComplete
+	case e.Size < e.Expected.Size:
+		return CachePartial
+	default:
+		return CacheCorrupt
+	}
+}
+
+// Age returns how long ago the archive was last written.
+func (e CacheEntry) Age() time.Duration {
+	return time.Since(e.ModTime)
+}
+
+// CacheVerifyResult summarises a cache verification.
+type CacheVerifyResult struct {
+	Valid   []CacheEntry
+	Corrupt []CacheEntry
+	Skipped []CacheEntry
+	Freed   int64
+}
+
+// CachePruneOptions selects the archives removed by PruneCache.
+type CachePruneOptions struct {
+	// OlderThan removes archives not written for longer than this; zero disables the check.
+	OlderThan time.Duration
+	// MaxSize removes the oldest archives until the cache is at most this many bytes; zero disables the check.
+	MaxSize int64
+	// KeepInstalled never removes archives of installed versions.
+	KeepInstalled bool
+	// DryRun reports what would be removed without deleting anything.
+	DryRun bool
+}
+
+// CachePruneResult summarises a cache prune.
+type CachePruneResult struct {
+	Removed   []CacheEntry
+	Freed     int64
+	Remaining int64
+}
+
+// CacheEntries lists the Go archives in the cache directory, newest version first, matched against the
+// release index of the configured source. Official archive names, goproxy toolchain zips, and any name the
+// index lists are recognised; other files named like <os>-<arch> archives are listed as unrecognised so they
+// can still be pruned. If the index cannot be loaded, entries are returned with an unknown status.
+// Returns the entries or an error if the cache directory cannot be read.
+func (m *Manager) CacheEntries() ([]CacheEntry, error) {
+	dirEntries, err := os.ReadDir(m.config.CacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []CacheEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	index := m.cacheIndex()
+
+	entries := []CacheEntry{}
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		file, ok := cachedArchive(name, index)
+		if !ok || !dirEntry.Type().IsRegular() {
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+
+		version := strings.TrimPrefix(file.Version, "go")
+		entry := CacheEntry{
+			Filename:  name,
+			Path:      filepath.Join(m.config.CacheDir, name),
+			Version:   version,
+			OS:        file.OS,
+			Arch:      file.Arch,
+			Size:      info.Size(),
+			ModTime:   info.ModTime(),
+			Installed: version != "" && m.IsInstalled(version),
+		}
+		if expected, ok := index[name]; ok {
+			entry.Expected = &expected
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if cmp := _golang.CompareVersions(entries[i].Version, entries[j].Version); cmp != 0 {
+			return cmp > 0
+		}
+		return entries[i].Filename < entries[j].Filename
+	})
+
+	return entries, nil
+}
+
+// VerifyCache re-hashes every complete archive in the cache against the release index and removes the
+// ones that do not match, unless dryRun is set. Partial downloads and archives missing from the index are skipped.
+// Returns the verification summary or an error.
+func (m *Manager) VerifyCache(dryRun bool) (*CacheVerifyResult, error) {
+	entries, err := m.CacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CacheVerifyResult{}
+	for _, entry := range entries {
+		switch entry.Status() {
+		case CacheComplete:
+			sum, err := fileSHA256(entry.Path)
+			if err != nil {
+				return result, err
+			}
+			if strings.EqualFold(sum, entry.Expected.Sha256) {
+				result.Valid = append(result.Valid, entry)
+				continue
+			}
+			_logger.Verbose("%s: expected sha256 %s, got %s", entry.Filename, entry.Expected.Sha256, sum)
+		case CacheCorrupt:
+		default:
+			result.Skipped = append(result.Skipped, entry)
+			continue
+		}
+
+		result.Corrupt = append(result.Corrupt, entry)
+		freed, err := removeCacheEntry(entry, dryRun)
+		result.Freed += freed
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// PruneCache removes cached archives older than opts.OlderThan, then the oldest remaining archives until the
+// cache fits in opts.MaxSize. Archives of installed versions are kept when opts.KeepInstalled is set.
+// Returns the prune summary or an error.
+func (m *Manager) PruneCache(opts CachePruneOptions) (*CachePruneResult, error) {
+	if opts.OlderThan <= 0 && opts.MaxSize <= 0 {
+		return nil, fmt.Errorf("nothing to prune: set a maximum age or a maximum cache size")
+	}
+
+	entries, err := m.CacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	// Oldest first, so the size limit removes the least recently downloaded archives
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].ModTime.Before(entries[j].ModTime)
+	})
+
+	result := &CachePruneResult{}
+	var candidates []CacheEntry
+	for _, entry := range entries {
+		result.Remaining += entry.Size
+		if opts.KeepInstalled && entry.Installed {
+			continue
+		}
+		candidates = append(candidates, entry)
+	}
+
+	for _, entry := range candidates {
+		expired := opts.OlderThan > 0 && entry.Age() > opts.OlderThan
+		oversize := opts.MaxSize > 0 && result.Remaining > opts.MaxSize
+		if !expired && !oversize {
+			continue
+		}
+
+		freed, err := removeCacheEntry(entry, opts.DryRun)
+		result.Removed = append(result.Removed, entry)
+		result.Freed += freed
+		result.Remaining -= entry.Size
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// cacheIndex returns the release index of the configured source keyed by archive file name.
+// An index that cannot be loaded yields an empty map with a warning.
+func (m *Manager) cacheIndex() map[string]_golang.File {
+	index := make(map[string]_golang.File)
+
+	source, err := _golang.SourceFromConfig(m.config.GoReleases, m.config.CacheDir)
+	if err != nil {
+		_logger.Warning("Invalid release source, archives cannot be checked: %v", err)
+		return index
+	}
+
+	releases, err := _golang.GetReleasesFromSource(source, m.config.GoReleases.CacheExpiry)
+	if err != nil {
+		_logger.Warning("Release index unavailable, archives cannot be checked: %v", err)
+		return index
+	}
+
+	for _, release := range releases {
+		for _, file := range release.Files {
+			index[file.Filename] = file
+		}
+	}
+	return index
+}
+
+// cachedArchive identifies a file in the cache directory as a Go archive, using the release index first so
+// that source-specific asset names resolve to their version. Files whose version cannot be determined but
+// whose name ends in <os>-<arch>.<ext> are returned without a version. Returns false for other files.
+func cachedArchive(name string, index map[string]_golang.File) (_golang.File, bool) {
+	if file, ok := index[name]; ok {
+		return file, true
+	}
+	if file, ok := _golang.ParseArchiveName(name); ok {
+		return file, true
+	}
+	if file, ok := _golang.ParseToolchainZipName(name); ok {
+		return file, true
+	}
+	if osName, arch, ok := _golang.ParseAssetPlatform(name); ok {
+		return _golang.File{Filename: name, OS: osName, Arch: arch, Kind: "archive"}, true
+	}
+	return _golang.File{}, false
+}
+
+// removeCacheEntry deletes a cached archive and its detached signature, unless dryRun is set.
+// Returns the number of bytes freed (or that would be freed) and any removal error.
+func removeCacheEntry(entry CacheEntry, dryRun bool) (int64, error) {
+	freed := entry.Size
+	sigPath := entry.Path + _golang.ArchiveSignatureSuffix
+	sigStat, sigErr := os.Stat(sigPath)
+	if sigErr == nil {
+		freed += sigStat.Size()
+	}
+
+	if dryRun {
+		return freed, nil
+	}
+
+	if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to remove %s: %w", entry.Filename, err)
+	}
+	if sigErr == nil {
+		os.Remove(sigPath)
+	}
+
+	return freed, nil
+}
+
+// fileSHA256 returns the hex SHA-256 of the file at path.
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", filepath.Base(path), err)
+	}
+
+	return fmt.Sprintf("%x", hasher.Sum(nil)), nil
+}
@@ -0,0 +1,302 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_config "github.com/sijunda/govman/internal/config"
+	_golang "github.com/sijunda/govman/internal/golang"
+)
+
+// createTestCache fills the cache with archives in every state and points the release source at a local index
+func createTestCache(t *testing.T) *_config.Config {
+	t.Helper()
+
+	config := createTestConfig(t)
+	indexDir := t.TempDir()
+	config.GoReleases.Source = "file"
+	config.GoReleases.APIURL = filepath.Join(indexDir, "index.json")
+
+	archives := []struct {
+		name    string
+		content string
+		cached  string
+		age     time.Duration
+		listed  bool
+	}{
+		{name: "go1.22.1.linux-amd64.tar.gz", content: "go 1.22.1", cached: "go 1.22.1", age: 40 * 24 * time.Hour, listed: true},
+		{name: "go1.21.5.linux-amd64.tar.gz", content: "go 1.21.5", cached: "go 1.21.X", age: 10 * 24 * time.Hour, listed: true},
+		{name: "go1.20.14.linux-amd64.tar.gz", content: "go 1.20.14", cached: "go 1.2", age: 24 * time.Hour, listed: true},
+		{name: "go1.19.1.linux-amd64.tar.gz", cached: "go 1.19.1", age: 60 * 24 * time.Hour},
+	}
+
+	var files []_golang.File
+	for _, archive := range archives {
+		path := filepath.Join(config.CacheDir, archive.name)
+		if err := os.WriteFile(path, []byte(archive.cached), 0644); err != nil {
+			t.Fatalf("Failed to write archive: %v", err)
+		}
+		mtime := time.Now().Add(-archive.age)
+		os.Chtimes(path, mtime, mtime)
+
+		if archive.listed {
+			file, _ := _golang.ParseArchiveName(archive.name)
+			file.Size = int64(len(archive.content))
+			file.Sha256 = fmt.Sprintf("%x", sha256.Sum256([]byte(archive.content)))
+			files = append(files, file)
+		}
+	}
+	os.WriteFile(filepath.Join(config.CacheDir, "notes.txt"), []byte("not an archive"), 0644)
+	os.WriteFile(filepath.Join(config.CacheDir, "go1.19.1.linux-amd64.tar.gz.asc"), []byte("sig"), 0644)
+
+	data, _ := json.Marshal(_golang.ReleasesFromFiles(files))
+	if err := os.WriteFile(config.GoReleases.APIURL, data, 0644); err != nil {
+		t.Fatalf("Failed to write index: %v", err)
+	}
+
+	os.MkdirAll(config.GetVersionDir("1.22.1"), 0755)
+
+	_golang.ClearReleasesCache()
+	t.Cleanup(_golang.ClearReleasesCache)
+
+	return config
+}
+
+func TestManager_CacheEntries(t *testing.T) {
+	config := createTestCache(t)
+
+	entries, err := createTestManager(t, config).CacheEntries()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		version   string
+		status    string
+		installed bool
+	}{
+		{"1.22.1", CacheComplete, true},
+		{"1.21.5", CacheComplete, false},
+		{"1.20.14", CachePartial, false},
+		{"1.19.1", CacheUnknown, false},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, want := range expected {
+		entry := entries[i]
+		if entry.Version != want.version || entry.Status() != want.status || entry.Installed != want.installed {
+			t.Errorf("Entry %d: expected %s %s installed=%v, got %s %s installed=%v",
+				i, want.version, want.status, want.installed, entry.Version, entry.Status(), entry.Installed)
+		}
+		if entry.OS != "linux" || entry.Arch != "amd64" {
+			t.Errorf("Entry %d: unexpected platform %s/%s", i, entry.OS, entry.Arch)
+		}
+	}
+}
+
+func TestManager_CacheEntries_ArchiveNames(t *testing.T) {
+	config := createTestConfig(t)
+	indexDir := t.TempDir()
+	config.GoReleases.Source = "file"
+	config.GoReleases.APIURL = filepath.Join(indexDir, "index.json")
+
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	archives := []struct {
+		name   string
+		listed *_golang.File
+	}{
+		{name: "v0.0.1-go1.22.1.linux-amd64.zip"},
+		{name: "go-fork-linux-amd64.tar.gz", listed: &_golang.File{OS: "linux", Arch: "amd64", Version: "go1.21.5"}},
+		{name: "stale-fork-linux-arm64.tar.gz"},
+		{name: "notes.txt"},
+	}
+
+	var files []_golang.File
+	for _, archive := range archives {
+		path := filepath.Join(config.CacheDir, archive.name)
+		if err := os.WriteFile(path, []byte(archive.name), 0644); err != nil {
+			t.Fatalf("Failed to write archive: %v", err)
+		}
+		os.Chtimes(path, old, old)
+
+		if archive.listed != nil {
+			file := *archive.listed
+			file.Filename = archive.name
+			file.Kind = "archive"
+			file.Size = int64(len(archive.name))
+			file.Sha256 = fmt.Sprintf("%x", sha256.Sum256([]byte(archive.name)))
+			files = append(files, file)
+		}
+	}
+
+	data, _ := json.Marshal(_golang.ReleasesFromFiles(files))
+	if err := os.WriteFile(config.GoReleases.APIURL, data, 0644); err != nil {
+		t.Fatalf("Failed to write index: %v", err)
+	}
+	_golang.ClearReleasesCache()
+	t.Cleanup(_golang.ClearReleasesCache)
+
+	manager := createTestManager(t, config)
+	entries, err := manager.CacheEntries()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		filename string
+		version  string
+		platform string
+		status   string
+	}{
+		{"v0.0.1-go1.22.1.linux-amd64.zip", "1.22.1", "linux/amd64", CacheUnknown},
+		{"go-fork-linux-amd64.tar.gz", "1.21.5", "linux/amd64", CacheComplete},
+		{"stale-fork-linux-arm64.tar.gz", "", "linux/arm64", CacheUnrecognised},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, want := range expected {
+		entry := entries[i]
+		if entry.Filename != want.filename || entry.Version != want.version || entry.OS+"/"+entry.Arch != want.platform || entry.Status() != want.status {
+			t.Errorf("Entry %d: expected %s %s %s %s, got %s %s %s/%s %s", i, want.filename, want.version, want.platform, want.status,
+				entry.Filename, entry.Version, entry.OS, entry.Arch, entry.Status())
+		}
+	}
+
+	t.Run("Verify skips unrecognised archives", func(t *testing.T) {
+		result, err := manager.VerifyCache(true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Valid) != 1 || len(result.Corrupt) != 0 || len(result.Skipped) != 2 {
+			t.Errorf("Unexpected result: valid=%d corrupt=%d skipped=%d", len(result.Valid), len(result.Corrupt), len(result.Skipped))
+		}
+	})
+
+	t.Run("Prune removes every recognised and unrecognised archive", func(t *testing.T) {
+		result, err := manager.PruneCache(CachePruneOptions{OlderThan: 30 * 24 * time.Hour})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Removed) != 3 {
+			t.Errorf("Expected 3 archives to be removed, got %d", len(result.Removed))
+		}
+		if _, err := os.Stat(filepath.Join(config.CacheDir, "notes.txt")); err != nil {
+			t.Error("Expected files that are not archives to be kept")
+		}
+	})
+}
+
+func TestManager_VerifyCache(t *testing.T) {
+	t.Run("Dry run keeps corrupt archives", func(t *testing.T) {
+		config := createTestCache(t)
+
+		result, err := createTestManager(t, config).VerifyCache(true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Valid) != 1 || len(result.Corrupt) != 1 || len(result.Skipped) != 2 {
+			t.Fatalf("Unexpected result: valid=%d corrupt=%d skipped=%d", len(result.Valid), len(result.Corrupt), len(result.Skipped))
+		}
+		if result.Corrupt[0].Version != "1.21.5" || result.Freed != 9 {
+			t.Errorf("Expected go1.21.5 (9 bytes) to be corrupt, got %s (%d bytes)", result.Corrupt[0].Version, result.Freed)
+		}
+		if _, err := os.Stat(result.Corrupt[0].Path); err != nil {
+			t.Error("Expected dry run to keep the archive")
+		}
+	})
+
+	t.Run("Corrupt archives are removed", func(t *testing.T) {
+		config := createTestCache(t)
+
+		result, err := createTestManager(t, config).VerifyCache(false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Corrupt) != 1 {
+			t.Fatalf("Expected 1 corrupt archive, got %d", len(result.Corrupt))
+		}
+		if _, err := os.Stat(result.Corrupt[0].Path); !os.IsNotExist(err) {
+			t.Error("Expected corrupt archive to be removed")
+		}
+		if _, err := os.Stat(filepath.Join(config.CacheDir, "go1.20.14.linux-amd64.tar.gz")); err != nil {
+			t.Error("Expected partial download to be kept for resuming")
+		}
+	})
+}
+
+func TestManager_PruneCache(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     CachePruneOptions
+		expected []string
+		freed    int64
+	}{
+		{
+			name:     "Older than",
+			opts:     CachePruneOptions{OlderThan: 30 * 24 * time.Hour},
+			expected: []string{"1.19.1", "1.22.1"},
+			freed:    9 + 3 + 9,
+		},
+		{
+			name:     "Older than keeping installed",
+			opts:     CachePruneOptions{OlderThan: 30 * 24 * time.Hour, KeepInstalled: true},
+			expected: []string{"1.19.1"},
+			freed:    9 + 3,
+		},
+		{
+			name:     "Max size removes oldest first",
+			opts:     CachePruneOptions{MaxSize: 20},
+			expected: []string{"1.19.1", "1.22.1"},
+			freed:    9 + 3 + 9,
+		},
+		{
+			name:     "Max size keeping installed",
+			opts:     CachePruneOptions{MaxSize: 20, KeepInstalled: true},
+			expected: []string{"1.19.1", "1.21.5"},
+			freed:    9 + 3 + 9,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, dryRun := range []bool{true, false} {
+			t.Run(fmt.Sprintf("%s (dry run %v)", tc.name, dryRun), func(t *testing.T) {
+				config := createTestCache(t)
+				tc.opts.DryRun = dryRun
+
+				result, err := createTestManager(t, config).PruneCache(tc.opts)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				var removed []string
+				for _, entry := range result.Removed {
+					removed = append(removed, entry.Version)
+					if _, err := os.Stat(entry.Path); os.IsNotExist(err) != !dryRun {
+						t.Errorf("%s: unexpected presence after prune (dry run %v)", entry.Filename, dryRun)
+					}
+				}
+				if fmt.Sprint(removed) != fmt.Sprint(tc.expected) {
+					t.Errorf("Expected %v to be removed, got %v", tc.expected, removed)
+				}
+				if result.Freed != tc.freed {
+					t.Errorf("Expected %d bytes freed, got %d", tc.freed, result.Freed)
+				}
+			})
+		}
+	}
+
+	t.Run("No criteria", func(t *testing.T) {
+		if _, err := createTestManager(t, createTestCache(t)).PruneCache(CachePruneOptions{}); err == nil {
+			t.Error("Expected error without age or size limit")
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_shell "github.com/sijunda/govman/internal/shell"
+)
+
+// ActivationVar is the session variable recording how the version in GOVMAN_VERSION was activated.
+const ActivationVar = "GOVMAN_ACTIVATION"
+
+// SavedToolchainVar is the session variable holding the GOTOOLCHAIN value from before govman activated a
+// version, restored by ResetEnv.
+const SavedToolchainVar = "GOVMAN_SAVED_GOTOOLCHAIN"
+
+// Activation methods reported by CurrentActivationMethod
+const (
+	ActivationSession   = "session-only"
+	ActivationLocal     = "project-local"
+	ActivationDefault   = "system-default"
+	ActivationUnmanaged = "not managed by govman"
+)
+
+// Env returns the environment changes that activate an installed version in the current session.
+// getenv reads the session's environment. PATH gets the version's bin directory first and loses
+// the bin directory of any other managed version, GOVMAN_PREVIOUS records the version being
+// replaced, and the first activation in a session saves GOTOOLCHAIN in GOVMAN_SAVED_GOTOOLCHAIN.
+// Returns an error if the version is not installed.
+func (m *Manager) Env(version string, getenv func(string) string) ([]_shell.EnvChange, error) {
+	activation := ActivationSession
+	if version == m.config.DefaultVersion {
+		activation = ActivationDefault
+	}
+	return m.activationEnv(version, activation, getenv)
+}
+
+// activationEnv returns the changes made by Env, recording activation in GOVMAN_ACTIVATION.
+func (m *Manager) activationEnv(version, activation string, getenv func(string) string) ([]_shell.EnvChange, error) {
+	if !m.IsInstalled(version) {
+		return nil, fmt.Errorf("go version %s is not installed. Run 'govman install %s' first", version, version)
+	}
+
+	versionDir := m.config.GetVersionDir(version)
+	binDir := filepath.Join(versionDir, "bin")
+
+	changes := []_shell.EnvChange{
+		{Name: "PATH", Value: _shell.PrependPath(m.stripVersionPaths(getenv("PATH")), binDir)},
+		{Name: "GOROOT", Value: versionDir},
+		{Name: "GOTOOLCHAIN", Value: "local"},
+		{Name: "GOVMAN_VERSION", Value: version},
+		{Name: ActivationVar, Value: activation},
+	}
+	if previous := m.sessionVersion(getenv); previous != "" && previous != version {
+		changes = append(changes, _shell.EnvChange{Name: PreviousVersionVar, Value: previous})
+	}
+	if getenv("GOVMAN_VERSION") == "" {
+		if toolchain := getenv("GOTOOLCHAIN"); toolchain != "" {
+			changes = append(changes, _shell.EnvChange{Name: SavedToolchainVar, Value: toolchain})
+		}
+	}
+
+	return changes, nil
+}
+
+// ResetEnv returns the environment changes that deactivate any session version, so the default takes over.
+// getenv reads the session's environment. Only variables that govman set are changed: GOTOOLCHAIN is
+// restored to the value saved at activation, or unset if there was none, unless it was changed since.
+func (m *Manager) ResetEnv(getenv func(string) string) []_shell.EnvChange {
+	var changes []_shell.EnvChange
+
+	pathList := getenv("PATH")
+	if stripped := m.stripVersionPaths(pathList); stripped != pathList {
+		changes = append(changes, _shell.EnvChange{Name: "PATH", Value: stripped})
+	}
+	if goroot := getenv("GOROOT"); goroot != "" && m.isVersionDir(goroot) {
+		changes = append(changes, _shell.EnvChange{Name: "GOROOT", Unset: true})
+	}
+	if version := getenv("GOVMAN_VERSION"); version != "" {
+		if getenv("GOTOOLCHAIN") == "local" {
+			if saved := getenv(SavedToolchainVar); saved == "" {
+				changes = append(changes, _shell.EnvChange{Name: "GOTOOLCHAIN", Unset: true})
+			} else if saved != "local" {
+				changes = append(changes, _shell.EnvChange{Name: "GOTOOLCHAIN", Value: saved})
+			}
+		}
+		changes = append(changes, _shell.EnvChange{Name: "GOVMAN_VERSION", Unset: true})
+		if version != m.config.DefaultVersion {
+			changes = append(changes, _shell.EnvChange{Name: PreviousVersionVar, Value: version})
+		}
+	}
+	if getenv(ActivationVar) != "" {
+		changes = append(changes, _shell.EnvChange{Name: ActivationVar, Unset: true})
+	}
+	if getenv(SavedToolchainVar) != "" {
+		changes = append(changes, _shell.EnvChange{Name: SavedToolchainVar, Unset: true})
+	}
+
+	return changes
+}
+
+// stripVersionPaths removes the bin directory of every managed version from pathList.
+func (m *Manager) stripVersionPaths(pathList string) string {
+	var entries []string
+	for _, entry := range filepath.SplitList(pathList) {
+		if filepath.Base(entry) == "bin" && m.isVersionDir(filepath.Dir(entry)) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return strings.Join(entries, string(os.PathListSeparator))
+}
+
+// isVersionDir reports whether dir is a version directory inside the install directory.
+func (m *Manager) isVersionDir(dir string) bool {
+	dir = filepath.Clean(dir)
+	return filepath.Dir(dir) == filepath.Clean(m.config.InstallDir) && strings.HasPrefix(filepath.Base(dir), "go")
+}
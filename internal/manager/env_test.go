@@ -0,0 +1,138 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManager_Env(t *testing.T) {
+	config := createTestConfig(t)
+	manager := createTestManager(t, config)
+
+	for _, version := range []string{"1.21.0", "1.22.1"} {
+		os.MkdirAll(filepath.Join(config.GetVersionDir(version), "bin"), 0755)
+	}
+
+	sep := string(os.PathListSeparator)
+	oldBin := filepath.Join(config.GetVersionDir("1.21.0"), "bin")
+	env := session{"PATH": oldBin + sep + "/usr/bin" + sep + oldBin}
+
+	t.Run("Activates version", func(t *testing.T) {
+		changes, err := manager.Env("1.22.1", env.getenv)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		env.apply(changes)
+
+		expectedPath := filepath.Join(config.GetVersionDir("1.22.1"), "bin") + sep + "/usr/bin"
+		if env["PATH"] != expectedPath {
+			t.Errorf("Expected PATH %s, got %s", expectedPath, env["PATH"])
+		}
+		if env["GOROOT"] != config.GetVersionDir("1.22.1") || env["GOTOOLCHAIN"] != "local" || env["GOVMAN_VERSION"] != "1.22.1" {
+			t.Errorf("Unexpected environment %+v", env)
+		}
+		if env[ActivationVar] != ActivationSession {
+			t.Errorf("Expected %s activation, got %s", ActivationSession, env[ActivationVar])
+		}
+	})
+
+	t.Run("Default version activation", func(t *testing.T) {
+		config.DefaultVersion = "1.22.1"
+		defer func() { config.DefaultVersion = "" }()
+
+		changes, _ := manager.Env("1.22.1", env.getenv)
+		env.apply(changes)
+		if env[ActivationVar] != ActivationDefault {
+			t.Errorf("Expected %s activation, got %s", ActivationDefault, env[ActivationVar])
+		}
+	})
+
+	t.Run("Version not installed", func(t *testing.T) {
+		if _, err := manager.Env("1.9.0", env.getenv); err == nil {
+			t.Error("Expected error for a version that is not installed")
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		env["GOROOT"] = config.GetVersionDir("1.22.1")
+		env.apply(manager.ResetEnv(env.getenv))
+
+		if env["PATH"] != "/usr/bin" || env["GOROOT"] != "" || env["GOTOOLCHAIN"] != "" || env["GOVMAN_VERSION"] != "" || env[ActivationVar] != "" {
+			t.Errorf("Expected managed variables to be removed, got %+v", env)
+		}
+		if changes := manager.ResetEnv(env.getenv); len(changes) != 0 {
+			t.Errorf("Expected no changes once reset, got %+v", changes)
+		}
+	})
+
+	t.Run("Reset restores GOTOOLCHAIN", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			initial  string
+			modified string
+			expected string
+		}{
+			{name: "Unset before activation", initial: "", expected: ""},
+			{name: "Saved value", initial: "go1.22.0+auto", expected: "go1.22.0+auto"},
+			{name: "Local before activation", initial: "local", expected: "local"},
+			{name: "Changed after activation", initial: "auto", modified: "go1.23.0", expected: "go1.23.0"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				env := session{"PATH": "/usr/bin"}
+				if tc.initial != "" {
+					env["GOTOOLCHAIN"] = tc.initial
+				}
+
+				for _, version := range []string{"1.21.0", "1.22.1"} {
+					changes, err := manager.Env(version, env.getenv)
+					if err != nil {
+						t.Fatalf("Unexpected error: %v", err)
+					}
+					env.apply(changes)
+				}
+				if tc.modified != "" {
+					env["GOTOOLCHAIN"] = tc.modified
+				}
+
+				env.apply(manager.ResetEnv(env.getenv))
+				if env["GOTOOLCHAIN"] != tc.expected {
+					t.Errorf("Expected GOTOOLCHAIN %q, got %q", tc.expected, env["GOTOOLCHAIN"])
+				}
+				if env[SavedToolchainVar] != "" {
+					t.Errorf("Expected %s to be removed, got %q", SavedToolchainVar, env[SavedToolchainVar])
+				}
+			})
+		}
+	})
+
+	t.Run("Reset keeps foreign GOROOT", func(t *testing.T) {
+		env["GOROOT"] = "/usr/local/go"
+		if changes := manager.ResetEnv(env.getenv); len(changes) != 0 {
+			t.Errorf("Expected GOROOT outside the install directory to be kept, got %+v", changes)
+		}
+	})
+}
+
+func TestManager_ProjectVersion(t *testing.T) {
+	config := createTestConfig(t)
+	config.AutoSwitch.ProjectFile = ".govman-version"
+	manager := createTestManager(t, config)
+
+	root := t.TempDir()
+	nested := filepath.Join(root, "pkg")
+	os.MkdirAll(nested, 0755)
+
+	if version, file := manager.ProjectVersion(nested); version != "" || file != "" {
+		t.Errorf("Expected no project version, got %s from %s", version, file)
+	}
+
+	os.WriteFile(filepath.Join(root, ".govman-version"), []byte(" 1.22.1\n"), 0644)
+	version, file := manager.ProjectVersion(nested)
+	if version != "1.22.1" || !strings.HasSuffix(file, ".govman-version") {
+		t.Errorf("Expected 1.22.1 from the parent project file, got %s from %s", version, file)
+	}
+}
@@ -0,0 +1,172 @@
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_logger "github.com/sijunda/govman/internal/logger"
+)
+
+// PreviousVersionVar is the session variable holding the version that was active before the last switch,
+// used by 'govman use -'.
+const PreviousVersionVar = "GOVMAN_PREVIOUS"
+
+// History events
+const (
+	HistoryUse        = "use"
+	HistoryDefault    = "use --default"
+	HistoryLocal      = "use --local"
+	HistoryAutoSwitch = "auto-switch"
+)
+
+// maxHistorySize is the size at which the history file is compacted to its most recent half
+const maxHistorySize = 256 * 1024
+
+// now returns the current time; overridden in tests
+var now = time.Now
+
+// ErrNoPreviousVersion is returned by PreviousVersion when the session has not switched versions yet.
+var ErrNoPreviousVersion = errors.New("no previous Go version in this session")
+
+// HistoryEntry is a version switch recorded in the history file.
+type HistoryEntry struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	Version  string    `json:"version"`
+	Previous string    `json:"previous,omitempty"`
+	Dir      string    `json:"dir,omitempty"`
+}
+
+// PreviousVersion returns the version that was active before the last switch in the session.
+// getenv reads the session's environment. Returns ErrNoPreviousVersion if there is none.
+func (m *Manager) PreviousVersion(getenv func(string) string) (string, error) {
+	previous := getenv(PreviousVersionVar)
+	if previous == "" {
+		return "", ErrNoPreviousVersion
+	}
+	return previous, nil
+}
+
+// sessionVersion returns the version active in the session: GOVMAN_VERSION, else the configured default.
+func (m *Manager) sessionVersion(getenv func(string) string) string {
+	if version := getenv("GOVMAN_VERSION"); version != "" {
+		return version
+	}
+	return m.config.DefaultVersion
+}
+
+// RecordHistory appends entry to the history file, setting its time if it is zero.
+// Once the file grows past maxHistorySize, the oldest entries are dropped.
+func (m *Manager) RecordHistory(entry HistoryEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = now()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	historyFile := m.config.GetHistoryFile()
+	if err := os.MkdirAll(filepath.Dir(historyFile), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	file, err := os.OpenFile(historyFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	if info, err := os.Stat(historyFile); err == nil && info.Size() > maxHistorySize {
+		return m.compactHistory()
+	}
+
+	return nil
+}
+
+// recordHistory records entry, logging instead of failing since history is informational.
+func (m *Manager) recordHistory(entry HistoryEntry) {
+	if err := m.RecordHistory(entry); err != nil {
+		_logger.Verbose("Failed to record history: %v", err)
+	}
+}
+
+// History returns the most recent history entries, oldest first. limit <= 0 returns all of them.
+// A missing history file yields no entries; malformed lines are skipped.
+func (m *Manager) History(limit int) ([]HistoryEntry, error) {
+	data, err := os.ReadFile(m.config.GetHistoryFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Version == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	return entries, nil
+}
+
+// compactHistory rewrites the history file with the most recent entries that fit in half of maxHistorySize.
+func (m *Manager) compactHistory() error {
+	entries, err := m.History(0)
+	if err != nil {
+		return err
+	}
+
+	var lines [][]byte
+	size := 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		line, err := json.Marshal(entries[i])
+		if err != nil {
+			return fmt.Errorf("failed to encode history entry: %w", err)
+		}
+		if size += len(line) + 1; size > maxHistorySize/2 {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	var buf bytes.Buffer
+	for i := len(lines) - 1; i >= 0; i-- {
+		buf.Write(lines[i])
+		buf.WriteByte('\n')
+	}
+
+	historyFile := m.config.GetHistoryFile()
+	tempFile := historyFile + ".tmp"
+	if err := os.WriteFile(tempFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := os.Rename(tempFile, historyFile); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to replace history file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package manager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManager_History(t *testing.T) {
+	config := createTestConfig(t)
+	manager := createTestManager(t, config)
+
+	originalNow := now
+	defer func() { now = originalNow }()
+	clock := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	now = func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}
+
+	t.Run("Empty history", func(t *testing.T) {
+		entries, err := manager.History(0)
+		if err != nil || len(entries) != 0 {
+			t.Errorf("Expected no entries, got %+v (%v)", entries, err)
+		}
+	})
+
+	t.Run("Records entries in order", func(t *testing.T) {
+		for _, version := range []string{"1.21.0", "1.22.1", "1.21.0"} {
+			if err := manager.RecordHistory(HistoryEntry{Event: HistoryUse, Version: version}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		entries, err := manager.History(2)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(entries) != 2 || entries[0].Version != "1.22.1" || entries[1].Version != "1.21.0" {
+			t.Errorf("Expected the last two entries, got %+v", entries)
+		}
+		if !entries[1].Time.After(entries[0].Time) {
+			t.Errorf("Expected timestamps to be set in order, got %+v", entries)
+		}
+	})
+
+	t.Run("Skips malformed lines", func(t *testing.T) {
+		file, _ := os.OpenFile(config.GetHistoryFile(), os.O_APPEND|os.O_WRONLY, 0644)
+		file.WriteString("not json\n")
+		file.Close()
+
+		entries, err := manager.History(0)
+		if err != nil || len(entries) != 3 {
+			t.Errorf("Expected 3 entries, got %d (%v)", len(entries), err)
+		}
+	})
+
+	t.Run("Compacts a large file", func(t *testing.T) {
+		dir := strings.Repeat("d", 1000)
+		for i := 0; i < maxHistorySize/1000+10; i++ {
+			if err := manager.RecordHistory(HistoryEntry{Event: HistoryAutoSwitch, Version: "1.22.1", Dir: dir}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		manager.RecordHistory(HistoryEntry{Event: HistoryUse, Version: "1.23.0"})
+
+		if info, _ := os.Stat(config.GetHistoryFile()); info.Size() > maxHistorySize {
+			t.Errorf("Expected the file to stay below %d bytes, got %d", maxHistorySize, info.Size())
+		}
+		entries, _ := manager.History(0)
+		if len(entries) == 0 || entries[len(entries)-1].Version != "1.23.0" {
+			t.Errorf("Expected the newest entry to be kept, got %d entries", len(entries))
+		}
+		if entries[0].Version == "1.21.0" {
+			t.Error("Expected the oldest entries to be dropped")
+		}
+	})
+}
+
+func TestManager_PreviousVersion(t *testing.T) {
+	config := createTestConfig(t)
+	config.DefaultVersion = "1.21.0"
+	config.AutoSwitch.Enabled = true
+	config.AutoSwitch.ProjectFile = ".govman-version"
+	manager := createTestManager(t, config)
+
+	for _, version := range []string{"1.21.0", "1.22.1", "1.23.0"} {
+		os.MkdirAll(filepath.Join(config.GetVersionDir(version), "bin"), 0755)
+	}
+
+	env := session{"PATH": "/usr/bin"}
+	use := func(t *testing.T, version string) {
+		t.Helper()
+		changes, err := manager.Env(version, env.getenv)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		env.apply(changes)
+	}
+
+	if _, err := manager.PreviousVersion(env.getenv); !errors.Is(err, ErrNoPreviousVersion) {
+		t.Errorf("Expected ErrNoPreviousVersion, got %v", err)
+	}
+
+	t.Run("First switch remembers the default", func(t *testing.T) {
+		use(t, "1.22.1")
+		if previous, _ := manager.PreviousVersion(env.getenv); previous != "1.21.0" {
+			t.Errorf("Expected 1.21.0, got %s", previous)
+		}
+	})
+
+	t.Run("Switching back toggles", func(t *testing.T) {
+		use(t, "1.23.0")
+		previous, _ := manager.PreviousVersion(env.getenv)
+		use(t, previous)
+		if env["GOVMAN_VERSION"] != "1.22.1" || env[PreviousVersionVar] != "1.23.0" {
+			t.Errorf("Expected 1.22.1 active and 1.23.0 previous, got %+v", env)
+		}
+	})
+
+	t.Run("Same version keeps previous", func(t *testing.T) {
+		use(t, "1.22.1")
+		if env[PreviousVersionVar] != "1.23.0" {
+			t.Errorf("Expected previous to stay 1.23.0, got %s", env[PreviousVersionVar])
+		}
+	})
+
+	t.Run("Auto-switch is recorded", func(t *testing.T) {
+		project := t.TempDir()
+		os.WriteFile(filepath.Join(project, ".govman-version"), []byte("1.23.0"), 0644)
+
+		changes, err := manager.HookEnv(project, env.getenv)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		env.apply(changes)
+		if env[PreviousVersionVar] != "1.22.1" {
+			t.Errorf("Expected previous to be 1.22.1, got %s", env[PreviousVersionVar])
+		}
+
+		changes, _ = manager.HookEnv(t.TempDir(), env.getenv)
+		env.apply(changes)
+		if env[PreviousVersionVar] != "1.23.0" {
+			t.Errorf("Expected leaving the project to remember 1.23.0, got %s", env[PreviousVersionVar])
+		}
+
+		entries, _ := manager.History(0)
+		if len(entries) != 2 {
+			t.Fatalf("Expected two auto-switch entries, got %+v", entries)
+		}
+		if entries[0].Event != HistoryAutoSwitch || entries[0].Version != "1.23.0" || entries[0].Previous != "1.22.1" || entries[0].Dir != project {
+			t.Errorf("Unexpected entry %+v", entries[0])
+		}
+		if entries[1].Version != "1.21.0" || entries[1].Previous != "1.23.0" {
+			t.Errorf("Unexpected entry %+v", entries[1])
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_logger "github.com/sijunda/govman/internal/logger"
+	_shell "github.com/sijunda/govman/internal/shell"
+)
+
+// HookStateVar is the session variable in which hook-env caches its last resolution.
+const HookStateVar = "__GOVMAN_HOOK"
+
+// ShellVersionVar pins a session started by 'govman shell' to a version; hook-env keeps that
+// version active instead of auto-switching.
+const ShellVersionVar = "GOVMAN_SHELL"
+
+// hookState is the per-directory resolution cached in HookStateVar between prompts.
+type hookState struct {
+	Dir     string `json:"dir"`
+	File    string `json:"file,omitempty"`
+	ModTime int64  `json:"mtime,omitempty"`
+	Version string `json:"version,omitempty"`
+	BinDir  string `json:"bin,omitempty"`
+}
+
+// HookEnv computes the environment changes that activate the project version for dir.
+// getenv reads the session's environment, including HookStateVar. Returns the changes to apply,
+// which is empty when the directory and its project file are unchanged since the last call.
+func (m *Manager) HookEnv(dir string, getenv func(string) string) ([]_shell.EnvChange, error) {
+	if pinned := getenv(ShellVersionVar); pinned != "" {
+		return m.pinnedEnv(pinned, getenv), nil
+	}
+
+	state := getenv(HookStateVar)
+
+	var prev hookState
+	if state != "" {
+		// A malformed state is treated as a fresh session
+		_ = json.Unmarshal([]byte(state), &prev)
+	}
+
+	if !m.config.AutoSwitch.Enabled {
+		if state == "" {
+			return nil, nil
+		}
+
+		var changes []_shell.EnvChange
+		if prev.BinDir != "" {
+			changes = m.ResetEnv(getenv)
+		}
+		return append(changes, _shell.EnvChange{Name: HookStateVar, Unset: true}), nil
+	}
+
+	if prev.Dir == dir && (prev.Version == "" || prev.BinDir != "") && m.projectFileUnchanged(prev) {
+		return nil, nil
+	}
+
+	next := hookState{Dir: dir}
+	next.File, next.ModTime = m.findProjectFile(dir)
+
+	if next.File != "" {
+		if next.File == prev.File && next.ModTime == prev.ModTime {
+			next.Version = prev.Version
+		} else if data, err := os.ReadFile(next.File); err == nil {
+			next.Version = strings.TrimSpace(string(data))
+		}
+	}
+
+	var changes []_shell.EnvChange
+	if next.Version != "" {
+		announce := next.Version != prev.Version || next.File != prev.File
+
+		if m.IsInstalled(next.Version) {
+			next.BinDir = filepath.Join(m.config.GetVersionDir(next.Version), "bin")
+			if announce || prev.BinDir == "" {
+				_logger.Info("Auto-switching to Go %s (required by %s)", next.Version, filepath.Base(next.File))
+			}
+		} else if announce {
+			_logger.Warning("Go %s required by %s is not installed. Install it with 'govman install %s'",
+				next.Version, next.File, next.Version)
+		}
+	}
+
+	// Only touch the environment when the project version changes, so a manual
+	// 'govman use' inside a project lasts until the project is left
+	switch {
+	case next.BinDir == prev.BinDir:
+	case next.BinDir != "":
+		envChanges, err := m.activationEnv(next.Version, ActivationLocal, getenv)
+		if err != nil {
+			return nil, err
+		}
+		changes = envChanges
+		m.recordHistory(HistoryEntry{
+			Event:    HistoryAutoSwitch,
+			Version:  next.Version,
+			Previous: m.sessionVersion(getenv),
+			Dir:      filepath.Dir(next.File),
+		})
+	default:
+		changes = m.ResetEnv(getenv)
+		if m.config.DefaultVersion != "" {
+			m.recordHistory(HistoryEntry{
+				Event:    HistoryAutoSwitch,
+				Version:  m.config.DefaultVersion,
+				Previous: m.sessionVersion(getenv),
+				Dir:      prev.Dir,
+			})
+		}
+	}
+
+	encoded, err := json.Marshal(next)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode hook state: %w", err)
+	}
+
+	return append(changes, _shell.EnvChange{Name: HookStateVar, Value: string(encoded)}), nil
+}
+
+// findProjectFile locates the project version file governing dir, searching parent directories.
+// Returns the file path and modification time, or an empty path if none applies.
+func (m *Manager) findProjectFile(dir string) (string, int64) {
+	name := m.config.AutoSwitch.ProjectFile
+	if name == "" {
+		return "", 0
+	}
+
+	if filepath.IsAbs(name) {
+		if info, err := os.Stat(name); err == nil && !info.IsDir() {
+			return name, info.ModTime().UnixNano()
+		}
+		return "", 0
+	}
+
+	for {
+		candidate := filepath.Join(dir, name)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, info.ModTime().UnixNano()
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", 0
+		}
+		dir = parent
+	}
+}
+
+// projectFileUnchanged reports whether the cached resolution for a directory is still valid,
+// i.e. its project file was not modified or removed and none was created in the directory itself.
+func (m *Manager) projectFileUnchanged(prev hookState) bool {
+	name := m.config.AutoSwitch.ProjectFile
+	if name == "" {
+		return prev.File == ""
+	}
+
+	local := name
+	if !filepath.IsAbs(name) {
+		local = filepath.Join(prev.Dir, name)
+	}
+
+	if prev.File == "" {
+		_, err := os.Stat(local)
+		return os.IsNotExist(err)
+	}
+
+	info, err := os.Stat(prev.File)
+	if err != nil || info.ModTime().UnixNano() != prev.ModTime {
+		return false
+	}
+
+	if prev.File != local {
+		_, err := os.Stat(local)
+		return os.IsNotExist(err)
+	}
+
+	return true
+}
+
+// pinnedEnv keeps the version of a 'govman shell' session first in PATH, undoing shell startup files
+// that put the default version ahead of it. Returns no changes once the version is in place, or
+// after the user switched to another version with 'govman use'.
+func (m *Manager) pinnedEnv(version string, getenv func(string) string) []_shell.EnvChange {
+	if getenv("GOVMAN_VERSION") != version || !m.IsInstalled(version) {
+		return nil
+	}
+
+	binDir := filepath.Join(m.config.GetVersionDir(version), "bin")
+	if entries := filepath.SplitList(getenv("PATH")); len(entries) > 0 && entries[0] == binDir {
+		return nil
+	}
+
+	changes, err := m.Env(version, getenv)
+	if err != nil {
+		return nil
+	}
+	return changes
+}
@@ -0,0 +1,247 @@
+package manager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_shell "github.com/sijunda/govman/internal/shell"
+)
+
+// session simulates a shell session's environment
+type session map[string]string
+
+// getenv reads a variable from the session
+func (s session) getenv(name string) string {
+	return s[name]
+}
+
+// apply evaluates environment changes in the session
+func (s session) apply(changes []_shell.EnvChange) {
+	for _, change := range changes {
+		if change.Unset {
+			delete(s, change.Name)
+			continue
+		}
+		s[change.Name] = change.Value
+	}
+}
+
+func TestManager_HookEnv(t *testing.T) {
+	config := createTestConfig(t)
+	config.AutoSwitch.Enabled = true
+	config.AutoSwitch.ProjectFile = ".govman-version"
+	manager := createTestManager(t, config)
+
+	for _, version := range []string{"1.21.0", "1.22.1"} {
+		os.MkdirAll(filepath.Join(config.GetVersionDir(version), "bin"), 0755)
+	}
+	bin := func(version string) string { return filepath.Join(config.GetVersionDir(version), "bin") }
+
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	nested := filepath.Join(project, "cmd", "tool")
+	other := filepath.Join(root, "other")
+	os.MkdirAll(nested, 0755)
+	os.MkdirAll(other, 0755)
+	os.WriteFile(filepath.Join(project, ".govman-version"), []byte("1.22.1\n"), 0644)
+
+	sep := string(os.PathListSeparator)
+	env := session{"PATH": "/usr/bin" + sep + "/bin"}
+
+	step := func(t *testing.T, dir string) []_shell.EnvChange {
+		t.Helper()
+		changes, err := manager.HookEnv(dir, env.getenv)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		env.apply(changes)
+		return changes
+	}
+
+	t.Run("Activates project version", func(t *testing.T) {
+		step(t, project)
+		if !strings.HasPrefix(env["PATH"], bin("1.22.1")+sep) {
+			t.Errorf("Expected Go 1.22.1 first in PATH, got %s", env["PATH"])
+		}
+		if env["GOVMAN_VERSION"] != "1.22.1" || env["GOROOT"] != config.GetVersionDir("1.22.1") {
+			t.Errorf("Expected Go 1.22.1 to be active, got %+v", env)
+		}
+		if env[ActivationVar] != ActivationLocal {
+			t.Errorf("Expected %s activation, got %s", ActivationLocal, env[ActivationVar])
+		}
+	})
+
+	t.Run("Unchanged directory prints nothing", func(t *testing.T) {
+		if changes := step(t, project); len(changes) != 0 {
+			t.Errorf("Expected no changes, got %+v", changes)
+		}
+	})
+
+	t.Run("Subdirectory keeps PATH", func(t *testing.T) {
+		for _, change := range step(t, nested) {
+			if change.Name == "PATH" {
+				t.Errorf("Expected PATH to be untouched, got %s", change.Value)
+			}
+		}
+	})
+
+	t.Run("Edited project file switches version", func(t *testing.T) {
+		file := filepath.Join(project, ".govman-version")
+		os.WriteFile(file, []byte("1.21.0"), 0644)
+		future := time.Now().Add(time.Minute)
+		os.Chtimes(file, future, future)
+
+		step(t, nested)
+		if !strings.HasPrefix(env["PATH"], bin("1.21.0")+sep) || strings.Contains(env["PATH"], bin("1.22.1")) {
+			t.Errorf("Expected Go 1.21.0 to replace 1.22.1 in PATH, got %s", env["PATH"])
+		}
+	})
+
+	t.Run("Manual switch lasts within the project", func(t *testing.T) {
+		changes, _ := manager.Env("1.22.1", env.getenv)
+		env.apply(changes)
+
+		if changes := step(t, project); len(changes) != 1 || changes[0].Name != HookStateVar {
+			t.Errorf("Expected only the state to change, got %+v", changes)
+		}
+		if env["GOVMAN_VERSION"] != "1.22.1" {
+			t.Errorf("Expected manual version to stay active, got %s", env["GOVMAN_VERSION"])
+		}
+	})
+
+	t.Run("Leaving the project restores PATH", func(t *testing.T) {
+		step(t, other)
+		if env["PATH"] != "/usr/bin"+sep+"/bin" || env["GOROOT"] != "" || env["GOVMAN_VERSION"] != "" {
+			t.Errorf("Expected original environment, got %+v", env)
+		}
+	})
+
+	t.Run("New project file in the current directory", func(t *testing.T) {
+		os.WriteFile(filepath.Join(other, ".govman-version"), []byte("1.22.1"), 0644)
+		step(t, other)
+		if !strings.HasPrefix(env["PATH"], bin("1.22.1")+sep) {
+			t.Errorf("Expected Go 1.22.1 first in PATH, got %s", env["PATH"])
+		}
+	})
+
+	t.Run("Version that is not installed", func(t *testing.T) {
+		os.WriteFile(filepath.Join(other, ".govman-version"), []byte("1.9.0"), 0644)
+		os.Chtimes(filepath.Join(other, ".govman-version"), time.Now().Add(2*time.Minute), time.Now().Add(2*time.Minute))
+		step(t, other)
+		if strings.Contains(env["PATH"], config.InstallDir) {
+			t.Errorf("Expected no version in PATH, got %s", env["PATH"])
+		}
+
+		var cached hookState
+		json.Unmarshal([]byte(env[HookStateVar]), &cached)
+		if cached.Version != "1.9.0" || cached.BinDir != "" {
+			t.Errorf("Unexpected cached state %+v", cached)
+		}
+
+		// Installing it activates it on the next prompt
+		os.MkdirAll(bin("1.9.0"), 0755)
+		step(t, other)
+		if !strings.HasPrefix(env["PATH"], bin("1.9.0")+sep) {
+			t.Errorf("Expected Go 1.9.0 first in PATH, got %s", env["PATH"])
+		}
+	})
+
+	t.Run("Disabling auto-switch reverts", func(t *testing.T) {
+		config.AutoSwitch.Enabled = false
+		defer func() { config.AutoSwitch.Enabled = true }()
+
+		step(t, other)
+		if env["PATH"] != "/usr/bin"+sep+"/bin" || env[HookStateVar] != "" || env["GOVMAN_VERSION"] != "" {
+			t.Errorf("Expected original PATH and cleared state, got %+v", env)
+		}
+		if changes := step(t, other); len(changes) != 0 {
+			t.Errorf("Expected no changes, got %+v", changes)
+		}
+	})
+}
+
+func TestManager_findProjectFile(t *testing.T) {
+	config := createTestConfig(t)
+	manager := createTestManager(t, config)
+
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	os.MkdirAll(nested, 0755)
+	os.WriteFile(filepath.Join(root, "go-version"), []byte("1.22.1"), 0644)
+
+	testCases := []struct {
+		name        string
+		projectFile string
+		dir         string
+		expected    string
+	}{
+		{"Found in parent", "go-version", nested, filepath.Join(root, "go-version")},
+		{"Found in directory", "go-version", root, filepath.Join(root, "go-version")},
+		{"Missing", ".govman-version", nested, ""},
+		{"Absolute path", filepath.Join(root, "go-version"), t.TempDir(), filepath.Join(root, "go-version")},
+		{"Empty name", "", nested, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config.AutoSwitch.ProjectFile = tc.projectFile
+			if file, _ := manager.findProjectFile(tc.dir); file != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, file)
+			}
+		})
+	}
+}
+
+func TestManager_HookEnvPinned(t *testing.T) {
+	config := createTestConfig(t)
+	config.AutoSwitch.Enabled = true
+	config.AutoSwitch.ProjectFile = ".govman-version"
+	manager := createTestManager(t, config)
+
+	for _, version := range []string{"1.21.0", "1.22.1"} {
+		os.MkdirAll(filepath.Join(config.GetVersionDir(version), "bin"), 0755)
+	}
+	bin := func(version string) string { return filepath.Join(config.GetVersionDir(version), "bin") }
+
+	project := t.TempDir()
+	os.WriteFile(filepath.Join(project, ".govman-version"), []byte("1.22.1"), 0644)
+
+	sep := string(os.PathListSeparator)
+	env := session{"PATH": "/usr/bin"}
+	changes, _ := manager.Env("1.21.0", env.getenv)
+	env.apply(changes)
+	env[ShellVersionVar] = "1.21.0"
+
+	t.Run("Project file does not switch a govman shell", func(t *testing.T) {
+		changes, err := manager.HookEnv(project, env.getenv)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(changes) != 0 {
+			t.Errorf("Expected no changes, got %+v", changes)
+		}
+	})
+
+	t.Run("Startup files that reorder PATH are undone", func(t *testing.T) {
+		env["PATH"] = "/govman/bin" + sep + env["PATH"]
+		changes, _ := manager.HookEnv(project, env.getenv)
+		env.apply(changes)
+		if !strings.HasPrefix(env["PATH"], bin("1.21.0")+sep) {
+			t.Errorf("Expected Go 1.21.0 first in PATH, got %s", env["PATH"])
+		}
+	})
+
+	t.Run("Manual switch inside the shell is kept", func(t *testing.T) {
+		changes, _ := manager.Env("1.22.1", env.getenv)
+		env.apply(changes)
+		env["PATH"] = "/govman/bin" + sep + env["PATH"]
+
+		if changes, _ := manager.HookEnv(project, env.getenv); len(changes) != 0 {
+			t.Errorf("Expected no changes, got %+v", changes)
+		}
+	})
+}
@@ -52,10 +52,13 @@ func (m *Manager) Install(version string) error {
 	_logger.Info("Installing Go %s...", resolvedVersion)
 
 	timer = _logger.StartTimer("download URL retrieval")
-	downloadURL, err := _golang.GetDownloadURLWithConfig(resolvedVersion,
-		m.config.GoReleases.APIURL,
-		m.config.GoReleases.CacheExpiry,
-		m.config.GoReleases.DownloadURL)
+	source, err := _golang.SourceFromConfig(m.config.GoReleases, m.config.CacheDir)
+	if err != nil {
+		_logger.StopTimer(timer)
+		return fmt.Errorf("invalid release source: %w", err)
+	}
+
+	downloadURL, err := _golang.GetDownloadURLFromSource(source, resolvedVersion, m.config.GoReleases.CacheExpiry)
 	if err != nil {
 		_logger.StopTimer(timer)
 		return fmt.Errorf("failed to get download URL: %w", err)
@@ -74,6 +77,32 @@ func (m *Manager) Install(version string) error {
 	return nil
 }
 
+// Download fetches and verifies the archive for a Go version on goos/goarch into the cache without installing it.
+// version may be an exact string or "latest". Returns the resolved version, the cached archive path, or an error.
+func (m *Manager) Download(version, goos, goarch string) (string, string, error) {
+	resolvedVersion, err := m.resolveVersion(version)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve version %s: %w", version, err)
+	}
+
+	source, err := _golang.SourceFromConfig(m.config.GoReleases, m.config.CacheDir)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid release source: %w", err)
+	}
+
+	fileInfo, err := _golang.GetPlatformFileInfoFromSource(source, resolvedVersion, goos, goarch, m.config.GoReleases.CacheExpiry)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	archivePath, err := m.downloader.Prefetch(source.DownloadURL(*fileInfo), fileInfo)
+	if err != nil {
+		return "", "", err
+	}
+
+	return resolvedVersion, archivePath, nil
+}
+
 // Uninstall removes an installed Go version.
 // Returns an error if the version is not installed, is active, or removal fails.
 func (m *Manager) Uninstall(version string) error {
@@ -83,8 +112,7 @@ func (m *Manager) Uninstall(version string) error {
 	}
 
 	_logger.InternalProgress("Checking if version is currently active")
-	current, err := m.Current()
-	if err == nil && current == version {
+	if m.IsActive(version) {
 		return fmt.Errorf("cannot uninstall currently active version %s", version)
 	}
 
@@ -102,7 +130,9 @@ func (m *Manager) Uninstall(version string) error {
 }
 
 // Use activates a Go version for the current session, as default, or for the local project.
-// setDefault sets it globally; setLocal writes a project version file. Returns an error if activation fails.
+// setDefault sets it globally; setLocal writes a project version file. The session environment is changed by
+// the shell wrapper, which evaluates 'govman env' afterwards, so Use prints no shell commands.
+// Returns an error if activation fails.
 func (m *Manager) Use(version string, setDefault, setLocal bool) error {
 	if version == "default" {
 		defaultVersion, err := m.CurrentGlobal()
@@ -118,6 +148,8 @@ func (m *Manager) Use(version string, setDefault, setLocal bool) error {
 		}
 	}
 
+	entry := HistoryEntry{Event: HistoryUse, Version: version, Previous: m.sessionVersion(os.Getenv)}
+
 	// Apply the version based on scope
 	switch {
 	case setLocal:
@@ -126,9 +158,12 @@ func (m *Manager) Use(version string, setDefault, setLocal bool) error {
 			return fmt.Errorf("failed to set local version: %w", err)
 		}
 		_logger.Success("Set Go %s as local version for this project", version)
+		entry.Event = HistoryLocal
+		entry.Dir, _ = os.Getwd()
 
 	case setDefault:
 		_logger.InternalProgress("Setting as system default version")
+		entry.Event = HistoryDefault
 
 		// Update config
 		m.config.DefaultVersion = version
@@ -149,23 +184,41 @@ func (m *Manager) Use(version string, setDefault, setLocal bool) error {
 		// Session-only, no additional action needed
 	}
 
-	// Update PATH
-	versionBinPath := filepath.Join(m.config.GetVersionDir(version), "bin")
-	return m.shell.ExecutePathCommand(versionBinPath)
+	m.recordHistory(entry)
+
+	return nil
 }
 
 // Current returns the currently active Go version, checking session, local project, or global symlink.
 // Returns the version string or an error if none is active or validation fails.
 func (m *Manager) Current() (string, error) {
-	sessionVersion, err := m.getCurrentSessionVersion()
-	if err == nil && sessionVersion != "" {
-		if !m.IsInstalled(sessionVersion) {
-			_logger.Warning("Session version %s is active but not managed by GOVMAN", sessionVersion)
+	session, err := m.sessionGo()
+	if err == nil {
+		if !session.Managed {
+			_logger.Warning("The go in PATH (%s) is Go %s, which is not managed by govman", session.Path, session.Version)
 		}
 
-		return sessionVersion, nil
+		return session.Version, nil
+	}
+
+	return m.fallbackVersion()
+}
+
+// IsActive reports whether version is the govman version in use: the one providing go in PATH or,
+// when go is not in PATH, the project or global version. A go that govman does not manage never
+// makes a version active.
+func (m *Manager) IsActive(version string) bool {
+	if session, err := m.sessionGo(); err == nil {
+		return session.Managed && session.Version == version
 	}
 
+	current, err := m.fallbackVersion()
+	return err == nil && current == version
+}
+
+// fallbackVersion returns the version used when the session has no go in PATH: the project
+// version, else the global default. Returns an error if neither is available.
+func (m *Manager) fallbackVersion() (string, error) {
 	if localVersion := m.getLocalVersion(); localVersion != "" {
 		if !m.IsInstalled(localVersion) {
 			return "", fmt.Errorf("local version %s specified in %s is not installed - run 'govman install %s' to install it",
@@ -298,12 +351,15 @@ func (m *Manager) ListInstalled() ([]string, error) {
 	return versions, nil
 }
 
-// ListRemote fetches available remote Go versions.
+// ListRemote fetches available remote Go versions from the configured release source.
 // includeUnstable controls inclusion of beta/rc versions. Returns the list or an error.
 func (m *Manager) ListRemote(includeUnstable bool) ([]string, error) {
-	return _golang.GetAvailableVersionsWithConfig(includeUnstable,
-		m.config.GoReleases.APIURL,
-		m.config.GoReleases.CacheExpiry)
+	source, err := _golang.SourceFromConfig(m.config.GoReleases, m.config.CacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release source: %w", err)
+	}
+
+	return _golang.GetAvailableVersionsFromSource(source, includeUnstable, m.config.GoReleases.CacheExpiry)
 }
 
 // IsInstalled reports whether a given version is installed by checking its directory.
@@ -427,6 +483,22 @@ func (m *Manager) getLocalVersion() string {
 	return strings.TrimSpace(string(data))
 }
 
+// ProjectVersion returns the version pinned by the project file governing dir, searching parent directories.
+// Returns the version and the file it was read from, or empty strings if no project file applies.
+func (m *Manager) ProjectVersion(dir string) (string, string) {
+	file, _ := m.findProjectFile(dir)
+	if file == "" {
+		return "", ""
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", ""
+	}
+
+	return strings.TrimSpace(string(data)), file
+}
+
 // DefaultVersion returns the configured default version string.
 func (m *Manager) DefaultVersion() string {
 	return m.config.DefaultVersion
@@ -439,33 +511,120 @@ func (m *Manager) GetDefaultVersionFromSymlink() (string, error) {
 }
 
 // CurrentActivationMethod returns the activation method for the currently active Go version.
-// Returns "session-only", "project-local", or "system-default" based on how the current version is activated.
+// Returns "session-only", "project-local", "system-default", or "not managed by govman" when the go in
+// PATH belongs to another installation. The method exported in GOVMAN_ACTIVATION is used when present.
 func (m *Manager) CurrentActivationMethod() string {
-	sessionVersion, err := m.getCurrentSessionVersion()
-	if err == nil && sessionVersion != "" {
-		if localVersion := m.getLocalVersion(); localVersion != "" && localVersion == sessionVersion {
-			return "project-local"
+	session, err := m.sessionGo()
+	if err == nil {
+		switch {
+		case !session.Managed:
+			return ActivationUnmanaged
+		case session.Activation != "":
+			return session.Activation
+		}
+
+		if localVersion := m.getLocalVersion(); localVersion != "" && localVersion == session.Version {
+			return ActivationLocal
 		}
 
 		globalVersion, err := m.CurrentGlobal()
-		if err == nil && globalVersion == sessionVersion {
-			return "system-default"
+		if err == nil && globalVersion == session.Version {
+			return ActivationDefault
 		}
 
-		return "session-only"
+		return ActivationSession
 	}
 
 	if localVersion := m.getLocalVersion(); localVersion != "" {
-		return "project-local"
+		return ActivationLocal
+	}
+
+	return ActivationDefault
+}
+
+// sessionGoInfo describes the go command found first in the session's PATH.
+type sessionGoInfo struct {
+	Path       string // Location of the go command
+	Version    string // Go version it runs
+	Managed    bool   // Whether it belongs to a govman installation
+	Activation string // GOVMAN_ACTIVATION, when GOVMAN_VERSION describes this go
+}
+
+// sessionGo identifies the go command in PATH without running it when govman manages it:
+// GOVMAN_VERSION is used while its bin directory provides go, and otherwise the command's location
+// identifies the version. Only a go outside govman is run with 'go version'.
+// Returns an error if go is not in PATH or its version cannot be determined.
+func (m *Manager) sessionGo() (sessionGoInfo, error) {
+	goPath, err := exec.LookPath("go")
+	if err != nil {
+		return sessionGoInfo{}, fmt.Errorf("go is not in PATH: %w", err)
+	}
+
+	if version := os.Getenv("GOVMAN_VERSION"); version != "" {
+		binDir := filepath.Join(m.config.GetVersionDir(version), "bin")
+		if sameDir(filepath.Dir(goPath), binDir) && m.IsInstalled(version) {
+			return sessionGoInfo{Path: goPath, Version: version, Managed: true, Activation: os.Getenv(ActivationVar)}, nil
+		}
+		_logger.Verbose("GOVMAN_VERSION is %s, but go resolves to %s", version, goPath)
+	}
+
+	if version := m.managedGoVersion(goPath); version != "" {
+		return sessionGoInfo{Path: goPath, Version: version, Managed: true}, nil
+	}
+
+	version, err := goVersion(goPath)
+	if err != nil {
+		return sessionGoInfo{}, err
+	}
+
+	return sessionGoInfo{Path: goPath, Version: version}, nil
+}
+
+// managedGoVersion returns the installed version that goPath belongs to, following the global
+// symlink in the govman bin directory. Returns "" if govman does not manage goPath.
+func (m *Manager) managedGoVersion(goPath string) string {
+	if sameDir(filepath.Dir(goPath), m.config.GetBinPath()) {
+		if version, err := m.CurrentGlobal(); err == nil {
+			return version
+		}
+	}
+
+	resolved, err := filepath.EvalSymlinks(goPath)
+	if err != nil {
+		return ""
 	}
 
-	return "system-default"
+	binDir := filepath.Dir(resolved)
+	versionDir := filepath.Dir(binDir)
+	if filepath.Base(binDir) != "bin" {
+		return ""
+	}
+	// Compare against the resolved install directory, which may itself be behind a symlink
+	if installDir, err := filepath.EvalSymlinks(m.config.InstallDir); err != nil || !sameDir(filepath.Dir(versionDir), installDir) {
+		return ""
+	}
+
+	version := strings.TrimPrefix(filepath.Base(versionDir), "go")
+	if !m.IsInstalled(version) {
+		return ""
+	}
+
+	return version
+}
+
+// sameDir reports whether a and b name the same directory path, ignoring case on Windows.
+func sameDir(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
 }
 
-// getCurrentSessionVersion executes "go version" and parses the active version.
+// goVersion executes "<goPath> version" and parses the version it reports.
 // Returns the version string or an error if command execution or parsing fails.
-func (m *Manager) getCurrentSessionVersion() (string, error) {
-	cmd := exec.Command("go", "version")
+func goVersion(goPath string) (string, error) {
+	cmd := exec.Command(goPath, "version")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to execute 'go version': %w", err)
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -54,6 +55,13 @@ func (m *mockShell) ExecutePathCommand(path string) error {
 func createTestConfig(t *testing.T) *_config.Config {
 	tempDir := t.TempDir()
 
+	// Keep the bin directory and history file out of the real home directory
+	t.Setenv("HOME", tempDir)
+	t.Setenv("USERPROFILE", tempDir)
+	// Ignore the govman session the tests run in
+	t.Setenv("GOVMAN_VERSION", "")
+	t.Setenv(ActivationVar, "")
+
 	// Create config file first
 	configFile := filepath.Join(tempDir, "config.yaml")
 	config := &_config.Config{
@@ -482,7 +490,7 @@ func TestManager_Use(t *testing.T) {
 			setDefault: false,
 			setLocal:   false,
 			expected:   "",
-			hasError:   false, // ExecutePathCommand returns nil
+			hasError:   false,
 		},
 		{
 			name:       "Set as default",
@@ -569,6 +577,23 @@ func TestManager_Use(t *testing.T) {
 			}
 		})
 	}
+	t.Run("Prints no shell commands", func(t *testing.T) {
+		oldStdout, oldStderr := os.Stdout, os.Stderr
+		r, w, _ := os.Pipe()
+		os.Stdout, os.Stderr = w, w
+
+		err := manager.Use(version, false, false)
+		w.Close()
+		os.Stdout, os.Stderr = oldStdout, oldStderr
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		output, _ := io.ReadAll(r)
+		if strings.Contains(string(output), "export ") || strings.Contains(string(output), "eval ") {
+			t.Errorf("Expected the shell wrapper, not Use, to change the session, got output %q", output)
+		}
+	})
 }
 
 func TestManager_Install(t *testing.T) {
@@ -947,6 +972,122 @@ func TestManager_CurrentActivationMethod(t *testing.T) {
 	}
 }
 
+func TestManager_sessionGo(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Uses shell scripts as the go command")
+	}
+
+	config := createTestConfig(t)
+	manager := createTestManager(t, config)
+
+	// Each fake go appends to ran when executed
+	ran := filepath.Join(t.TempDir(), "ran")
+	writeGo := func(dir, version string) {
+		os.MkdirAll(dir, 0755)
+		script := fmt.Sprintf("#!/bin/sh\necho %s >> %s\necho 'go version go%s linux/amd64'\n", version, ran, version)
+		os.WriteFile(filepath.Join(dir, "go"), []byte(script), 0755)
+	}
+	bin := func(version string) string { return filepath.Join(config.GetVersionDir(version), "bin") }
+
+	for _, version := range []string{"1.21.0", "1.22.1"} {
+		writeGo(bin(version), version)
+	}
+	systemBin := filepath.Join(t.TempDir(), "system")
+	writeGo(systemBin, "1.22.1")
+	os.Symlink(filepath.Join(bin("1.21.0"), "go"), config.GetCurrentSymlink())
+
+	testCases := []struct {
+		name       string
+		path       string
+		version    string
+		activation string
+		expected   sessionGoInfo
+		executed   bool
+		isActive   bool
+	}{
+		{
+			name:       "Activated version",
+			path:       bin("1.22.1"),
+			version:    "1.22.1",
+			activation: ActivationLocal,
+			expected:   sessionGoInfo{Version: "1.22.1", Managed: true, Activation: ActivationLocal},
+			isActive:   true,
+		},
+		{
+			name:     "Version directory without GOVMAN_VERSION",
+			path:     bin("1.22.1"),
+			expected: sessionGoInfo{Version: "1.22.1", Managed: true},
+			isActive: true,
+		},
+		{
+			name:     "Global symlink",
+			path:     config.GetBinPath(),
+			expected: sessionGoInfo{Version: "1.21.0", Managed: true},
+		},
+		{
+			name:       "Stale GOVMAN_VERSION",
+			path:       config.GetBinPath(),
+			version:    "1.22.1",
+			activation: ActivationSession,
+			expected:   sessionGoInfo{Version: "1.21.0", Managed: true},
+		},
+		{
+			name:     "Go outside govman",
+			path:     systemBin,
+			version:  "1.22.1",
+			expected: sessionGoInfo{Version: "1.22.1"},
+			executed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Remove(ran)
+			t.Setenv("PATH", tc.path)
+			t.Setenv("GOVMAN_VERSION", tc.version)
+			t.Setenv(ActivationVar, tc.activation)
+
+			session, err := manager.sessionGo()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			session.Path = ""
+			if session != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, session)
+			}
+
+			if _, err := os.Stat(ran); (err == nil) != tc.executed {
+				t.Errorf("Expected go to be executed: %v", tc.executed)
+			}
+
+			if active := manager.IsActive("1.22.1"); active != tc.isActive {
+				t.Errorf("Expected IsActive(1.22.1) to be %v", tc.isActive)
+			}
+		})
+	}
+
+	t.Run("Activation method", func(t *testing.T) {
+		t.Setenv("PATH", bin("1.22.1"))
+		t.Setenv("GOVMAN_VERSION", "1.22.1")
+		t.Setenv(ActivationVar, ActivationLocal)
+		if method := manager.CurrentActivationMethod(); method != ActivationLocal {
+			t.Errorf("Expected %s, got %s", ActivationLocal, method)
+		}
+
+		t.Setenv("PATH", systemBin)
+		if method := manager.CurrentActivationMethod(); method != ActivationUnmanaged {
+			t.Errorf("Expected %s, got %s", ActivationUnmanaged, method)
+		}
+	})
+
+	t.Run("No go in PATH", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		if _, err := manager.sessionGo(); err == nil {
+			t.Error("Expected error when go is not in PATH")
+		}
+	})
+}
+
 func TestManager_Info(t *testing.T) {
 	config := createTestConfig(t)
 	manager := createTestManager(t, config)
@@ -0,0 +1,37 @@
+package manager
+
+import (
+	"fmt"
+
+	_downloader "github.com/sijunda/govman/internal/downloader"
+	_golang "github.com/sijunda/govman/internal/golang"
+	_logger "github.com/sijunda/govman/internal/logger"
+	_mirror "github.com/sijunda/govman/internal/mirror"
+)
+
+// SyncMirror keeps dir in step with the releases of the configured source matching constraint for platforms.
+// New or changed archives are downloaded and verified, archives that no longer match are removed when opts.Prune is set,
+// and a static index.json (signed with opts.SigningKey if set) is written for use as go_releases.api_url.
+// Returns a summary of the changes or an error.
+func (m *Manager) SyncMirror(dir, constraint string, platforms []Platform, includeUnstable bool, opts _mirror.SyncOptions) (*_mirror.SyncResult, error) {
+	releases, err := m.SelectReleases(constraint, platforms, includeUnstable)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := _golang.SourceFromConfig(m.config.GoReleases, m.config.CacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release source: %w", err)
+	}
+
+	// Archives go straight into the mirror directory rather than the download cache
+	mirrorConfig := *m.config
+	mirrorConfig.CacheDir = dir
+	downloader := _downloader.New(&mirrorConfig)
+
+	return _mirror.Sync(dir, releases, func(file _golang.File) error {
+		_logger.Info("Fetching %s...", file.Filename)
+		_, err := downloader.Prefetch(source.DownloadURL(file), &file)
+		return err
+	}, opts)
+}
@@ -0,0 +1,375 @@
+package mirror
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+)
+
+// Server serves a directory of Go archives the way go.dev/dl does: archives under /dl/<filename>
+// and a generated release index under /dl/?mode=json (add include=all for every release).
+type Server struct {
+	dir       string
+	accessLog io.Writer
+
+	mu   sync.Mutex
+	sums map[string]cachedSum
+}
+
+// cachedSum remembers an archive checksum so unchanged files are hashed only once.
+type cachedSum struct {
+	size    int64
+	modTime time.Time
+	sha256  string
+}
+
+// NewServer creates a Server for the archives in dir.
+// Requests are logged to accessLog in Common Log Format; a nil accessLog disables logging.
+func NewServer(dir string, accessLog io.Writer) *Server {
+	return &Server{
+		dir:       dir,
+		accessLog: accessLog,
+		sums:      make(map[string]cachedSum),
+	}
+}
+
+// ServeHTTP routes index and archive requests and writes an access log entry for each request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	s.route(rec, r)
+
+	if s.accessLog != nil {
+		fmt.Fprintf(s.accessLog, "%s - - [%s] %q %d %d %q %s\n",
+			remoteHost(r.RemoteAddr),
+			start.Format("02/Jan/2006:15:04:05 -0700"),
+			r.Method+" "+r.URL.RequestURI()+" "+r.Proto,
+			rec.status,
+			rec.bytes,
+			r.UserAgent(),
+			time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// route dispatches a request to the index or archive handler.
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Accept both the go.dev layout (/dl/<file>) and a flat layout (/<file>)
+	name := r.URL.Path
+	if name == "/dl" || strings.HasPrefix(name, "/dl/") {
+		name = strings.TrimPrefix(name, "/dl")
+	}
+	name = strings.TrimPrefix(name, "/")
+
+	switch name {
+	case "":
+		s.serveIndex(w, r)
+	case _golang.SignatureSuffix:
+		s.serveIndexSignature(w, r)
+	case _golang.DefaultIndexName, _golang.DefaultIndexName + _golang.SignatureSuffix:
+		// Static index written by 'mirror sync'
+		s.serveFile(w, r, name)
+	default:
+		s.serveArchive(w, r, name)
+	}
+}
+
+// serveIndex writes the release index as JSON for ?mode=json, or a plain list of archives otherwise.
+func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("mode") != "json" {
+		releases, err := s.Index()
+		if err != nil {
+			http.Error(w, "failed to build release index", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, release := range releases {
+			for _, file := range release.Files {
+				fmt.Fprintf(w, "%s\t%d\t%s\n", file.Filename, file.Size, file.Sha256)
+			}
+		}
+		return
+	}
+
+	data, _, err := s.indexJSON(r)
+	if err != nil {
+		http.Error(w, "failed to build release index", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(data)
+}
+
+// serveIndexSignature serves the index.json.sig written by 'mirror sync' when the request's index is that
+// index.json verbatim, and answers 404 otherwise. The server never signs anything itself.
+func (s *Server) serveIndexSignature(w http.ResponseWriter, r *http.Request) {
+	_, synced, err := s.indexJSON(r)
+	if err != nil {
+		http.Error(w, "failed to build release index", http.StatusInternalServerError)
+		return
+	}
+	if !synced {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	s.serveFile(w, r, _golang.DefaultIndexName+_golang.SignatureSuffix)
+}
+
+// indexJSON encodes the release index for the request's include parameter.
+// With include=all, the index.json written by 'mirror sync' is returned unchanged when every archive it lists
+// is present and verified, so its signature applies; synced reports whether that is the case.
+func (s *Server) indexJSON(r *http.Request) (data []byte, synced bool, err error) {
+	releases, raw, err := s.index()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if r.URL.Query().Get("include") != "all" {
+		releases = currentReleases(releases)
+	} else if raw != nil {
+		return raw, true, nil
+	}
+
+	data, err = json.MarshalIndent(releases, "", " ")
+	return data, false, err
+}
+
+// serveArchive serves a single archive or its detached signature; only files named like Go archives are exposed.
+func (s *Server) serveArchive(w http.ResponseWriter, r *http.Request, name string) {
+	if name != path.Base(name) {
+		http.NotFound(w, r)
+		return
+	}
+	if _, ok := _golang.ParseArchiveName(strings.TrimSuffix(name, _golang.ArchiveSignatureSuffix)); !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.serveFile(w, r, name)
+}
+
+// serveFile serves a regular file from the mirror directory with Range and conditional request support.
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	file, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil || !stat.Mode().IsRegular() {
+		http.NotFound(w, r)
+		return
+	}
+
+	if strings.HasSuffix(name, ".json") {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	http.ServeContent(w, r, name, stat.ModTime(), file)
+}
+
+// Index returns the archives of the directory grouped into releases, newest first.
+// When the directory holds an index.json written by 'mirror sync', only the archives it lists whose size and
+// checksum match are published, so partial downloads and stray files never appear. Otherwise every file named
+// like a Go archive is listed. Checksums are computed once per file and reused while its size and modification
+// time are unchanged.
+func (s *Server) Index() ([]_golang.Release, error) {
+	releases, _, err := s.index()
+	return releases, err
+}
+
+// index builds the release index like Index. It also returns the raw index.json written by 'mirror sync'
+// when every archive listed there is present and verified, or nil otherwise.
+func (s *Server) index() ([]_golang.Release, []byte, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read mirror directory: %w", err)
+	}
+
+	synced, raw, err := s.syncedIndex()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var files []_golang.File
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		file, ok := _golang.ParseArchiveName(entry.Name())
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		seen[file.Filename] = true
+
+		expected, listed := synced[file.Filename]
+		if synced != nil && (!listed || expected.Size != info.Size()) {
+			continue
+		}
+
+		sum, ok := s.sums[file.Filename]
+		if !ok || sum.size != info.Size() || !sum.modTime.Equal(info.ModTime()) {
+			digest, err := fileSHA256(filepath.Join(s.dir, file.Filename))
+			if err != nil {
+				return nil, nil, err
+			}
+			sum = cachedSum{size: info.Size(), modTime: info.ModTime(), sha256: digest}
+			s.sums[file.Filename] = sum
+		}
+
+		if synced != nil {
+			if sum.sha256 != expected.Sha256 {
+				continue
+			}
+			file = expected
+		}
+
+		file.Size = sum.size
+		file.Sha256 = sum.sha256
+		files = append(files, file)
+	}
+
+	for name := range s.sums {
+		if !seen[name] {
+			delete(s.sums, name)
+		}
+	}
+
+	if len(files) != len(synced) {
+		raw = nil
+	}
+
+	return _golang.ReleasesFromFiles(files), raw, nil
+}
+
+// syncedIndex reads the index.json written by 'mirror sync' into a filename-to-file map and returns its raw content.
+// Returns nil when the directory has no such index, or an error if it cannot be parsed.
+func (s *Server) syncedIndex() (map[string]_golang.File, []byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, _golang.DefaultIndexName))
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", _golang.DefaultIndexName, err)
+	}
+
+	var releases []_golang.Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", _golang.DefaultIndexName, err)
+	}
+
+	files := make(map[string]_golang.File)
+	for _, release := range releases {
+		for _, file := range release.Files {
+			files[file.Filename] = file
+		}
+	}
+	return files, data, nil
+}
+
+// currentReleases mimics go.dev/dl/?mode=json without include=all: the newest patch release
+// of the two most recent stable minor versions.
+func currentReleases(releases []_golang.Release) []_golang.Release {
+	current := make([]_golang.Release, 0, 2)
+	seenMinor := make(map[string]bool)
+	for _, release := range releases {
+		if !release.Stable {
+			continue
+		}
+
+		minor := minorVersion(release.Version)
+		if seenMinor[minor] {
+			continue
+		}
+		seenMinor[minor] = true
+		current = append(current, release)
+
+		if len(current) == 2 {
+			break
+		}
+	}
+	return current
+}
+
+// minorVersion returns the "goX.Y" prefix of a release version.
+func minorVersion(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// fileSHA256 returns the hex SHA-256 digest of the file at path.
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("failed to calculate checksum: %w", err)
+	}
+
+	return fmt.Sprintf("%x", hasher.Sum(nil)), nil
+}
+
+// remoteHost strips the port from a request's remote address.
+func remoteHost(addr string) string {
+	if i := strings.LastIndex(addr, ":"); i > 0 {
+		return strings.Trim(addr[:i], "[]")
+	}
+	return addr
+}
+
+// statusRecorder captures the status code and body size written by a handler for the access log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader records the status code before passing it on.
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of body bytes written.
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
@@ -0,0 +1,304 @@
+package mirror
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_config "github.com/sijunda/govman/internal/config"
+	_golang "github.com/sijunda/govman/internal/golang"
+)
+
+// createMirrorDir writes fake archives into a temporary directory and returns it
+func createMirrorDir(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("content of "+name), 0644); err != nil {
+			t.Fatalf("Failed to write archive: %v", err)
+		}
+	}
+	return dir
+}
+
+// writeSyncedIndex writes an index.json listing names with the checksums of their content from createMirrorDir
+func writeSyncedIndex(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	var files []_golang.File
+	for _, name := range names {
+		file, _ := _golang.ParseArchiveName(name)
+		content := []byte("content of " + name)
+		file.Size = int64(len(content))
+		file.Sha256 = fmt.Sprintf("%x", sha256.Sum256(content))
+		files = append(files, file)
+	}
+
+	data, err := json.MarshalIndent(_golang.ReleasesFromFiles(files), "", " ")
+	if err != nil {
+		t.Fatalf("Failed to encode index: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, _golang.DefaultIndexName), data, 0644); err != nil {
+		t.Fatalf("Failed to write index: %v", err)
+	}
+}
+
+func TestServer_Index(t *testing.T) {
+	dir := createMirrorDir(t,
+		"go1.22.1.linux-amd64.tar.gz",
+		"go1.22.0.linux-amd64.tar.gz",
+		"go1.21.8.linux-amd64.tar.gz",
+		"go1.21.8.windows-amd64.zip",
+		"go1.20.14.linux-amd64.tar.gz",
+		"go1.23rc1.linux-amd64.tar.gz",
+		"notes.txt",
+	)
+
+	var accessLog bytes.Buffer
+	server := httptest.NewServer(NewServer(dir, &accessLog))
+	defer server.Close()
+
+	fetch := func(t *testing.T, query string) []_golang.Release {
+		resp, err := http.Get(server.URL + "/dl/" + query)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var releases []_golang.Release
+		if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+			t.Fatalf("Failed to decode index: %v", err)
+		}
+		return releases
+	}
+
+	t.Run("include=all lists every release", func(t *testing.T) {
+		releases := fetch(t, "?mode=json&include=all")
+		if len(releases) != 5 {
+			t.Fatalf("Expected 5 releases, got %d", len(releases))
+		}
+		if releases[0].Version != "go1.23rc1" || releases[0].Stable {
+			t.Errorf("Expected unstable go1.23rc1 first, got %+v", releases[0])
+		}
+
+		file := releases[2].Files[0]
+		content := []byte("content of " + file.Filename)
+		if file.Sha256 != fmt.Sprintf("%x", sha256.Sum256(content)) || file.Size != int64(len(content)) {
+			t.Errorf("Unexpected checksum or size: %+v", file)
+		}
+	})
+
+	t.Run("Default lists current stable releases", func(t *testing.T) {
+		releases := fetch(t, "?mode=json")
+		if len(releases) != 2 || releases[0].Version != "go1.22.1" || releases[1].Version != "go1.21.8" {
+			t.Errorf("Expected go1.22.1 and go1.21.8, got %+v", releases)
+		}
+	})
+
+	t.Run("Readable by the release source", func(t *testing.T) {
+		_golang.ClearReleasesCache()
+		defer _golang.ClearReleasesCache()
+
+		src := _golang.NewJSONSource(server.URL+"/dl/?mode=json&include=all", server.URL+"/dl/%s")
+		file, err := _golang.GetPlatformFileInfoFromSource(src, "1.21.8", "windows", "amd64", 0)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if src.DownloadURL(*file) != server.URL+"/dl/go1.21.8.windows-amd64.zip" {
+			t.Errorf("Unexpected download URL %s", src.DownloadURL(*file))
+		}
+	})
+
+	if !strings.Contains(accessLog.String(), `"GET /dl/?mode=json&include=all HTTP/1.1" 200`) {
+		t.Errorf("Expected access log entry, got %q", accessLog.String())
+	}
+}
+
+func TestServer_Index_Synced(t *testing.T) {
+	dir := createMirrorDir(t,
+		"go1.22.1.linux-amd64.tar.gz",
+		"go1.22.1.darwin-arm64.tar.gz",
+		"go1.22.0.linux-amd64.tar.gz",
+		"go1.21.8.linux-amd64.tar.gz",
+	)
+	writeSyncedIndex(t, dir, "go1.22.1.linux-amd64.tar.gz", "go1.22.1.darwin-arm64.tar.gz", "go1.22.0.linux-amd64.tar.gz")
+
+	// A download in progress and an archive that was replaced after the sync
+	os.WriteFile(filepath.Join(dir, "go1.22.1.darwin-arm64.tar.gz"), []byte("content"), 0644)
+	os.WriteFile(filepath.Join(dir, "go1.22.0.linux-amd64.tar.gz"), []byte("content of go1.22.0.linux-amd64.tar.GZ"), 0644)
+
+	releases, err := NewServer(dir, nil).Index()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, release := range releases {
+		for _, file := range release.Files {
+			names = append(names, file.Filename)
+		}
+	}
+	if len(names) != 1 || names[0] != "go1.22.1.linux-amd64.tar.gz" {
+		t.Errorf("Expected only the verified archive, got %v", names)
+	}
+}
+
+func TestServer_Archive(t *testing.T) {
+	dir := createMirrorDir(t, "go1.22.1.linux-amd64.tar.gz")
+	os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0644)
+
+	server := httptest.NewServer(NewServer(dir, nil))
+	defer server.Close()
+
+	t.Run("Full download", func(t *testing.T) {
+		for _, path := range []string{"/dl/go1.22.1.linux-amd64.tar.gz", "/go1.22.1.linux-amd64.tar.gz"} {
+			resp, err := http.Get(server.URL + path)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK || string(body) != "content of go1.22.1.linux-amd64.tar.gz" {
+				t.Errorf("%s: unexpected response %d %q", path, resp.StatusCode, body)
+			}
+		}
+	})
+
+	t.Run("Range request", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/dl/go1.22.1.linux-amd64.tar.gz", nil)
+		req.Header.Set("Range", "bytes=11-")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+
+		if resp.StatusCode != http.StatusPartialContent || string(body) != "go1.22.1.linux-amd64.tar.gz" {
+			t.Errorf("Unexpected response %d %q", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("Archive signature", func(t *testing.T) {
+		os.WriteFile(filepath.Join(dir, "go1.22.1.linux-amd64.tar.gz.asc"), []byte("signature"), 0644)
+
+		resp, err := http.Get(server.URL + "/dl/go1.22.1.linux-amd64.tar.gz.asc")
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || string(body) != "signature" {
+			t.Errorf("Unexpected response %d %q", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("Non-archive files are not served", func(t *testing.T) {
+		for _, path := range []string{"/dl/secret.txt", "/dl/..%2fsecret.txt", "/dl/go1.22.2.linux-amd64.tar.gz"} {
+			resp, err := http.Get(server.URL + path)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("%s: expected 404, got %d", path, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("Only GET and HEAD", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/dl/go1.22.1.linux-amd64.tar.gz", "text/plain", nil)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("Expected 405, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestServer_SignedIndex(t *testing.T) {
+	dir := createMirrorDir(t, "go1.22.1.linux-amd64.tar.gz")
+	writeSyncedIndex(t, dir, "go1.22.1.linux-amd64.tar.gz")
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	server := httptest.NewServer(NewServer(dir, nil))
+	defer server.Close()
+
+	cfg := _config.GoReleasesConfig{
+		APIURL:           server.URL + "/dl/?mode=json&include=all",
+		DownloadURL:      server.URL + "/dl/%s",
+		TrustedKeys:      []string{_golang.EncodePublicKey(pub)},
+		RequireSignature: true,
+	}
+
+	_golang.ClearReleasesCache()
+	defer _golang.ClearReleasesCache()
+
+	src, err := _golang.SourceFromConfig(cfg, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := _golang.GetReleasesFromSource(src, 0); err == nil {
+		t.Fatal("Expected unsigned index to be refused")
+	}
+
+	// Sign index.json the way 'mirror sync --sign-key' does
+	index, _ := os.ReadFile(filepath.Join(dir, _golang.DefaultIndexName))
+	sig, err := _golang.SignIndex(index, priv)
+	if err != nil {
+		t.Fatalf("Failed to sign index: %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, _golang.DefaultIndexName+_golang.SignatureSuffix), sig, 0644)
+
+	releases, err := _golang.GetReleasesFromSource(src, 0)
+	if err != nil {
+		t.Fatalf("Expected signed index to be accepted, got %v", err)
+	}
+	if len(releases) != 1 {
+		t.Errorf("Expected 1 release, got %d", len(releases))
+	}
+
+	t.Run("Static index", func(t *testing.T) {
+		for _, name := range []string{_golang.DefaultIndexName, _golang.DefaultIndexName + _golang.SignatureSuffix} {
+			resp, err := http.Get(server.URL + "/dl/" + name)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected static %s to be served, got %d", name, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("Incomplete mirror is not signed", func(t *testing.T) {
+		os.WriteFile(filepath.Join(dir, "go1.22.1.linux-amd64.tar.gz"), []byte("partial"), 0644)
+
+		resp, err := http.Get(server.URL + "/dl/.sig?mode=json&include=all")
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected 404 for the signature of a generated index, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package mirror
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+)
+
+// FetchFunc downloads file into dir and verifies it against file.Sha256.
+type FetchFunc func(file _golang.File) error
+
+// SyncOptions controls optional Sync behaviour.
+type SyncOptions struct {
+	// Prune removes archives that are no longer listed.
+	Prune bool
+	// SigningKey, when set, signs the written index into index.json.sig.
+	SigningKey ed25519.PrivateKey
+}
+
+// SyncResult summarises the changes made by Sync.
+type SyncResult struct {
+	Downloaded    []string
+	Unchanged     []string
+	Removed       []string
+	IndexPath     string
+	SignaturePath string
+}
+
+// Sync brings dir in step with releases: archives that are missing or changed upstream are fetched,
+// archives no longer listed are removed when opts.Prune is set, and a static release index is written to
+// dir/index.json in the go.dev schema, signed when opts.SigningKey is set. Archives whose size and upstream
+// checksum match the previous index are kept without re-downloading.
+// Returns a summary of the changes or the first error encountered.
+func Sync(dir string, releases []_golang.Release, fetch FetchFunc, opts SyncOptions) (*SyncResult, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create mirror directory: %w", err)
+	}
+
+	indexPath := filepath.Join(dir, _golang.DefaultIndexName)
+	previous := previousChecksums(indexPath)
+
+	result := &SyncResult{IndexPath: indexPath}
+	wanted := make(map[string]bool)
+
+	index := []_golang.Release{}
+	for _, release := range releases {
+		entry := _golang.Release{Version: release.Version, Stable: release.Stable}
+		for _, file := range release.Files {
+			wanted[file.Filename] = true
+
+			if isUnchanged(filepath.Join(dir, file.Filename), file, previous) {
+				result.Unchanged = append(result.Unchanged, file.Filename)
+			} else {
+				if sum, ok := previous[file.Filename]; ok && sum != file.Sha256 {
+					// Upstream republished the archive; drop the old copy instead of resuming into it
+					os.Remove(filepath.Join(dir, file.Filename))
+				}
+				if err := fetch(file); err != nil {
+					return result, fmt.Errorf("failed to fetch %s: %w", file.Filename, err)
+				}
+				result.Downloaded = append(result.Downloaded, file.Filename)
+			}
+
+			// Archive locations are resolved by the consumer's source (file:// directory or download_url)
+			file.URL = ""
+			entry.Files = append(entry.Files, file)
+		}
+		index = append(index, entry)
+	}
+
+	if opts.Prune {
+		removed, err := pruneArchives(dir, wanted)
+		result.Removed = removed
+		if err != nil {
+			return result, err
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", " ")
+	if err != nil {
+		return result, fmt.Errorf("failed to encode release index: %w", err)
+	}
+
+	sigPath := indexPath + _golang.SignatureSuffix
+	if opts.SigningKey != nil {
+		sig, err := _golang.SignIndex(data, opts.SigningKey)
+		if err != nil {
+			return result, err
+		}
+		// The signature is written first; clients reading in between see a mismatch and fall back to their cached index
+		if err := writeFileAtomic(sigPath, sig); err != nil {
+			return result, err
+		}
+		result.SignaturePath = sigPath
+	} else if err := os.Remove(sigPath); err != nil && !os.IsNotExist(err) {
+		// A stale signature would make clients reject the new index
+		return result, fmt.Errorf("failed to remove stale signature: %w", err)
+	}
+
+	if err := writeFileAtomic(indexPath, data); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// previousChecksums returns the filename-to-sha256 map of an existing mirror index, or an empty map.
+func previousChecksums(indexPath string) map[string]string {
+	sums := make(map[string]string)
+
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return sums
+	}
+
+	var releases []_golang.Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return sums
+	}
+
+	for _, release := range releases {
+		for _, file := range release.Files {
+			sums[file.Filename] = file.Sha256
+		}
+	}
+	return sums
+}
+
+// isUnchanged reports whether the archive at path was verified by a previous sync and still matches file.
+func isUnchanged(path string, file _golang.File, previous map[string]string) bool {
+	stat, err := os.Stat(path)
+	if err != nil || stat.Size() != file.Size {
+		return false
+	}
+	return previous[file.Filename] != "" && previous[file.Filename] == file.Sha256
+}
+
+// pruneArchives removes Go archives in dir that are not in wanted, together with their detached
+// signatures. Other files are left alone. Returns the removed file names.
+func pruneArchives(dir string, wanted map[string]bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror directory: %w", err)
+	}
+
+	var removed []string
+	for _, entry := range entries {
+		archive := strings.TrimSuffix(entry.Name(), _golang.ArchiveSignatureSuffix)
+		if _, ok := _golang.ParseArchiveName(archive); !ok || !entry.Type().IsRegular() || wanted[archive] {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", entry.Name(), err)
+		}
+		removed = append(removed, entry.Name())
+	}
+
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// writeFileAtomic writes data to path through a temporary file and rename, so readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".govman-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.Chmod(tmpFile.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to set permissions of %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("failed to save %s: %w", filepath.Base(path), err)
+	}
+
+	return nil
+}
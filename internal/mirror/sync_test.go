@@ -0,0 +1,204 @@
+package mirror
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	_golang "github.com/sijunda/govman/internal/golang"
+)
+
+// upstreamRelease builds a release whose archives have deterministic content
+func upstreamRelease(version string, platforms ...string) _golang.Release {
+	release := _golang.Release{Version: "go" + version, Stable: !strings.Contains(version, "rc")}
+	for _, platform := range platforms {
+		name := fmt.Sprintf("go%s.%s.tar.gz", version, platform)
+		content := archiveContent(name)
+		goos, goarch, _ := strings.Cut(platform, "-")
+		release.Files = append(release.Files, _golang.File{
+			Filename: name,
+			OS:       goos,
+			Arch:     goarch,
+			Version:  "go" + version,
+			Sha256:   fmt.Sprintf("%x", sha256.Sum256(content)),
+			Size:     int64(len(content)),
+			Kind:     "archive",
+			URL:      "https://upstream.example.com/" + name,
+		})
+	}
+	return release
+}
+
+func archiveContent(name string) []byte {
+	return []byte("content of " + name)
+}
+
+// fakeFetch writes the archive into dir and records the fetched names
+func fakeFetch(dir string, fetched *[]string) FetchFunc {
+	return func(file _golang.File) error {
+		*fetched = append(*fetched, file.Filename)
+		return os.WriteFile(filepath.Join(dir, file.Filename), archiveContent(file.Filename), 0644)
+	}
+}
+
+func TestSync(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "README.txt"), []byte("keep me"), 0644)
+
+	var fetched []string
+	first := []_golang.Release{
+		upstreamRelease("1.22.1", "linux-amd64", "darwin-arm64"),
+		upstreamRelease("1.21.8", "linux-amd64"),
+	}
+
+	result, err := Sync(dir, first, fakeFetch(dir, &fetched), SyncOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Downloaded) != 3 || len(fetched) != 3 {
+		t.Fatalf("Expected 3 downloads on first sync, got %v", result.Downloaded)
+	}
+
+	t.Run("Second sync downloads only new archives and prunes old ones", func(t *testing.T) {
+		fetched = nil
+		os.WriteFile(filepath.Join(dir, "go1.21.8.linux-amd64.tar.gz.asc"), []byte("sig"), 0644)
+		os.WriteFile(filepath.Join(dir, "go1.22.1.linux-amd64.tar.gz.asc"), []byte("sig"), 0644)
+		second := []_golang.Release{
+			upstreamRelease("1.22.2", "linux-amd64"),
+			upstreamRelease("1.22.1", "linux-amd64", "darwin-arm64"),
+		}
+
+		result, err := Sync(dir, second, fakeFetch(dir, &fetched), SyncOptions{Prune: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if strings.Join(fetched, ",") != "go1.22.2.linux-amd64.tar.gz" {
+			t.Errorf("Expected only the new archive to be fetched, got %v", fetched)
+		}
+		if len(result.Unchanged) != 2 {
+			t.Errorf("Expected 2 unchanged archives, got %v", result.Unchanged)
+		}
+		if strings.Join(result.Removed, ",") != "go1.21.8.linux-amd64.tar.gz,go1.21.8.linux-amd64.tar.gz.asc" {
+			t.Errorf("Expected go1.21.8 and its signature to be pruned, got %v", result.Removed)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go1.22.1.linux-amd64.tar.gz.asc")); err != nil {
+			t.Error("Expected signatures of kept archives to be kept")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "README.txt")); err != nil {
+			t.Error("Expected unrelated files to be kept")
+		}
+	})
+
+	t.Run("Index is consumable as a file source", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(dir, _golang.DefaultIndexName))
+		if err != nil {
+			t.Fatalf("Expected index.json: %v", err)
+		}
+		var releases []_golang.Release
+		if err := json.Unmarshal(data, &releases); err != nil {
+			t.Fatalf("Invalid index: %v", err)
+		}
+		if len(releases) != 2 || releases[0].Files[0].URL != "" {
+			t.Fatalf("Unexpected index contents: %+v", releases)
+		}
+
+		_golang.ClearReleasesCache()
+		defer _golang.ClearReleasesCache()
+
+		src, err := _golang.NewFileSource(_golang.FileURL(filepath.Join(dir, _golang.DefaultIndexName)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		file, err := _golang.GetPlatformFileInfoFromSource(src, "1.22.1", "darwin", "arm64", time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		path, _ := _golang.FilePath(src.DownloadURL(*file))
+		if path != filepath.Join(dir, "go1.22.1.darwin-arm64.tar.gz") {
+			t.Errorf("Unexpected archive path %s", path)
+		}
+	})
+
+	t.Run("Republished archive is downloaded again", func(t *testing.T) {
+		fetched = nil
+		release := upstreamRelease("1.22.2", "linux-amd64")
+		release.Files[0].Sha256 = strings.Repeat("a", 64)
+
+		if _, err := Sync(dir, []_golang.Release{release}, fakeFetch(dir, &fetched), SyncOptions{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(fetched) != 1 {
+			t.Errorf("Expected changed archive to be fetched, got %v", fetched)
+		}
+	})
+
+	t.Run("Prune disabled keeps archives", func(t *testing.T) {
+		entries, _ := os.ReadDir(dir)
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		sort.Strings(names)
+		if !strings.Contains(strings.Join(names, ","), "go1.22.1.linux-amd64.tar.gz") {
+			t.Errorf("Expected archives to be kept without prune, got %v", names)
+		}
+	})
+}
+
+func TestSyncFetchError(t *testing.T) {
+	dir := t.TempDir()
+	fetch := func(file _golang.File) error {
+		return fmt.Errorf("checksum mismatch")
+	}
+
+	if _, err := Sync(dir, []_golang.Release{upstreamRelease("1.22.1", "linux-amd64")}, fetch, SyncOptions{Prune: true}); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, _golang.DefaultIndexName)); !os.IsNotExist(err) {
+		t.Error("Expected no index to be written after a failed sync")
+	}
+}
+
+func TestSyncSigning(t *testing.T) {
+	dir := t.TempDir()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	var fetched []string
+	releases := []_golang.Release{upstreamRelease("1.22.1", "linux-amd64")}
+
+	result, err := Sync(dir, releases, fakeFetch(dir, &fetched), SyncOptions{Prune: true, SigningKey: priv})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.SignaturePath != filepath.Join(dir, "index.json.sig") {
+		t.Errorf("Unexpected signature path %s", result.SignaturePath)
+	}
+
+	verifier, _ := _golang.NewIndexVerifier([]string{_golang.EncodePublicKey(pub)}, true)
+	index, _ := os.ReadFile(result.IndexPath)
+	sig, _ := os.ReadFile(result.SignaturePath)
+	if signed, err := verifier.Verify(index, sig); !signed || err != nil {
+		t.Errorf("Expected valid signature, got %v, %v", signed, err)
+	}
+
+	t.Run("Unsigned sync removes stale signature", func(t *testing.T) {
+		if _, err := Sync(dir, releases, fakeFetch(dir, &fetched), SyncOptions{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "index.json.sig")); !os.IsNotExist(err) {
+			t.Error("Expected stale signature to be removed")
+		}
+	})
+}
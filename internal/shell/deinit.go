@@ -0,0 +1,198 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// now returns the current time; replaced in tests
+var now = time.Now
+
+// All returns every shell govman can integrate with, including registered templates, whether or not it is installed.
+func All() []Shell {
+	return withTemplates([]Shell{
+		&BashShell{},
+		&ZshShell{},
+		&FishShell{},
+		&PowerShell{},
+		&CmdShell{},
+		&NuShell{},
+		&XonshShell{},
+	})
+}
+
+// DeinitializeShell removes govman integration for shell from every configuration file it may have been written to.
+// For Command Prompt the govman.bat wrapper in binPath is deleted. Returns the files changed.
+func DeinitializeShell(shell Shell, binPath string, opts Options) ([]FileChange, error) {
+	var changed []FileChange
+
+	if shell.Name() == "cmd" {
+		wrapperPath := filepath.Join(binPath, "govman.bat")
+		content, err := os.ReadFile(wrapperPath)
+		if os.IsNotExist(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read wrapper: %w", err)
+		}
+
+		change, err := removeFile(wrapperPath, string(content), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove wrapper %s: %w", wrapperPath, err)
+		}
+
+		return []FileChange{change}, nil
+	}
+
+	for _, configFile := range knownConfigFiles(shell) {
+		content, err := os.ReadFile(configFile)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return changed, fmt.Errorf("failed to read %s: %w", configFile, err)
+		}
+
+		original := string(content)
+		if !containsGovmanConfig(original) {
+			continue
+		}
+
+		cleaned := removeExistingConfig(original)
+		if cleaned == strings.TrimSpace(original) {
+			// Only legacy markers outside a GOVMAN block, which cannot be removed safely
+			fmt.Fprintf(os.Stderr, "⚠️  %s mentions govman outside a GOVMAN block; remove it manually\n", configFile)
+			continue
+		}
+		if cleaned != "" {
+			cleaned += lineEnding(original)
+		}
+
+		change, err := updateFile(configFile, original, cleaned, true, opts)
+		if err != nil {
+			return changed, fmt.Errorf("failed to write config to %s: %w", configFile, err)
+		}
+		changed = append(changed, change)
+	}
+
+	return changed, nil
+}
+
+// knownConfigFiles lists every configuration file govman init may have written for shell.
+func knownConfigFiles(shell Shell) []string {
+	files := []string{shell.ConfigFile()}
+
+	if home, err := userHomeDir(); err == nil {
+		switch shell.Name() {
+		case "bash":
+			files = append(files,
+				filepath.Join(home, ".bashrc"),
+				filepath.Join(home, ".bash_profile"),
+				filepath.Join(home, ".profile"),
+			)
+		case "zsh":
+			files = append(files, filepath.Join(home, ".zshrc"), filepath.Join(home, ".zprofile"))
+		case "powershell":
+			files = append(files,
+				filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1"),
+				filepath.Join(home, "Documents", "WindowsPowerShell", "Microsoft.PowerShell_profile.ps1"),
+			)
+		case "nu":
+			files = append(files, nuConfigCandidates(home)...)
+		case "xonsh":
+			files = append(files, xonshConfigCandidates(home)...)
+		}
+	}
+
+	// Remove duplicates, keeping the first occurrence
+	seen := make(map[string]bool)
+	var unique []string
+	for _, file := range files {
+		if !seen[file] {
+			seen[file] = true
+			unique = append(unique, file)
+		}
+	}
+
+	return unique
+}
+
+// updateFile writes after to path, or prints a unified diff from before when opts.DryRun is set.
+// If the file exists it is backed up first. Returns what was (or would be) done to the file.
+func updateFile(path, before, after string, exists bool, opts Options) (FileChange, error) {
+	change := FileChange{Path: path, Action: "created"}
+	if exists {
+		change.Action = "updated"
+		if before == after {
+			change.Action = "unchanged"
+		}
+	}
+
+	if opts.DryRun {
+		fmt.Fprint(opts.output(), unifiedDiff(path, before, after))
+		return change, nil
+	}
+
+	if change.Action == "updated" {
+		backup, err := backupFile(path, before)
+		if err != nil {
+			return FileChange{}, err
+		}
+		change.Backup = backup
+	}
+
+	if err := os.WriteFile(path, []byte(after), 0644); err != nil {
+		return FileChange{}, err
+	}
+
+	return change, nil
+}
+
+// removeFile deletes path after backing it up, or prints a unified diff when opts.DryRun is set.
+// Returns what was (or would be) done to the file.
+func removeFile(path, content string, opts Options) (FileChange, error) {
+	change := FileChange{Path: path, Action: "removed"}
+
+	if opts.DryRun {
+		fmt.Fprint(opts.output(), unifiedDiff(path, content, ""))
+		return change, nil
+	}
+
+	backup, err := backupFile(path, content)
+	if err != nil {
+		return FileChange{}, err
+	}
+	change.Backup = backup
+
+	if err := os.Remove(path); err != nil {
+		return FileChange{}, err
+	}
+
+	return change, nil
+}
+
+// backupFile saves content next to path with a timestamp suffix, never overwriting an earlier backup.
+// Returns the backup path.
+func backupFile(path, content string) (string, error) {
+	base := fmt.Sprintf("%s.govman-backup-%s", path, now().Format("20060102-150405"))
+
+	backup := base
+	for i := 2; fileExists(backup); i++ {
+		backup = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	if err := os.WriteFile(backup, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	return backup, nil
+}
+
+// lineEnding returns the line terminator used by content
+func lineEnding(content string) string {
+	if strings.Contains(content, "\r\n") {
+		return "\r\n"
+	}
+	return "\n"
+}
@@ -0,0 +1,157 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupDeinitTest points the home directory at a temporary directory and fixes the backup timestamp
+func setupDeinitTest(t *testing.T) string {
+	t.Helper()
+
+	originalUserHomeDir := userHomeDir
+	originalNow := now
+	originalGOOS := currentGOOS
+	t.Cleanup(func() {
+		userHomeDir = originalUserHomeDir
+		now = originalNow
+		currentGOOS = originalGOOS
+	})
+
+	home := t.TempDir()
+	userHomeDir = func() (string, error) {
+		return home, nil
+	}
+	now = func() time.Time {
+		return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	currentGOOS = "linux"
+
+	return home
+}
+
+func TestDeinitializeShell(t *testing.T) {
+	home := setupDeinitTest(t)
+	binPath := t.TempDir()
+
+	block := strings.Join((&BashShell{}).SetupCommands(binPath), "\n")
+	bashrc := filepath.Join(home, ".bashrc")
+	profile := filepath.Join(home, ".profile")
+	os.WriteFile(bashrc, []byte("alias ll='ls -l'\n"+block+"\n"), 0644)
+	os.WriteFile(profile, []byte(block+"\n"), 0644)
+	os.WriteFile(filepath.Join(home, ".bash_profile"), []byte("source ~/.bashrc\n"), 0644)
+
+	t.Run("Dry run", func(t *testing.T) {
+		var out strings.Builder
+		changed, err := DeinitializeShell(&BashShell{}, binPath, Options{DryRun: true, Output: &out})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(changed) != 2 {
+			t.Errorf("Expected 2 files to change, got %v", changed)
+		}
+		if !strings.Contains(out.String(), "--- "+bashrc) || !strings.Contains(out.String(), "-# GOVMAN - Go Version Manager") {
+			t.Errorf("Expected a diff for %s, got:\n%s", bashrc, out.String())
+		}
+		if content, _ := os.ReadFile(bashrc); !containsGovmanConfig(string(content)) {
+			t.Error("Dry run should not change files")
+		}
+	})
+
+	t.Run("Removes block from every file", func(t *testing.T) {
+		changed, err := DeinitializeShell(&BashShell{}, binPath, Options{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(changed) != 2 {
+			t.Errorf("Expected 2 files to change, got %v", changed)
+		}
+
+		if content, _ := os.ReadFile(bashrc); string(content) != "alias ll='ls -l'\n" {
+			t.Errorf("Expected user content to be kept, got %q", content)
+		}
+		if content, _ := os.ReadFile(profile); len(content) != 0 {
+			t.Errorf("Expected empty .profile, got %q", content)
+		}
+
+		backup := bashrc + ".govman-backup-20250102-030405"
+		if content, err := os.ReadFile(backup); err != nil || !containsGovmanConfig(string(content)) {
+			t.Errorf("Expected backup %s with the original content: %v", backup, err)
+		}
+	})
+
+	t.Run("Nothing left to remove", func(t *testing.T) {
+		changed, err := DeinitializeShell(&BashShell{}, binPath, Options{})
+		if err != nil || len(changed) != 0 {
+			t.Errorf("Expected no changes, got %v (%v)", changed, err)
+		}
+	})
+
+	t.Run("Command Prompt wrapper", func(t *testing.T) {
+		wrapper := filepath.Join(binPath, "govman.bat")
+		os.WriteFile(wrapper, []byte("@echo off\r\n"), 0644)
+
+		changed, err := DeinitializeShell(&CmdShell{}, binPath, Options{})
+		if err != nil || len(changed) != 1 {
+			t.Fatalf("Expected the wrapper to be removed, got %v (%v)", changed, err)
+		}
+		if fileExists(wrapper) {
+			t.Error("Expected wrapper to be deleted")
+		}
+		if !fileExists(wrapper + ".govman-backup-20250102-030405") {
+			t.Error("Expected wrapper backup")
+		}
+	})
+}
+
+func TestInitializeShellBackup(t *testing.T) {
+	home := setupDeinitTest(t)
+	binPath := t.TempDir()
+
+	zshrc := filepath.Join(home, ".zshrc")
+	os.WriteFile(zshrc, []byte("setopt autocd\n"), 0644)
+
+	var out strings.Builder
+	if _, err := InitializeShell(&ZshShell{}, binPath, Options{DryRun: true, Output: &out}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "+# GOVMAN - Go Version Manager") {
+		t.Errorf("Expected dry run diff, got:\n%s", out.String())
+	}
+	if content, _ := os.ReadFile(zshrc); string(content) != "setopt autocd\n" {
+		t.Errorf("Dry run should not change files, got %q", content)
+	}
+
+	if _, err := InitializeShell(&ZshShell{}, binPath, Options{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if content, _ := os.ReadFile(zshrc + ".govman-backup-20250102-030405"); string(content) != "setopt autocd\n" {
+		t.Errorf("Expected backup of the original file, got %q", content)
+	}
+
+	// A second change in the same second keeps the first backup
+	os.WriteFile(zshrc, []byte("setopt autocd\nsetopt nobeep\n"), 0644)
+	if _, err := InitializeShell(&ZshShell{}, binPath, Options{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !fileExists(zshrc + ".govman-backup-20250102-030405-2") {
+		t.Error("Expected a second, numbered backup")
+	}
+}
+
+func TestKnownConfigFiles(t *testing.T) {
+	home := setupDeinitTest(t)
+
+	files := knownConfigFiles(&BashShell{})
+	expected := []string{
+		filepath.Join(home, ".bashrc"),
+		filepath.Join(home, ".bash_profile"),
+		filepath.Join(home, ".profile"),
+	}
+	if strings.Join(files, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, files)
+	}
+}
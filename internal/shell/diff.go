@@ -0,0 +1,148 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffLine is a single line of a line-based diff
+type diffLine struct {
+	op   byte // ' ' unchanged, '-' removed, '+' added
+	text string
+}
+
+// unifiedDiff returns a unified diff that turns before into after, labelled with path.
+// Returns an empty string if the contents are identical.
+func unifiedDiff(path, before, after string) string {
+	if before == after {
+		return ""
+	}
+
+	lines := diffLines(splitLines(before), splitLines(after))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", path, path)
+
+	oldLine, newLine := 1, 1
+	for start := 0; start < len(lines); {
+		// Skip to the next change
+		next := start
+		for next < len(lines) && lines[next].op == ' ' {
+			next++
+		}
+		if next == len(lines) {
+			break
+		}
+
+		hunkStart := max(next-diffContext, start)
+		for i := start; i < hunkStart; i++ {
+			oldLine++
+			newLine++
+		}
+
+		// Extend the hunk while changes are within twice the context of each other
+		hunkEnd, unchanged := next, 0
+		for i := next; i < len(lines); i++ {
+			if lines[i].op != ' ' {
+				unchanged = 0
+				hunkEnd = i + 1
+				continue
+			}
+			unchanged++
+			if unchanged > 2*diffContext {
+				break
+			}
+		}
+		hunkEnd = min(hunkEnd+diffContext, len(lines))
+
+		var oldCount, newCount int
+		var body strings.Builder
+		for _, line := range lines[hunkStart:hunkEnd] {
+			if line.op != '+' {
+				oldCount++
+			}
+			if line.op != '-' {
+				newCount++
+			}
+			body.WriteByte(line.op)
+			body.WriteString(line.text)
+			body.WriteByte('\n')
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+		out.WriteString(body.String())
+
+		oldLine += oldCount
+		newLine += newCount
+		start = hunkEnd
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the start,count pair of a hunk header
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// An empty range refers to the line before it
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits content into lines without their terminators
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// diffLines computes a line diff from the longest common subsequence of a and b
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+
+	return lines
+}
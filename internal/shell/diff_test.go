@@ -0,0 +1,73 @@
+package shell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		before   string
+		after    string
+		expected string
+	}{
+		{
+			name:     "Identical",
+			before:   "a\nb\n",
+			after:    "a\nb\n",
+			expected: "",
+		},
+		{
+			name:   "New file",
+			before: "",
+			after:  "a\nb\n",
+			expected: "--- rc\n+++ rc\n" +
+				"@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:   "Removed file",
+			before: "a\n",
+			after:  "",
+			expected: "--- rc\n+++ rc\n" +
+				"@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:   "Context around a change",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			after:  "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			expected: "--- rc\n+++ rc\n" +
+				"@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name:   "Separate hunks",
+			before: "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			after:  "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			expected: "--- rc\n+++ rc\n" +
+				"@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n" +
+				"@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+		{
+			name:   "CRLF line endings",
+			before: "a\r\nb\r\n",
+			after:  "a\r\n",
+			expected: "--- rc\n+++ rc\n" +
+				"@@ -1,2 +1 @@\n a\n-b\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := unifiedDiff("rc", tc.before, tc.after); result != tc.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffMergesNearbyChanges(t *testing.T) {
+	result := unifiedDiff("rc", "a\n1\n2\n3\nb\n", "A\n1\n2\n3\nB\n")
+	if strings.Count(result, "@@ -") != 1 {
+		t.Errorf("Expected a single hunk, got:\n%s", result)
+	}
+}
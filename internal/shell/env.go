@@ -0,0 +1,194 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvChange is a single environment variable update for a shell to evaluate.
+// Unset removes the variable; otherwise it is set to Value.
+type EnvChange struct {
+	Name  string
+	Value string
+	Unset bool
+}
+
+// ForName returns the Shell implementation for a shell name such as "bash" or "pwsh",
+// preferring a user-defined template registered with RegisterTemplates.
+// Returns an error if the shell is not supported.
+func ForName(name string) (Shell, error) {
+	if shell := LookupTemplate(name); shell != nil {
+		return shell, nil
+	}
+
+	switch strings.ToLower(name) {
+	case "bash":
+		return &BashShell{}, nil
+	case "zsh":
+		return &ZshShell{}, nil
+	case "fish":
+		return &FishShell{}, nil
+	case "powershell", "pwsh":
+		return &PowerShell{}, nil
+	case "cmd":
+		return &CmdShell{}, nil
+	case "nu", "nushell":
+		return &NuShell{}, nil
+	case "xonsh":
+		return &XonshShell{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported shell %q (supported: bash, zsh, fish, powershell, cmd, nu, xonsh)", name)
+	}
+}
+
+// EnvCommands renders environment changes in the syntax of the given shell.
+// Returns one command per change, or an error if the shell cannot evaluate them.
+func EnvCommands(shell Shell, changes []EnvChange) ([]string, error) {
+	if shell.Name() == "nu" {
+		// Nushell cannot evaluate generated code, so its integration loads a JSON record instead
+		if len(changes) == 0 {
+			return nil, nil
+		}
+		output, err := EnvJSON(changes)
+		if err != nil {
+			return nil, err
+		}
+		return []string{output}, nil
+	}
+
+	commands := make([]string, 0, len(changes))
+
+	for _, change := range changes {
+		switch shell.Name() {
+		case "bash", "zsh":
+			if change.Unset {
+				commands = append(commands, fmt.Sprintf("unset %s", change.Name))
+			} else {
+				commands = append(commands, fmt.Sprintf("export %s=%s", change.Name, quotePOSIX(change.Value)))
+			}
+		case "fish":
+			if change.Unset {
+				commands = append(commands, fmt.Sprintf("set -e %s", change.Name))
+			} else if change.Name == "PATH" {
+				// Fish keeps PATH as a list, so pass each entry separately
+				entries := filepath.SplitList(change.Value)
+				for i, entry := range entries {
+					entries[i] = quoteFish(entry)
+				}
+				commands = append(commands, fmt.Sprintf("set -gx PATH %s", strings.Join(entries, " ")))
+			} else {
+				commands = append(commands, fmt.Sprintf("set -gx %s %s", change.Name, quoteFish(change.Value)))
+			}
+		case "powershell":
+			if change.Unset {
+				commands = append(commands, fmt.Sprintf("Remove-Item Env:%s -ErrorAction SilentlyContinue", change.Name))
+			} else {
+				commands = append(commands, fmt.Sprintf("$env:%s = %s", change.Name, quotePowerShell(change.Value)))
+			}
+		case "cmd":
+			// The quotes keep trailing spaces and special characters out of the value
+			commands = append(commands, fmt.Sprintf(`set "%s=%s"`, change.Name, change.Value))
+		case "xonsh":
+			if change.Unset {
+				commands = append(commands, fmt.Sprintf("${...}.pop(%s, None)", quoteXonsh(change.Name)))
+			} else {
+				commands = append(commands, fmt.Sprintf("$%s = %s", change.Name, quoteXonsh(change.Value)))
+			}
+		default:
+			return nil, fmt.Errorf("%s does not support evaluating environment changes", shell.DisplayName())
+		}
+	}
+
+	return commands, nil
+}
+
+// EnvJSON renders environment changes as a JSON object, with null for unset variables.
+// Returns the encoded object or an error if encoding fails.
+func EnvJSON(changes []EnvChange) (string, error) {
+	values := make(map[string]*string, len(changes))
+	for _, change := range changes {
+		if change.Unset {
+			values[change.Name] = nil
+			continue
+		}
+		value := change.Value
+		values[change.Name] = &value
+	}
+
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode environment: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// ApplyEnv returns a copy of environ, a list of "NAME=value" entries such as os.Environ, with changes applied.
+// Names are matched case-insensitively on Windows.
+func ApplyEnv(environ []string, changes []EnvChange) []string {
+	result := append([]string(nil), environ...)
+
+	for _, change := range changes {
+		kept := result[:0]
+		for _, entry := range result {
+			name, _, _ := strings.Cut(entry, "=")
+			if name == change.Name || (currentGOOS == "windows" && strings.EqualFold(name, change.Name)) {
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		result = kept
+
+		if !change.Unset {
+			result = append(result, change.Name+"="+change.Value)
+		}
+	}
+
+	return result
+}
+
+// PrependPath returns pathList with dir moved to the front and empty entries dropped.
+// Entries are separated by the OS path list separator.
+func PrependPath(pathList, dir string) string {
+	var entries []string
+	if dir != "" {
+		entries = append(entries, dir)
+	}
+
+	for _, entry := range filepath.SplitList(pathList) {
+		if entry == "" || entry == dir {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return strings.Join(entries, string(os.PathListSeparator))
+}
+
+// quotePOSIX single-quotes a value for bash/zsh
+func quotePOSIX(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// quoteFish single-quotes a value for fish, where only backslash and quote are special
+func quoteFish(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`'`, `\'`,
+	)
+	return "'" + replacer.Replace(value) + "'"
+}
+
+// quotePowerShell single-quotes a value for PowerShell by doubling embedded quotes
+func quotePowerShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// quoteXonsh single-quotes a value as a Python string literal for xonsh
+func quoteXonsh(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`)
+	return "'" + replacer.Replace(value) + "'"
+}
@@ -0,0 +1,274 @@
+package shell
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestForName(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expected    string
+		expectError bool
+	}{
+		{name: "bash", expected: "bash"},
+		{name: "zsh", expected: "zsh"},
+		{name: "fish", expected: "fish"},
+		{name: "powershell", expected: "powershell"},
+		{name: "pwsh", expected: "powershell"},
+		{name: "CMD", expected: "cmd"},
+		{name: "nu", expected: "nu"},
+		{name: "nushell", expected: "nu"},
+		{name: "xonsh", expected: "xonsh"},
+		{name: "tcsh", expectError: true},
+		{name: "", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			shell, err := ForName(tc.name)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if shell.Name() != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, shell.Name())
+			}
+		})
+	}
+}
+
+func TestEnvCommands(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	changes := []EnvChange{
+		{Name: "PATH", Value: "/opt/go/bin" + sep + "/usr/bin"},
+		{Name: "STATE", Value: `it's "$x"`},
+		{Name: "OLD", Unset: true},
+	}
+
+	testCases := []struct {
+		shell    Shell
+		expected []string
+	}{
+		{&BashShell{}, []string{
+			`export PATH='/opt/go/bin` + sep + `/usr/bin'`,
+			`export STATE='it'\''s "$x"'`,
+			`unset OLD`,
+		}},
+		{&ZshShell{}, []string{
+			`export PATH='/opt/go/bin` + sep + `/usr/bin'`,
+			`export STATE='it'\''s "$x"'`,
+			`unset OLD`,
+		}},
+		{&FishShell{}, []string{
+			`set -gx PATH '/opt/go/bin' '/usr/bin'`,
+			`set -gx STATE 'it\'s "$x"'`,
+			`set -e OLD`,
+		}},
+		{&PowerShell{}, []string{
+			`$env:PATH = '/opt/go/bin` + sep + `/usr/bin'`,
+			`$env:STATE = 'it''s "$x"'`,
+			`Remove-Item Env:OLD -ErrorAction SilentlyContinue`,
+		}},
+		{&XonshShell{}, []string{
+			`$PATH = '/opt/go/bin` + sep + `/usr/bin'`,
+			`$STATE = 'it\'s "$x"'`,
+			`${...}.pop('OLD', None)`,
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.shell.Name(), func(t *testing.T) {
+			commands, err := EnvCommands(tc.shell, changes)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Join(commands, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(tc.expected, "\n"), strings.Join(commands, "\n"))
+			}
+		})
+	}
+
+	t.Run("nu", func(t *testing.T) {
+		commands, err := EnvCommands(&NuShell{}, changes)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var values map[string]*string
+		if len(commands) != 1 || json.Unmarshal([]byte(commands[0]), &values) != nil {
+			t.Fatalf("Expected a single JSON record, got %v", commands)
+		}
+		if values["STATE"] == nil || *values["STATE"] != `it's "$x"` || values["OLD"] != nil {
+			t.Errorf("Unexpected record %s", commands[0])
+		}
+
+		if commands, _ := EnvCommands(&NuShell{}, nil); len(commands) != 0 {
+			t.Errorf("Expected no output without changes, got %v", commands)
+		}
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		commands, err := EnvCommands(&CmdShell{}, changes)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `set "PATH=/opt/go/bin` + sep + `/usr/bin"` + "\n" + `set "STATE=it's "$x""` + "\n" + `set "OLD="`
+		if strings.Join(commands, "\n") != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, strings.Join(commands, "\n"))
+		}
+	})
+}
+
+func TestApplyEnv(t *testing.T) {
+	originalGOOS := currentGOOS
+	defer func() { currentGOOS = originalGOOS }()
+
+	environ := []string{"PATH=/usr/bin", "Path=/windows", "GOROOT=/old", "HOME=/home/user"}
+	changes := []EnvChange{
+		{Name: "PATH", Value: "/go/bin:/usr/bin"},
+		{Name: "GOROOT", Unset: true},
+		{Name: "GOVMAN_SHELL", Value: "1.22.1"},
+	}
+
+	testCases := []struct {
+		goos     string
+		expected []string
+	}{
+		{
+			goos:     "linux",
+			expected: []string{"Path=/windows", "HOME=/home/user", "PATH=/go/bin:/usr/bin", "GOVMAN_SHELL=1.22.1"},
+		},
+		{
+			goos:     "windows",
+			expected: []string{"HOME=/home/user", "PATH=/go/bin:/usr/bin", "GOVMAN_SHELL=1.22.1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.goos, func(t *testing.T) {
+			currentGOOS = tc.goos
+			result := ApplyEnv(environ, changes)
+			if strings.Join(result, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+
+	if environ[0] != "PATH=/usr/bin" {
+		t.Errorf("Expected the input to be left unchanged, got %v", environ)
+	}
+}
+
+func TestPrependPath(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	join := func(entries ...string) string { return strings.Join(entries, sep) }
+
+	testCases := []struct {
+		name     string
+		pathList string
+		dir      string
+		expected string
+	}{
+		{"Prepends directory", join("/usr/bin", "/bin"), "/go/bin", join("/go/bin", "/usr/bin", "/bin")},
+		{"Moves existing directory to front", join("/usr/bin", "/go/bin"), "/go/bin", join("/go/bin", "/usr/bin")},
+		{"Empty directory", join("/usr/bin", "/bin"), "", join("/usr/bin", "/bin")},
+		{"Drops empty entries", join("", "/usr/bin", ""), "/go/bin", join("/go/bin", "/usr/bin")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := PrependPath(tc.pathList, tc.dir); result != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestEnvJSON(t *testing.T) {
+	output, err := EnvJSON([]EnvChange{{Name: "GOROOT", Value: `C:\go "1"`}, {Name: "OLD", Unset: true}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var values map[string]*string
+	if err := json.Unmarshal([]byte(output), &values); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", output, err)
+	}
+	if values["GOROOT"] == nil || *values["GOROOT"] != `C:\go "1"` {
+		t.Errorf("Unexpected GOROOT in %s", output)
+	}
+	if value, ok := values["OLD"]; !ok || value != nil {
+		t.Errorf("Expected OLD to be null in %s", output)
+	}
+}
+
+func TestSetupCommandsUseHookEnv(t *testing.T) {
+	for _, shell := range []Shell{&BashShell{}, &ZshShell{}, &FishShell{}, &PowerShell{}} {
+		t.Run(shell.Name(), func(t *testing.T) {
+			setup := strings.Join(shell.SetupCommands("/usr/local/bin"), "\n")
+			if !strings.Contains(setup, "hook-env --shell "+shell.Name()) {
+				t.Errorf("Expected setup to evaluate hook-env for %s", shell.Name())
+			}
+			if strings.Contains(setup, "config.yaml") || strings.Contains(setup, "go version") {
+				t.Error("Setup should not parse the config file or run 'go version'")
+			}
+		})
+	}
+}
+
+func TestSetupCommandsPassPreviousVersion(t *testing.T) {
+	testCases := []struct {
+		shell    Shell
+		expected string
+	}{
+		{shell: &BashShell{}, expected: `"$arg" == "-"`},
+		{shell: &ZshShell{}, expected: `"$arg" == "-"`},
+		{shell: &FishShell{}, expected: `test "$arg" = -`},
+		{shell: &PowerShell{}, expected: `$_ -eq '-'`},
+		{shell: &NuShell{}, expected: `$arg == "-"`},
+		{shell: &XonshShell{}, expected: `arg == "-"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.shell.Name(), func(t *testing.T) {
+			setup := strings.Join(tc.shell.SetupCommands("/usr/local/bin"), "\n")
+			if !strings.Contains(setup, tc.expected) {
+				t.Errorf("Expected the wrapper to pass '-' to 'govman env', missing %s", tc.expected)
+			}
+		})
+	}
+}
+
+func TestCompletionFile(t *testing.T) {
+	binPath := filepath.Join("home", ".govman", "bin")
+	testCases := []struct {
+		shell    Shell
+		expected string
+	}{
+		{&BashShell{}, filepath.Join("home", ".govman", "completions", "govman.bash")},
+		{&ZshShell{}, filepath.Join("home", ".govman", "completions", "_govman")},
+		{&FishShell{}, filepath.Join("home", ".govman", "completions", "govman.fish")},
+		{&PowerShell{}, filepath.Join("home", ".govman", "completions", "govman.ps1")},
+		{&CmdShell{}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.shell.Name(), func(t *testing.T) {
+			if file := CompletionFile(tc.shell, binPath); file != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, file)
+			}
+			if tc.expected != "" && !strings.Contains(strings.Join(tc.shell.SetupCommands(binPath), "\n"), filepath.Base(tc.expected)) {
+				t.Errorf("Expected setup to source %s", filepath.Base(tc.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoginConfigFiles returns the files login shells read instead of the interactive configuration,
+// so that non-interactive sessions such as `ssh host 'go build'` also get the govman PATH.
+// Returns nil for shells whose configuration file is read by login shells too.
+func LoginConfigFiles(shell Shell) []string {
+	home, err := userHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	switch shell.Name() {
+	case "bash":
+		// Bash reads only the first of these that exists; sh and dash read .profile
+		var files []string
+		for _, name := range []string{".bash_profile", ".profile"} {
+			if file := filepath.Join(home, name); fileExists(file) {
+				files = append(files, file)
+			}
+		}
+		if len(files) == 0 {
+			files = []string{filepath.Join(home, ".profile")}
+		}
+		return files
+	case "zsh":
+		return []string{filepath.Join(home, ".zprofile")}
+	default:
+		return nil
+	}
+}
+
+// loginSetupCommands returns the POSIX lines that put binPath on PATH for login shells.
+// Unlike SetupCommands it installs no wrapper or prompt hook, and it is safe to evaluate twice.
+func loginSetupCommands(binPath string) []string {
+	escapedPath := escapeBashPath(binPath)
+
+	return []string{
+		"# GOVMAN - Go Version Manager (login shells)",
+		`case ":$PATH:" in`,
+		fmt.Sprintf(`    *":%s:"*) ;;`, escapedPath),
+		fmt.Sprintf(`    *) export PATH="%s:$PATH" ;;`, escapedPath),
+		"esac",
+		"export GOTOOLCHAIN=local",
+		"# END GOVMAN",
+	}
+}
+
+// initializeLoginFiles adds the login-shell PATH block to each of shell's login files.
+// Files that are the shell's interactive configuration are skipped, and files already configured
+// are left alone unless opts.Force is set. Returns the files changed.
+func initializeLoginFiles(shell Shell, binPath string, opts Options) ([]FileChange, error) {
+	var changes []FileChange
+
+	for _, loginFile := range LoginConfigFiles(shell) {
+		if loginFile == shell.ConfigFile() {
+			continue
+		}
+
+		var existingContent string
+		exists := false
+		if content, err := os.ReadFile(loginFile); err == nil {
+			existingContent = string(content)
+			exists = true
+		} else if !os.IsNotExist(err) {
+			return changes, fmt.Errorf("failed to read %s: %w", loginFile, err)
+		}
+		originalContent := existingContent
+
+		if containsGovmanConfig(existingContent) {
+			if !opts.Force {
+				changes = append(changes, FileChange{Path: loginFile, Action: "unchanged"})
+				continue
+			}
+			existingContent = removeExistingConfig(existingContent)
+		}
+
+		newConfig := "\n" + strings.Join(loginSetupCommands(binPath), "\n") + "\n"
+		finalContent := strings.TrimSpace(existingContent) + newConfig
+
+		change, err := updateFile(loginFile, originalContent, finalContent, exists, opts)
+		if err != nil {
+			return changes, fmt.Errorf("failed to write config to %s: %w", loginFile, err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,105 @@
+package shell
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoginConfigFiles(t *testing.T) {
+	home := setupDeinitTest(t)
+
+	testCases := []struct {
+		name     string
+		shell    Shell
+		existing []string
+		expected []string
+	}{
+		{"Bash without login files", &BashShell{}, nil, []string{".profile"}},
+		{"Bash with .bash_profile", &BashShell{}, []string{".bash_profile"}, []string{".bash_profile"}},
+		{"Bash with both", &BashShell{}, []string{".bash_profile", ".profile"}, []string{".bash_profile", ".profile"}},
+		{"Zsh", &ZshShell{}, nil, []string{".zprofile"}},
+		{"Fish", &FishShell{}, nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, name := range tc.existing {
+				os.WriteFile(filepath.Join(home, name), []byte(""), 0644)
+				defer os.Remove(filepath.Join(home, name))
+			}
+
+			var expected []string
+			for _, name := range tc.expected {
+				expected = append(expected, filepath.Join(home, name))
+			}
+
+			if files := LoginConfigFiles(tc.shell); strings.Join(files, ",") != strings.Join(expected, ",") {
+				t.Errorf("Expected %v, got %v", expected, files)
+			}
+		})
+	}
+}
+
+func TestInitializeShellLoginShells(t *testing.T) {
+	home := setupDeinitTest(t)
+	binPath := t.TempDir()
+
+	bashrc := filepath.Join(home, ".bashrc")
+	bashProfile := filepath.Join(home, ".bash_profile")
+	os.WriteFile(bashrc, []byte("alias ll='ls -l'\n"), 0644)
+	os.WriteFile(bashProfile, []byte(". ~/.bashrc\n"), 0644)
+
+	t.Run("Configures interactive and login files", func(t *testing.T) {
+		changes, err := InitializeShell(&BashShell{}, binPath, Options{LoginShells: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(changes) != 2 || changes[0].Path != bashrc || changes[1].Path != bashProfile {
+			t.Fatalf("Expected .bashrc and .bash_profile to change, got %+v", changes)
+		}
+		if changes[1].Action != "updated" || changes[1].Backup == "" {
+			t.Errorf("Expected .bash_profile to be updated with a backup, got %+v", changes[1])
+		}
+
+		content, _ := os.ReadFile(bashProfile)
+		if !strings.HasPrefix(string(content), ". ~/.bashrc\n") || !strings.Contains(string(content), `export PATH="`+binPath+`:$PATH"`) {
+			t.Errorf("Unexpected .bash_profile:\n%s", content)
+		}
+		if strings.Contains(string(content), "govman_auto_switch") {
+			t.Error("Login files should not install the prompt hook")
+		}
+		if fileExists(filepath.Join(home, ".profile")) {
+			t.Error("Expected .profile to be left alone when .bash_profile exists")
+		}
+	})
+
+	t.Run("Already configured", func(t *testing.T) {
+		changes, err := InitializeShell(&BashShell{}, binPath, Options{LoginShells: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, change := range changes {
+			if change.Action != "unchanged" {
+				t.Errorf("Expected no changes, got %+v", change)
+			}
+		}
+
+		if _, err := InitializeShell(&BashShell{}, binPath, Options{}); !errors.Is(err, ErrAlreadyConfigured) {
+			t.Errorf("Expected ErrAlreadyConfigured without login shells, got %v", err)
+		}
+	})
+
+	t.Run("Zsh creates .zprofile", func(t *testing.T) {
+		changes, err := InitializeShell(&ZshShell{}, binPath, Options{LoginShells: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		zprofile := filepath.Join(home, ".zprofile")
+		if len(changes) != 2 || changes[1].Path != zprofile || changes[1].Action != "created" {
+			t.Errorf("Expected .zprofile to be created, got %+v", changes)
+		}
+	})
+}
@@ -1,7 +1,9 @@
 package shell
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -16,9 +18,13 @@ var (
 	execLookPath       = exec.LookPath
 	userHomeDir        = os.UserHomeDir
 	newlineRegex       = regexp.MustCompile(`\n{3,}`)
-	configRemovalRegex = regexp.MustCompile(`(?ms)^[#\s]*(REM\s+)?GOVMAN - Go Version Manager.*?^[#\s]*(REM\s+)?END GOVMAN.*?$\n?`)
+	configRemovalRegex = regexp.MustCompile(`(?ms)^[^\w\n]*(REM\s+)?GOVMAN - Go Version Manager.*?^[^\w\n]*(REM\s+)?END GOVMAN.*?$\n?`)
 )
 
+// ErrAlreadyConfigured is returned by InitializeShell when the configuration file already
+// contains govman integration and Options.Force is not set.
+var ErrAlreadyConfigured = errors.New("govman is already configured")
+
 type Shell interface {
 	Name() string
 	DisplayName() string
@@ -34,6 +40,8 @@ type ZshShell struct{}
 type FishShell struct{}
 type PowerShell struct{}
 type CmdShell struct{}
+type NuShell struct{}
+type XonshShell struct{}
 
 // validateBinPath ensures the binary path is safe and exists
 func validateBinPath(binPath string) error {
@@ -118,12 +126,59 @@ func escapePowerShellPath(path string) string {
 	return replacer.Replace(path)
 }
 
+// escapeNuPath properly escapes a path for use in a Nushell double-quoted string
+func escapeNuPath(path string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+	)
+	return replacer.Replace(path)
+}
+
+// escapeXonshPath properly escapes a path for use in a Xonsh (Python) double-quoted string
+func escapeXonshPath(path string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(path)
+}
+
+// govmanExecutable returns the path of the govman binary inside binPath
+func govmanExecutable(binPath string) string {
+	if currentGOOS == "windows" {
+		return filepath.Join(binPath, "govman.exe")
+	}
+	return filepath.Join(binPath, "govman")
+}
+
 // escapeCmdPath properly escapes a path for use in cmd
 func escapeCmdPath(path string) string {
 	// CMD uses % for variables
 	return strings.ReplaceAll(path, "%", "%%")
 }
 
+// CompletionFile returns where govman init installs the completion script for shell,
+// next to the bin directory binPath. Returns an empty string if the shell has no completion support.
+func CompletionFile(shell Shell, binPath string) string {
+	var name string
+	switch shell.Name() {
+	case "bash":
+		name = "govman.bash"
+	case "zsh":
+		name = "_govman"
+	case "fish":
+		name = "govman.fish"
+	case "powershell":
+		name = "govman.ps1"
+	default:
+		return ""
+	}
+
+	return filepath.Join(filepath.Dir(binPath), "completions", name)
+}
+
 // Detect determines the user's shell based on OS and environment variables,
 // falling back to an available default when detection is inconclusive.
 func Detect() Shell {
@@ -147,6 +202,10 @@ func Detect() Shell {
 	}
 
 	shellName := filepath.Base(shellPath)
+	if shell := LookupTemplate(shellName); shell != nil && shell.IsAvailable() {
+		return shell
+	}
+
 	switch shellName {
 	case "zsh":
 		if isCommandAvailable("zsh") {
@@ -160,13 +219,21 @@ func Detect() Shell {
 		if isCommandAvailable("bash") {
 			return &BashShell{}
 		}
+	case "nu":
+		if isCommandAvailable("nu") {
+			return &NuShell{}
+		}
+	case "xonsh":
+		if isCommandAvailable("xonsh") {
+			return &XonshShell{}
+		}
 	}
 
 	// If the detected shell isn't available, find an alternative
 	return detectAvailableShell()
 }
 
-// DetectAll returns a slice of supported shells that are available on the current system.
+// DetectAll returns a slice of supported shells, including registered templates, that are available on the current system.
 func DetectAll() []Shell {
 	var shells []Shell
 
@@ -175,6 +242,8 @@ func DetectAll() []Shell {
 		shells = []Shell{
 			&PowerShell{},
 			&CmdShell{},
+			&NuShell{},
+			&XonshShell{},
 		}
 	} else {
 		// Unix-like shells
@@ -182,11 +251,13 @@ func DetectAll() []Shell {
 			&ZshShell{},
 			&BashShell{},
 			&FishShell{},
+			&NuShell{},
+			&XonshShell{},
 		}
 	}
 
 	var available []Shell
-	for _, shell := range shells {
+	for _, shell := range withTemplates(shells) {
 		if shell.IsAvailable() {
 			available = append(available, shell)
 		}
@@ -201,6 +272,8 @@ func detectAvailableShell() Shell {
 		&BashShell{},
 		&ZshShell{},
 		&FishShell{},
+		&NuShell{},
+		&XonshShell{},
 	}
 
 	for _, shell := range shells {
@@ -274,6 +347,7 @@ func (s *BashShell) PathCommand(path string) string {
 // SetupCommands returns the Bash shell configuration lines to integrate govman.
 func (s *BashShell) SetupCommands(binPath string) []string {
 	escapedPath := escapeBashPath(binPath)
+	completionFile := escapeBashPath(CompletionFile(s, binPath))
 
 	commands := []string{
 		"# GOVMAN - Go Version Manager",
@@ -291,68 +365,36 @@ func (s *BashShell) SetupCommands(binPath string) []string {
 		"        local output",
 		`        output="$("$govman_bin" "$@" 2>&1)"`,
 		"        local exit_code=$?",
-		"        if [[ $exit_code -eq 0 ]]; then",
-		`            local export_cmd=$(echo "$output" | grep -E '^export PATH=')`,
-		`            if [[ -n "$export_cmd" ]]; then`,
-		`                eval "$export_cmd"`,
-		`                echo "✓ Go version switched successfully"`,
-		"                return 0",
-		"            fi",
-		"        else",
+		"        if [[ $exit_code -ne 0 ]]; then",
 		`            echo "$output" >&2`,
 		"            return $exit_code",
 		"        fi",
+		"        local arg version",
+		`        for arg in "${@:2}"; do`,
+		`            if [[ "$arg" == "-" || "$arg" != -* ]]; then version="$arg"; break; fi`,
+		"        done",
+		"        local env_output",
+		`        env_output="$("$govman_bin" env "$version" --shell bash)" || return $?`,
+		`        eval "$env_output"`,
+		`        if [[ -n "$output" ]]; then echo "$output"; fi`,
+		"        return 0",
 		"    fi",
 		`    "$govman_bin" "$@"`,
 		"}",
 		"",
-		"# Auto-switch Go versions based on .govman-version file",
-		"govman_auto_switch() {",
-		"    # Check if auto-switch is enabled in config",
-		`    local config_file="$HOME/.govman/config.yaml"`,
-		`    if [[ -f "$config_file" ]]; then`,
-		`        local auto_switch_enabled=$(grep -E '^auto_switch:' -A 10 "$config_file" 2>/dev/null | grep -E '^[[:space:]]*enabled:' | head -1 | awk '{print $2}' | tr -d '[:space:]')`,
-		`        if [[ "$auto_switch_enabled" != "true" ]]; then`,
-		"            return 0",
-		"        fi",
-		"    fi",
-		"",
-		"    if [[ -f .govman-version ]]; then",
-		`        local required_version=$(cat .govman-version 2>/dev/null | tr -d '\n\r' | sed 's/^[[:space:]]*//;s/[[:space:]]*$//')`,
-		`        if [[ -n "$required_version" ]]; then`,
-		"            if ! command -v go >/dev/null 2>&1; then",
-		`                echo "Go not found. Switching to Go $required_version..."`,
-		`                govman use "$required_version" >/dev/null 2>&1 || {`,
-		`                    echo "Warning: Failed to switch to Go $required_version. Install it with 'govman install $required_version'" >&2`,
-		"                }",
-		"                return",
-		"            fi",
-		"",
-		`            local current_version=$(go version 2>/dev/null | awk '{print $3}' | sed 's/go//')`,
-		`            if [[ "$current_version" != "$required_version" ]]; then`,
-		`                echo "Auto-switching to Go $required_version (required by .govman-version)"`,
-		`                govman use "$required_version" >/dev/null 2>&1 || {`,
-		`                    echo "Warning: Failed to switch to Go $required_version. Install it with 'govman install $required_version'" >&2`,
-		"                }",
-		"            fi",
-		"        fi",
-		"    fi",
-		"}",
+		"# Shell completions (installed by 'govman init')",
+		fmt.Sprintf(`if [[ -f "%s" ]]; then source "%s"; fi`, completionFile, completionFile),
 		"",
-		"# Bash-specific: Hook into PROMPT_COMMAND for directory changes",
-		`__govman_prev_pwd="$PWD"`,
-		"__govman_check_dir_change() {",
-		`    if [[ "$PWD" != "$__govman_prev_pwd" ]]; then`,
-		`        __govman_prev_pwd="$PWD"`,
-		"        govman_auto_switch",
-		"    fi",
+		"# Auto-switch Go versions based on the project version file",
+		"govman_auto_switch() {",
+		"    local exit_status=$?",
+		fmt.Sprintf(`    eval "$("%s/govman" hook-env --shell bash)"`, escapedPath),
+		"    return $exit_status",
 		"}",
 		"",
-		"# Add to PROMPT_COMMAND (preserves existing commands)",
-		`if [[ -z "$PROMPT_COMMAND" ]]; then`,
-		`    PROMPT_COMMAND="__govman_check_dir_change"`,
-		"else",
-		`    PROMPT_COMMAND="__govman_check_dir_change;$PROMPT_COMMAND"`,
+		"# Bash-specific: Run the hook before each prompt (preserves existing commands)",
+		`if [[ ";${PROMPT_COMMAND:-};" != *";govman_auto_switch;"* ]]; then`,
+		`    PROMPT_COMMAND="govman_auto_switch${PROMPT_COMMAND:+;$PROMPT_COMMAND}"`,
 		"fi",
 		"",
 		"# Run auto-switch on shell startup",
@@ -414,6 +456,7 @@ func (s *ZshShell) PathCommand(path string) string {
 // SetupCommands returns the Zsh configuration lines to integrate govman.
 func (s *ZshShell) SetupCommands(binPath string) []string {
 	escapedPath := escapeBashPath(binPath)
+	completionFile := escapeBashPath(CompletionFile(s, binPath))
 
 	commands := []string{
 		"# GOVMAN - Go Version Manager",
@@ -431,57 +474,34 @@ func (s *ZshShell) SetupCommands(binPath string) []string {
 		"        local output",
 		`        output="$("$govman_bin" "$@" 2>&1)"`,
 		"        local exit_code=$?",
-		"        if [[ $exit_code -eq 0 ]]; then",
-		`            local export_cmd=$(echo "$output" | grep -E '^export PATH=')`,
-		`            if [[ -n "$export_cmd" ]]; then`,
-		`                eval "$export_cmd"`,
-		`                echo "✓ Go version switched successfully"`,
-		"                return 0",
-		"            fi",
-		"        else",
+		"        if [[ $exit_code -ne 0 ]]; then",
 		`            echo "$output" >&2`,
 		"            return $exit_code",
 		"        fi",
+		"        local arg version",
+		`        for arg in "${@:2}"; do`,
+		`            if [[ "$arg" == "-" || "$arg" != -* ]]; then version="$arg"; break; fi`,
+		"        done",
+		"        local env_output",
+		`        env_output="$("$govman_bin" env "$version" --shell zsh)" || return $?`,
+		`        eval "$env_output"`,
+		`        if [[ -n "$output" ]]; then echo "$output"; fi`,
+		"        return 0",
 		"    fi",
 		`    "$govman_bin" "$@"`,
 		"}",
 		"",
-		"# Auto-switch Go versions based on .govman-version file",
-		"govman_auto_switch() {",
-		"    # Check if auto-switch is enabled in config",
-		`    local config_file="$HOME/.govman/config.yaml"`,
-		`    if [[ -f "$config_file" ]]; then`,
-		`        local auto_switch_enabled=$(grep -E '^auto_switch:' -A 10 "$config_file" 2>/dev/null | grep -E '^[[:space:]]*enabled:' | head -1 | awk '{print $2}' | tr -d '[:space:]')`,
-		`        if [[ "$auto_switch_enabled" != "true" ]]; then`,
-		"            return 0",
-		"        fi",
-		"    fi",
+		"# Shell completions (installed by 'govman init', requires compinit)",
+		fmt.Sprintf(`if [[ -f "%s" ]] && (( $+functions[compdef] )); then source "%s"; fi`, completionFile, completionFile),
 		"",
-		"    if [[ -f .govman-version ]]; then",
-		`        local required_version=$(cat .govman-version 2>/dev/null | tr -d '\n\r' | sed 's/^[[:space:]]*//;s/[[:space:]]*$//')`,
-		`        if [[ -n "$required_version" ]]; then`,
-		"            if ! command -v go >/dev/null 2>&1; then",
-		`                echo "Go not found. Switching to Go $required_version..."`,
-		`                govman use "$required_version" >/dev/null 2>&1 || {`,
-		`                    echo "Warning: Failed to switch to Go $required_version. Install it with 'govman install $required_version'" >&2`,
-		"                }",
-		"                return",
-		"            fi",
-		"",
-		`            local current_version=$(go version 2>/dev/null | awk '{print $3}' | sed 's/go//')`,
-		`            if [[ "$current_version" != "$required_version" ]]; then`,
-		`                echo "Auto-switching to Go $required_version (required by .govman-version)"`,
-		`                govman use "$required_version" >/dev/null 2>&1 || {`,
-		`                    echo "Warning: Failed to switch to Go $required_version. Install it with 'govman install $required_version'" >&2`,
-		"                }",
-		"            fi",
-		"        fi",
-		"    fi",
+		"# Auto-switch Go versions based on the project version file",
+		"govman_auto_switch() {",
+		fmt.Sprintf(`    eval "$("%s/govman" hook-env --shell zsh)"`, escapedPath),
 		"}",
 		"",
-		"# Zsh-specific: Hook into chpwd for directory changes",
+		"# Zsh-specific: Run the hook before each prompt",
 		"autoload -U add-zsh-hook",
-		"add-zsh-hook chpwd govman_auto_switch",
+		"add-zsh-hook precmd govman_auto_switch",
 		"",
 		"# Run auto-switch on shell startup",
 		"govman_auto_switch",
@@ -539,6 +559,7 @@ func (s *FishShell) PathCommand(path string) string {
 // SetupCommands returns the Fish configuration lines to integrate govman.
 func (s *FishShell) SetupCommands(binPath string) []string {
 	escapedPath := escapeFishPath(binPath)
+	completionFile := escapeFishPath(CompletionFile(s, binPath))
 
 	commands := []string{
 		"# GOVMAN - Go Version Manager",
@@ -556,59 +577,34 @@ func (s *FishShell) SetupCommands(binPath string) []string {
 		`    if test "$argv[1]" = "use"; and test (count $argv) -ge 2; and test "$argv[2]" != "--help"; and test "$argv[2]" != "-h"`,
 		"        set output ($govman_bin $argv 2>&1)",
 		"        set exit_code $status",
-		"        if test $exit_code -eq 0",
-		"            for line in $output",
-		"                if string match -qr '^fish_add_path' -- $line",
-		"                    eval $line",
-		`                    echo "✓ Go version switched successfully"`,
-		"                    return 0",
-		"                end",
-		"            end",
-		"        else",
+		"        if test $exit_code -ne 0",
 		"            for line in $output",
 		"                echo $line >&2",
 		"            end",
 		"            return $exit_code",
 		"        end",
-		"    end",
-		"    $govman_bin $argv",
-		"end",
-		"",
-		"# Auto-switch Go versions based on .govman-version file",
-		"function govman_auto_switch",
-		`    set config_file "$HOME/.govman/config.yaml"`,
-		`    if test -f "$config_file"`,
-		`        set auto_switch_enabled (grep -E '^auto_switch:' -A 10 "$config_file" 2>/dev/null | grep -E '^[[:space:]]*enabled:' | head -1 | awk '{print $2}' | tr -d '[:space:]')`,
-		`        if test "$auto_switch_enabled" != "true"`,
-		"            return 0",
-		"        end",
-		"    end",
-		"",
-		"    if test -f .govman-version",
-		"        set required_version (string trim < .govman-version)",
-		`        if test -n "$required_version"`,
-		"            if not command -v go >/dev/null 2>&1",
-		`                echo "Go not found. Switching to Go $required_version..."`,
-		`                govman use "$required_version" >/dev/null 2>&1; or begin`,
-		`                    echo "Warning: Failed to switch to Go $required_version. Install it with 'govman install $required_version'" >&2`,
-		"                end",
-		"                return",
-		"            end",
-		"",
-		"            set current_version (go version 2>/dev/null | awk '{print $3}' | sed 's/go//')",
-		`            if test "$current_version" != "$required_version"`,
-		`                echo "Auto-switching to Go $required_version (required by .govman-version)"`,
-		`                govman use "$required_version" >/dev/null 2>&1; or begin`,
-		`                    echo "Warning: Failed to switch to Go $required_version. Install it with 'govman install $required_version'" >&2`,
-		"                end",
+		"        set version",
+		"        for arg in $argv[2..-1]",
+		"            if test \"$arg\" = -; or not string match -q -- '-*' $arg",
+		"                set version $arg",
+		"                break",
 		"            end",
 		"        end",
+		`        $govman_bin env "$version" --shell fish | source; or return $status`,
+		"        for line in $output",
+		"            echo $line",
+		"        end",
+		"        return 0",
 		"    end",
+		"    $govman_bin $argv",
 		"end",
 		"",
-		"# Fish-specific: Hook into directory changes",
-		"function __govman_cd_hook --on-variable PWD",
-		"    govman_auto_switch",
+		"# Shell completions (installed by 'govman init')",
+		fmt.Sprintf(`if test -f "%s"; source "%s"; end`, completionFile, completionFile),
+		"",
+		"# Auto-switch Go versions based on the project version file",
+		"function govman_auto_switch --on-event fish_prompt",
+		fmt.Sprintf(`    "%s/govman" hook-env --shell fish | source`, escapedPath),
 		"end",
 		"",
 		"# Run auto-switch on shell startup",
@@ -676,6 +672,7 @@ func (s *PowerShell) PathCommand(path string) string {
 // SetupCommands returns the PowerShell profile lines to integrate govman.
 func (s *PowerShell) SetupCommands(binPath string) []string {
 	escapedPath := escapePowerShellPath(binPath)
+	completionFile := escapePowerShellPath(CompletionFile(s, binPath))
 
 	commands := []string{
 		"# GOVMAN - Go Version Manager",
@@ -693,96 +690,43 @@ func (s *PowerShell) SetupCommands(binPath string) []string {
 		"    if ($args.Count -ge 2 -and $args[0] -eq 'use' -and $args[1] -ne '--help' -and $args[1] -ne '-h') {",
 		"        try {",
 		"            $output = & $govman_bin @args 2>&1",
-		"            if ($LASTEXITCODE -eq 0) {",
-		"                $pathCmd = $output | Where-Object { $_ -match '^\\$env:PATH = ' }",
-		"                if ($pathCmd) {",
-		"                    Invoke-Expression $pathCmd",
-		"                    Write-Host '✓ Go version switched successfully' -ForegroundColor Green",
-		"                    return",
-		"                }",
-		"            } else {",
+		"            if ($LASTEXITCODE -ne 0) {",
 		"                $output | ForEach-Object { Write-Error $_ }",
 		"                return",
 		"            }",
-		"        } catch {",
-		"            Write-Error $_.Exception.Message",
-		"            return",
-		"        }",
-		"    }",
-		"    & $govman_bin @args",
-		"}",
-		"",
-		"# Auto-switch Go versions based on .govman-version file",
-		"function Invoke-GovmanAutoSwitch {",
-		"    $configFile = \"$env:USERPROFILE\\.govman\\config.yaml\"",
-		"    if (Test-Path $configFile) {",
-		"        try {",
-		"            $autoSwitchEnabled = $false",
-		"            $content = Get-Content $configFile -Raw -ErrorAction Stop",
-		"            if ($content -match '(?ms)auto_switch:.*?enabled:\\s*(true|false)') {",
-		"                $autoSwitchEnabled = ($matches[1] -eq 'true')",
-		"            }",
-		"            if (-not $autoSwitchEnabled) {",
-		"                return",
+		"            $version = $args[1..($args.Count - 1)] | Where-Object { $_ -eq '-' -or $_ -notlike '-*' } | Select-Object -First 1",
+		"            $envCmd = & $govman_bin env $version --shell powershell",
+		"            if ($LASTEXITCODE -eq 0 -and $envCmd) {",
+		"                Invoke-Expression ($envCmd -join \"`n\")",
+		"                $output | ForEach-Object { Write-Host $_ }",
 		"            }",
-		"        } catch {",
 		"            return",
-		"        }",
-		"    }",
-		"",
-		"    if (Test-Path .govman-version) {",
-		"        try {",
-		"            $requiredVersion = (Get-Content .govman-version -Raw -ErrorAction Stop).Trim()",
 		"        } catch {",
+		"            Write-Error $_.Exception.Message",
 		"            return",
 		"        }",
-		"",
-		"        if ($requiredVersion) {",
-		"            $currentVersion = $null",
-		"            try {",
-		"                $goVersionOutput = go version 2>$null",
-		"                if ($LASTEXITCODE -eq 0 -and $goVersionOutput) {",
-		"                    if ($goVersionOutput -match 'go version go([\\d\\.]+)') {",
-		"                        $currentVersion = $matches[1]",
-		"                    }",
-		"                }",
-		"            } catch {}",
-		"",
-		"            if (-not $currentVersion) {",
-		"                Write-Host \"Go not found. Switching to Go $requiredVersion...\" -ForegroundColor Yellow",
-		"                govman use $requiredVersion *>$null",
-		"                if ($LASTEXITCODE -ne 0) {",
-		"                    Write-Warning \"Failed to switch to Go $requiredVersion. Install it with 'govman install $requiredVersion'\"",
-		"                }",
-		"                return",
-		"            }",
-		"",
-		"            if ($currentVersion -ne $requiredVersion) {",
-		"                Write-Host \"Auto-switching to Go $requiredVersion (required by .govman-version)\" -ForegroundColor Yellow",
-		"                govman use $requiredVersion *>$null",
-		"                if ($LASTEXITCODE -ne 0) {",
-		"                    Write-Warning \"Failed to switch to Go $requiredVersion. Install it with 'govman install $requiredVersion'\"",
-		"                }",
-		"            }",
-		"        }",
 		"    }",
+		"    & $govman_bin @args",
 		"}",
 		"",
-		"# PowerShell-specific: Hook into location changes",
-		"$Global:GovmanPreviousLocation = $PWD.Path",
+		"# Shell completions (installed by 'govman init')",
+		fmt.Sprintf(`if (Test-Path "%s") { . "%s" }`, completionFile, completionFile),
 		"",
-		"function Global:Invoke-GovmanLocationCheck {",
-		"    if ($PWD.Path -ne $Global:GovmanPreviousLocation) {",
-		"        $Global:GovmanPreviousLocation = $PWD.Path",
-		"        Invoke-GovmanAutoSwitch",
+		"# Auto-switch Go versions based on the project version file",
+		"function Invoke-GovmanAutoSwitch {",
+		"    $exitCode = $Global:LASTEXITCODE",
+		fmt.Sprintf(`    $hookEnv = & "%s\govman.exe" hook-env --shell powershell`, escapedPath),
+		"    if ($hookEnv) {",
+		"        Invoke-Expression ($hookEnv -join \"`n\")",
 		"    }",
+		"    $Global:LASTEXITCODE = $exitCode",
 		"}",
 		"",
-		"# Hook into prompt for auto-switching",
+		"# PowerShell-specific: Hook into prompt for auto-switching",
 		"if (Get-Command prompt -ErrorAction SilentlyContinue) {",
 		"    $Global:GovmanOriginalPrompt = $function:prompt",
 		"    function global:prompt {",
-		"        Invoke-GovmanLocationCheck",
+		"        Invoke-GovmanAutoSwitch",
 		"        if ($Global:GovmanOriginalPrompt) {",
 		"            & $Global:GovmanOriginalPrompt",
 		"        } else {",
@@ -887,52 +831,345 @@ func (s *CmdShell) ExecutePathCommand(path string) error {
 	return nil
 }
 
+// Name returns the identifier for Nushell.
+func (s *NuShell) Name() string {
+	return "nu"
+}
+
+// DisplayName returns the human-friendly name for Nushell.
+func (s *NuShell) DisplayName() string {
+	return "Nushell"
+}
+
+// IsAvailable reports whether Nushell is present in the system PATH.
+func (s *NuShell) IsAvailable() bool {
+	return isCommandAvailable("nu")
+}
+
+// ConfigFile returns the path to the Nushell config.nu file.
+func (s *NuShell) ConfigFile() string {
+	home, err := userHomeDir()
+	if err != nil {
+		return "config.nu"
+	}
+
+	candidates := nuConfigCandidates(home)
+	for _, candidate := range candidates {
+		if fileExists(candidate) {
+			return candidate
+		}
+	}
+
+	// Default to Nushell's own default location for this OS
+	return candidates[len(candidates)-1]
+}
+
+// nuConfigCandidates lists the places Nushell looks for config.nu, most specific first.
+func nuConfigCandidates(home string) []string {
+	var candidates []string
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		candidates = append(candidates, filepath.Join(xdg, "nushell", "config.nu"))
+	}
+	candidates = append(candidates, filepath.Join(home, ".config", "nushell", "config.nu"))
+
+	switch currentGOOS {
+	case "darwin":
+		candidates = append(candidates, filepath.Join(home, "Library", "Application Support", "nushell", "config.nu"))
+	case "windows":
+		candidates = append(candidates, filepath.Join(home, "AppData", "Roaming", "nushell", "config.nu"))
+	}
+
+	return candidates
+}
+
+// PathCommand returns a Nushell command to prepend binPath to PATH.
+func (s *NuShell) PathCommand(path string) string {
+	escapedPath := escapeNuPath(path)
+	return fmt.Sprintf(`$env.PATH = ($env.PATH | split row (char esep) | prepend "%s")`, escapedPath)
+}
+
+// SetupCommands returns the Nushell configuration lines to integrate govman.
+// Nushell cannot evaluate generated code, so the wrapper and hook load govman's JSON output with load-env.
+func (s *NuShell) SetupCommands(binPath string) []string {
+	escapedPath := escapeNuPath(binPath)
+	govmanBin := escapeNuPath(govmanExecutable(binPath))
+
+	commands := []string{
+		"# GOVMAN - Go Version Manager",
+		fmt.Sprintf(`$env.PATH = ($env.PATH | split row (char esep) | prepend "%s")`, escapedPath),
+		`$env.GOTOOLCHAIN = "local"`,
+		"",
+		"# Ensure GOBIN and GOPATH/bin are available",
+		`$env.PATH = ($env.PATH | prepend (if ($env.GOBIN? | is-empty) { [] } else { [$env.GOBIN] }))`,
+		`$env.PATH = ($env.PATH | prepend (if (which go | is-empty) { [] } else { [(^go env GOPATH | str trim | path join "bin")] }))`,
+		`$env.PATH = ($env.PATH | prepend ($nu.home-path | path join "go" "bin"))`,
+		"",
+		"# Apply environment changes printed by 'govman env' or 'govman hook-env'",
+		"def --env __govman_load_env [output: string] {",
+		`    let changes = (if ($output | str trim | is-empty) { {} } else { $output | from json })`,
+		"    let entries = ($changes | transpose name value)",
+		"    let unset = ($entries | where value == null | get name)",
+		"    if not ($unset | is-empty) { hide-env --ignore-errors ...$unset }",
+		"    load-env ($entries | where value != null | reduce --fold {} {|it, acc| $acc | upsert $it.name $it.value })",
+		"    $env.PATH = ($env.PATH | split row (char esep))",
+		"}",
+		"",
+		"# Wrapper function for automatic PATH execution",
+		"def --env --wrapped govman [...args] {",
+		fmt.Sprintf(`    let govman_bin = "%s"`, govmanBin),
+		`    if ($args | length) >= 2 and $args.0 == "use" and not ($args.1 in ["--help", "-h"]) {`,
+		"        let result = (do { ^$govman_bin ...$args } | complete)",
+		"        if $result.exit_code != 0 {",
+		"            print --stderr ($result.stdout + $result.stderr)",
+		"            return",
+		"        }",
+		`        let versions = ($args | skip 1 | where {|arg| $arg == "-" or not ($arg | str starts-with "-")})`,
+		`        let version = (if ($versions | is-empty) { "" } else { $versions | first })`,
+		"        __govman_load_env (^$govman_bin env $version --shell nu)",
+		`        if not ($result.stdout | is-empty) { print --no-newline $result.stdout }`,
+		`        if not ($result.stderr | is-empty) { print --no-newline --stderr $result.stderr }`,
+		"        return",
+		"    }",
+		"    ^$govman_bin ...$args",
+		"}",
+		"",
+		"# Auto-switch Go versions based on the project version file",
+		"$env.config = ($env.config | upsert hooks.pre_prompt (($env.config.hooks?.pre_prompt? | default []) | append {||",
+		fmt.Sprintf(`    __govman_load_env (^"%s" hook-env --shell nu)`, govmanBin),
+		"}))",
+		"# END GOVMAN",
+	}
+
+	return commands
+}
+
+// ExecutePathCommand outputs the PATH command for Nushell.
+func (s *NuShell) ExecutePathCommand(path string) error {
+	if err := validateBinPath(path); err != nil {
+		return err
+	}
+
+	pathCmd := s.PathCommand(path)
+	fmt.Println(pathCmd)
+
+	fmt.Fprintf(os.Stderr, "# To apply to current session, use the govman wrapper installed by 'govman init':\n")
+	fmt.Fprintf(os.Stderr, "# govman use <version>\n")
+
+	return nil
+}
+
+// Name returns the identifier for Xonsh.
+func (s *XonshShell) Name() string {
+	return "xonsh"
+}
+
+// DisplayName returns the human-friendly name for Xonsh.
+func (s *XonshShell) DisplayName() string {
+	return "Xonsh"
+}
+
+// IsAvailable reports whether Xonsh is present in the system PATH.
+func (s *XonshShell) IsAvailable() bool {
+	return isCommandAvailable("xonsh")
+}
+
+// ConfigFile returns the path to the Xonsh run control file.
+func (s *XonshShell) ConfigFile() string {
+	home, err := userHomeDir()
+	if err != nil {
+		return ".xonshrc"
+	}
+
+	candidates := xonshConfigCandidates(home)
+	for _, candidate := range candidates {
+		if fileExists(candidate) {
+			return candidate
+		}
+	}
+
+	// Default to ~/.xonshrc if none exist
+	return candidates[0]
+}
+
+// xonshConfigCandidates lists the Xonsh run control files govman can configure.
+func xonshConfigCandidates(home string) []string {
+	configHome := filepath.Join(home, ".config")
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		configHome = xdg
+	}
+
+	return []string{
+		filepath.Join(home, ".xonshrc"),
+		filepath.Join(configHome, "xonsh", "rc.xsh"),
+	}
+}
+
+// PathCommand returns a Xonsh command to prepend binPath to PATH.
+func (s *XonshShell) PathCommand(path string) string {
+	escapedPath := escapeXonshPath(path)
+	return fmt.Sprintf(`$PATH.insert(0, "%s")`, escapedPath)
+}
+
+// SetupCommands returns the Xonsh run control lines to integrate govman.
+func (s *XonshShell) SetupCommands(binPath string) []string {
+	escapedPath := escapeXonshPath(binPath)
+	govmanBin := escapeXonshPath(govmanExecutable(binPath))
+
+	commands := []string{
+		"# GOVMAN - Go Version Manager",
+		fmt.Sprintf(`$PATH.insert(0, "%s")`, escapedPath),
+		`$GOTOOLCHAIN = "local"`,
+		"",
+		"# Ensure GOBIN and GOPATH/bin are available",
+		"import shutil as __govman_shutil",
+		"import sys as __govman_sys",
+		`if ${...}.get("GOBIN"):`,
+		"    $PATH.insert(0, $GOBIN)",
+		`if __govman_shutil.which("go"):`,
+		`    $PATH.insert(0, $(go env GOPATH).strip() + "/bin")`,
+		`$PATH.insert(0, $HOME + "/go/bin")`,
+		"",
+		"# Wrapper function for automatic PATH execution",
+		"def __govman_wrapper(args):",
+		fmt.Sprintf(`    govman_bin = "%s"`, govmanBin),
+		`    if len(args) >= 2 and args[0] == "use" and args[1] not in ("--help", "-h"):`,
+		"        result = !(@(govman_bin) @(args) 2>&1)",
+		"        if result.returncode != 0:",
+		`            print(result.output, end="", file=__govman_sys.stderr)`,
+		"            return result.returncode",
+		`        version = next((arg for arg in args[1:] if arg == "-" or not arg.startswith("-")), "")`,
+		"        execx($(@(govman_bin) env @(version) --shell xonsh))",
+		`        print(result.output, end="")`,
+		"        return 0",
+		"    return ![@(govman_bin) @(args)].returncode",
+		"",
+		`aliases["govman"] = __govman_wrapper`,
+		"",
+		"# Auto-switch Go versions based on the project version file",
+		"@events.on_pre_prompt",
+		"def govman_auto_switch(**kwargs):",
+		fmt.Sprintf(`    output = $("%s" hook-env --shell xonsh)`, govmanBin),
+		"    if output.strip():",
+		"        execx(output)",
+		"",
+		"# Run auto-switch on shell startup",
+		"govman_auto_switch()",
+		"# END GOVMAN",
+	}
+
+	return commands
+}
+
+// ExecutePathCommand outputs the PATH command for Xonsh.
+func (s *XonshShell) ExecutePathCommand(path string) error {
+	if err := validateBinPath(path); err != nil {
+		return err
+	}
+
+	pathCmd := s.PathCommand(path)
+	fmt.Println(pathCmd)
+
+	fmt.Fprintf(os.Stderr, "# To apply to current session, run:\n")
+	fmt.Fprintf(os.Stderr, "# execx($(govman use <version>))\n")
+
+	return nil
+}
+
+// Options controls how init and deinit edit shell configuration files.
+type Options struct {
+	Force  bool      // Replace an existing govman configuration
+	DryRun bool      // Print a unified diff instead of changing files
+	Output io.Writer // Destination for dry-run diffs (defaults to stdout)
+
+	LoginShells bool // Also configure login-shell files such as .profile and .zprofile
+}
+
+// FileChange describes a file changed by init or deinit, or that would be changed with Options.DryRun.
+type FileChange struct {
+	Path   string
+	Action string // "created", "updated", "unchanged" or "removed"
+	Backup string // Copy of the previous content, if one was made
+}
+
+// output returns the writer dry-run diffs are printed to.
+func (o Options) output() io.Writer {
+	if o.Output == nil {
+		return os.Stdout
+	}
+	return o.Output
+}
+
 // InitializeShell sets up shell integration for govman.
-func InitializeShell(shell Shell, binPath string, force bool) error {
+// Returns the files changed, including login-shell files when opts.LoginShells is set.
+func InitializeShell(shell Shell, binPath string, opts Options) ([]FileChange, error) {
 	// Validate the binary path first
 	if err := validateBinPath(binPath); err != nil {
-		return fmt.Errorf("invalid binary path: %w", err)
+		return nil, fmt.Errorf("invalid binary path: %w", err)
 	}
 
+	var change FileChange
+	var err error
 	switch shell.Name() {
 	case "powershell":
-		return initializePowerShell(shell, binPath, force)
+		change, err = initializePowerShell(shell, binPath, opts)
 	case "cmd":
-		return initializeCmdShell(shell, binPath, force)
+		change, err = initializeCmdShell(shell, binPath, opts)
 	default:
-		return initializeUnixShell(shell, binPath, force)
+		change, err = initializeUnixShell(shell, binPath, opts)
+	}
+	if errors.Is(err, ErrAlreadyConfigured) && opts.LoginShells {
+		// Still bring the login-shell files up to date
+		change = FileChange{Path: shell.ConfigFile(), Action: "unchanged"}
+	} else if err != nil {
+		return nil, err
+	}
+
+	changes := []FileChange{change}
+	if opts.LoginShells {
+		loginChanges, err := initializeLoginFiles(shell, binPath, opts)
+		changes = append(changes, loginChanges...)
+		if err != nil {
+			return changes, err
+		}
 	}
+
+	return changes, nil
 }
 
 // initializeUnixShell writes govman integration to the shell config file.
-func initializeUnixShell(shell Shell, binPath string, force bool) error {
+func initializeUnixShell(shell Shell, binPath string, opts Options) (FileChange, error) {
 	configFile := shell.ConfigFile()
 
-	// Create config directory if needed
 	configDir := filepath.Dir(configFile)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory %s: %w", configDir, err)
-	}
+	if !opts.DryRun {
+		// Create config directory if needed
+		if err := os.MkdirAll(configDir, 0755); err != nil {
+			return FileChange{}, fmt.Errorf("failed to create config directory %s: %w", configDir, err)
+		}
 
-	// Verify we can write to the directory
-	testFile := filepath.Join(configDir, ".govman_test")
-	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
-		return fmt.Errorf("insufficient permissions to write to %s: %w", configDir, err)
+		// Verify we can write to the directory
+		testFile := filepath.Join(configDir, ".govman_test")
+		if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
+			return FileChange{}, fmt.Errorf("insufficient permissions to write to %s: %w", configDir, err)
+		}
+		os.Remove(testFile)
 	}
-	os.Remove(testFile)
 
 	// Read existing content
 	var existingContent string
+	exists := false
 	if content, err := os.ReadFile(configFile); err == nil {
 		existingContent = string(content)
+		exists = true
 	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to read config file: %w", err)
+		return FileChange{}, fmt.Errorf("failed to read config file: %w", err)
 	}
+	originalContent := existingContent
 
 	// Check if govman is already configured
 	if containsGovmanConfig(existingContent) {
-		if !force {
-			return fmt.Errorf("govman is already configured in %s (use --force to override)", configFile)
+		if !opts.Force {
+			return FileChange{}, fmt.Errorf("%w in %s (use --force to override)", ErrAlreadyConfigured, configFile)
 		}
 		existingContent = removeExistingConfig(existingContent)
 	}
@@ -945,46 +1182,58 @@ func initializeUnixShell(shell Shell, binPath string, force bool) error {
 	finalContent := strings.TrimSpace(existingContent) + newConfig
 
 	// Write to file with proper permissions
-	if err := os.WriteFile(configFile, []byte(finalContent), 0644); err != nil {
-		return fmt.Errorf("failed to write config to %s: %w", configFile, err)
+	change, err := updateFile(configFile, originalContent, finalContent, exists, opts)
+	if err != nil {
+		return FileChange{}, fmt.Errorf("failed to write config to %s: %w", configFile, err)
+	}
+	if opts.DryRun {
+		return change, nil
 	}
 
 	fmt.Printf("✅ Successfully configured %s\n", shell.DisplayName())
 	fmt.Printf("📝 Configuration added to: %s\n", configFile)
+	if change.Backup != "" {
+		fmt.Printf("💾 Backup saved to: %s\n", change.Backup)
+	}
 	fmt.Printf("🔄 Reload your shell or run: source %s\n", configFile)
 
-	return nil
+	return change, nil
 }
 
 // initializePowerShell writes configuration to PowerShell profile.
-func initializePowerShell(shell Shell, binPath string, force bool) error {
+func initializePowerShell(shell Shell, binPath string, opts Options) (FileChange, error) {
 	profilePath := shell.ConfigFile()
 
-	// Create profile directory if needed
 	profileDir := filepath.Dir(profilePath)
-	if err := os.MkdirAll(profileDir, 0755); err != nil {
-		return fmt.Errorf("failed to create profile directory: %w", err)
-	}
+	if !opts.DryRun {
+		// Create profile directory if needed
+		if err := os.MkdirAll(profileDir, 0755); err != nil {
+			return FileChange{}, fmt.Errorf("failed to create profile directory: %w", err)
+		}
 
-	// Verify write permissions
-	testFile := filepath.Join(profileDir, ".govman_test")
-	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
-		return fmt.Errorf("insufficient permissions to write to %s: %w", profileDir, err)
+		// Verify write permissions
+		testFile := filepath.Join(profileDir, ".govman_test")
+		if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
+			return FileChange{}, fmt.Errorf("insufficient permissions to write to %s: %w", profileDir, err)
+		}
+		os.Remove(testFile)
 	}
-	os.Remove(testFile)
 
 	// Read existing content
 	var existingContent string
+	exists := false
 	if content, err := os.ReadFile(profilePath); err == nil {
 		existingContent = string(content)
+		exists = true
 	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to read profile: %w", err)
+		return FileChange{}, fmt.Errorf("failed to read profile: %w", err)
 	}
+	originalContent := existingContent
 
 	// Check if govman is already configured
 	if containsGovmanConfig(existingContent) {
-		if !force {
-			return fmt.Errorf("govman is already configured in PowerShell profile (use --force to override)")
+		if !opts.Force {
+			return FileChange{}, fmt.Errorf("%w in PowerShell profile (use --force to override)", ErrAlreadyConfigured)
 		}
 		existingContent = removeExistingConfig(existingContent)
 	}
@@ -997,32 +1246,43 @@ func initializePowerShell(shell Shell, binPath string, force bool) error {
 	finalContent := strings.TrimSpace(existingContent) + newConfig
 
 	// Write to file
-	if err := os.WriteFile(profilePath, []byte(finalContent), 0644); err != nil {
-		return fmt.Errorf("failed to write PowerShell profile: %w", err)
+	change, err := updateFile(profilePath, originalContent, finalContent, exists, opts)
+	if err != nil {
+		return FileChange{}, fmt.Errorf("failed to write PowerShell profile: %w", err)
+	}
+	if opts.DryRun {
+		return change, nil
 	}
 
 	fmt.Printf("✅ Successfully configured PowerShell\n")
 	fmt.Printf("📝 Configuration added to: %s\n", profilePath)
+	if change.Backup != "" {
+		fmt.Printf("💾 Backup saved to: %s\n", change.Backup)
+	}
 	fmt.Printf("🔄 Reload PowerShell or run: . $PROFILE\n")
 
-	return nil
+	return change, nil
 }
 
 // initializeCmdShell creates a batch wrapper for Command Prompt.
-func initializeCmdShell(shell Shell, binPath string, force bool) error {
+func initializeCmdShell(shell Shell, binPath string, opts Options) (FileChange, error) {
 	wrapperPath := filepath.Join(binPath, "govman.bat")
 
 	// Check if wrapper exists
-	if !force && fileExists(wrapperPath) {
-		return fmt.Errorf("wrapper already exists at %s (use --force to override)", wrapperPath)
+	existing, readErr := os.ReadFile(wrapperPath)
+	exists := readErr == nil
+	if !opts.Force && exists {
+		return FileChange{}, fmt.Errorf("wrapper already exists at %s (use --force to override)", wrapperPath)
 	}
 
 	// Verify write permissions
-	testFile := filepath.Join(binPath, ".govman_test")
-	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
-		return fmt.Errorf("insufficient permissions to write to %s: %w", binPath, err)
+	if !opts.DryRun {
+		testFile := filepath.Join(binPath, ".govman_test")
+		if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
+			return FileChange{}, fmt.Errorf("insufficient permissions to write to %s: %w", binPath, err)
+		}
+		os.Remove(testFile)
 	}
-	os.Remove(testFile)
 
 	// Create wrapper content using template for better maintainability
 	tmpl := `@echo off
@@ -1037,58 +1297,49 @@ if not exist "%GOVMAN_BIN%" (
     exit /b 1
 )
 
-REM Handle 'use' command with special PATH updating logic
-if "%~1"=="use" (
-    if not "%~2"=="" (
-        if not "%~2"=="--help" (
-            if not "%~2"=="-h" (
-                REM Execute govman use and capture output
-                "%GOVMAN_BIN%" %* > "%TEMP%\govman_output.tmp" 2>&1
-                set GOVMAN_EXIT_CODE=!errorlevel!
-                
-                if !GOVMAN_EXIT_CODE! equ 0 (
-                    REM Look for PATH export command in output
-                    set "PATH_UPDATED="
-                    for /f "usebackq delims=" %%i in ("%TEMP%\govman_output.tmp") do (
-                        set "LINE=%%i"
-                        echo !LINE! | findstr /b /c:"set PATH=" >nul
-                        if !errorlevel! equ 0 (
-                            REM Execute the PATH update command
-                            %%i
-                            set "PATH_UPDATED=1"
-                        )
-                    )
-                    del "%TEMP%\govman_output.tmp" 2>nul
-                    if defined PATH_UPDATED (
-                        echo.
-                        echo ✓ Go version switched successfully
-                        echo.
-                        echo Note: This change only affects the current Command Prompt session.
-                        echo To verify, run: go version
-                    ) else (
-                        echo Warning: No PATH update found in govman output >&2
-                    )
-                    exit /b 0
-                ) else (
-                    REM Show error output
-                    type "%TEMP%\govman_output.tmp" >&2
-                    del "%TEMP%\govman_output.tmp" 2>nul
-                    exit /b !GOVMAN_EXIT_CODE!
-                )
-            )
-        )
-    )
-)
+REM Handle 'use': run it, then apply 'govman env' for the selected version
+if "%~1"=="use" if not "%~2"=="" if not "%~2"=="--help" if not "%~2"=="-h" goto :use
 
 REM For all other commands, just pass through
 "%GOVMAN_BIN%" %*
 exit /b %errorlevel%
+
+:use
+"%GOVMAN_BIN%" %* > "%TEMP%\govman_output.tmp" 2>&1
+set GOVMAN_EXIT_CODE=!errorlevel!
+if !GOVMAN_EXIT_CODE! neq 0 (
+    type "%TEMP%\govman_output.tmp" >&2
+    del "%TEMP%\govman_output.tmp" 2>nul
+    exit /b !GOVMAN_EXIT_CODE!
+)
+
+REM The version is the first argument after 'use' that is not a flag ('-' switches back)
+set "GOVMAN_ARG="
+for %%a in (%*) do (
+    if not defined GOVMAN_ARG if not "%%~a"=="use" (
+        set "ARG=%%~a"
+        if "!ARG!"=="-" (set "GOVMAN_ARG=-") else if not "!ARG:~0,1!"=="-" set "GOVMAN_ARG=!ARG!"
+    )
+)
+
+"%GOVMAN_BIN%" env !GOVMAN_ARG! --shell cmd > "%TEMP%\govman_env.tmp"
+if errorlevel 1 (
+    del "%TEMP%\govman_output.tmp" "%TEMP%\govman_env.tmp" 2>nul
+    exit /b 1
+)
+type "%TEMP%\govman_output.tmp"
+del "%TEMP%\govman_output.tmp" 2>nul
+
+REM Apply the changes after endlocal so they reach the calling session
+endlocal & for /f "usebackq delims=" %%i in ("%TEMP%\govman_env.tmp") do %%i
+del "%TEMP%\govman_env.tmp" 2>nul
+exit /b 0
 `
 
 	// Parse and execute template
 	t, err := template.New("wrapper").Parse(tmpl)
 	if err != nil {
-		return fmt.Errorf("failed to parse wrapper template: %w", err)
+		return FileChange{}, fmt.Errorf("failed to parse wrapper template: %w", err)
 	}
 
 	var buf strings.Builder
@@ -1099,17 +1350,25 @@ exit /b %errorlevel%
 	}
 
 	if err := t.Execute(&buf, data); err != nil {
-		return fmt.Errorf("failed to generate wrapper: %w", err)
+		return FileChange{}, fmt.Errorf("failed to generate wrapper: %w", err)
 	}
 
 	// Write wrapper file with CRLF line endings for Windows
 	content := strings.ReplaceAll(buf.String(), "\n", "\r\n")
-	if err := os.WriteFile(wrapperPath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to create wrapper: %w", err)
+	change, err := updateFile(wrapperPath, string(existing), content, exists, opts)
+	if err != nil {
+		return FileChange{}, fmt.Errorf("failed to create wrapper: %w", err)
+	}
+	if opts.DryRun {
+		return change, nil
 	}
 
 	// Print setup instructions (inline, no separate function)
-	fmt.Printf("✅ Created govman wrapper: %s\n\n", wrapperPath)
+	fmt.Printf("✅ Created govman wrapper: %s\n", wrapperPath)
+	if change.Backup != "" {
+		fmt.Printf("💾 Backup saved to: %s\n", change.Backup)
+	}
+	fmt.Println()
 	fmt.Println("📝 SETUP INSTRUCTIONS")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
@@ -1148,7 +1407,7 @@ exit /b %errorlevel%
 	fmt.Println("  wsl -e govman init")
 	fmt.Println()
 
-	return nil
+	return change, nil
 }
 
 // containsGovmanConfig checks if content contains govman configuration.
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -57,6 +58,20 @@ func TestDetect(t *testing.T) {
 			mockCommands: map[string]bool{"bash": true},
 			expectedType: &BashShell{},
 		},
+		{
+			name:         "Unix with nu in SHELL",
+			goos:         "linux",
+			shellEnv:     "/usr/bin/nu",
+			mockCommands: map[string]bool{"nu": true},
+			expectedType: &NuShell{},
+		},
+		{
+			name:         "Unix with xonsh in SHELL",
+			goos:         "linux",
+			shellEnv:     "/usr/local/bin/xonsh",
+			mockCommands: map[string]bool{"xonsh": true},
+			expectedType: &XonshShell{},
+		},
 		{
 			name:         "Unix with unknown SHELL",
 			goos:         "linux",
@@ -124,6 +139,14 @@ func TestDetect(t *testing.T) {
 				if _, ok := shell.(*CmdShell); !ok {
 					t.Errorf("Expected CmdShell, got %T", shell)
 				}
+			case *NuShell:
+				if _, ok := shell.(*NuShell); !ok {
+					t.Errorf("Expected NuShell, got %T", shell)
+				}
+			case *XonshShell:
+				if _, ok := shell.(*XonshShell); !ok {
+					t.Errorf("Expected XonshShell, got %T", shell)
+				}
 			}
 		})
 	}
@@ -568,6 +591,135 @@ func TestFishShell(t *testing.T) {
 	os.Stderr = oldStderr
 }
 
+func TestNuShell(t *testing.T) {
+	shell := &NuShell{}
+
+	if shell.Name() != "nu" {
+		t.Errorf("Expected 'nu', got %s", shell.Name())
+	}
+	if shell.DisplayName() != "Nushell" {
+		t.Errorf("Expected 'Nushell', got %s", shell.DisplayName())
+	}
+
+	originalLookPath := execLookPath
+	defer func() { execLookPath = originalLookPath }()
+	execLookPath = func(cmd string) (string, error) {
+		if cmd == "nu" {
+			return "/usr/bin/nu", nil
+		}
+		return "", exec.ErrNotFound
+	}
+	if !shell.IsAvailable() {
+		t.Error("Expected nu to be available")
+	}
+
+	// Test ConfigFile
+	originalUserHomeDir := userHomeDir
+	originalGOOS := currentGOOS
+	defer func() {
+		userHomeDir = originalUserHomeDir
+		currentGOOS = originalGOOS
+	}()
+	testHome := t.TempDir()
+	userHomeDir = func() (string, error) {
+		return testHome, nil
+	}
+	currentGOOS = "linux"
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	expected := filepath.Join(testHome, ".config", "nushell", "config.nu")
+	if shell.ConfigFile() != expected {
+		t.Errorf("Expected %s, got %s", expected, shell.ConfigFile())
+	}
+
+	currentGOOS = "darwin"
+	expected = filepath.Join(testHome, "Library", "Application Support", "nushell", "config.nu")
+	if shell.ConfigFile() != expected {
+		t.Errorf("Expected macOS default %s, got %s", expected, shell.ConfigFile())
+	}
+
+	xdgConfig := filepath.Join(testHome, "xdg", "nushell", "config.nu")
+	os.MkdirAll(filepath.Dir(xdgConfig), 0755)
+	os.WriteFile(xdgConfig, []byte(""), 0644)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(testHome, "xdg"))
+	if shell.ConfigFile() != xdgConfig {
+		t.Errorf("Expected existing %s, got %s", xdgConfig, shell.ConfigFile())
+	}
+	currentGOOS = "linux"
+
+	// Test PathCommand
+	if cmd := shell.PathCommand(`/opt/my "go"/bin`); cmd != `$env.PATH = ($env.PATH | split row (char esep) | prepend "/opt/my \"go\"/bin")` {
+		t.Errorf("PathCommand output incorrect: %s", cmd)
+	}
+
+	// Test SetupCommands
+	setup := strings.Join(shell.SetupCommands("/usr/local/bin"), "\n")
+	for _, expected := range []string{
+		"# GOVMAN - Go Version Manager",
+		"def --env --wrapped govman [...args]",
+		"env $version --shell nu",
+		`hooks.pre_prompt`,
+		`__govman_load_env (^"/usr/local/bin/govman" hook-env --shell nu)`,
+		"# END GOVMAN",
+	} {
+		if !strings.Contains(setup, expected) {
+			t.Errorf("SetupCommands should contain %q", expected)
+		}
+	}
+}
+
+func TestXonshShell(t *testing.T) {
+	shell := &XonshShell{}
+
+	if shell.Name() != "xonsh" {
+		t.Errorf("Expected 'xonsh', got %s", shell.Name())
+	}
+	if shell.DisplayName() != "Xonsh" {
+		t.Errorf("Expected 'Xonsh', got %s", shell.DisplayName())
+	}
+
+	// Test ConfigFile
+	originalUserHomeDir := userHomeDir
+	defer func() { userHomeDir = originalUserHomeDir }()
+	testHome := t.TempDir()
+	userHomeDir = func() (string, error) {
+		return testHome, nil
+	}
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	expected := filepath.Join(testHome, ".xonshrc")
+	if shell.ConfigFile() != expected {
+		t.Errorf("Expected %s, got %s", expected, shell.ConfigFile())
+	}
+
+	rcFile := filepath.Join(testHome, ".config", "xonsh", "rc.xsh")
+	os.MkdirAll(filepath.Dir(rcFile), 0755)
+	os.WriteFile(rcFile, []byte(""), 0644)
+	if shell.ConfigFile() != rcFile {
+		t.Errorf("Expected existing %s, got %s", rcFile, shell.ConfigFile())
+	}
+
+	// Test PathCommand
+	if cmd := shell.PathCommand(`C:\Go\bin`); cmd != `$PATH.insert(0, "C:\\Go\\bin")` {
+		t.Errorf("PathCommand output incorrect: %s", cmd)
+	}
+
+	// Test SetupCommands
+	setup := strings.Join(shell.SetupCommands("/usr/local/bin"), "\n")
+	for _, expected := range []string{
+		"# GOVMAN - Go Version Manager",
+		`aliases["govman"] = __govman_wrapper`,
+		"env @(version) --shell xonsh",
+		"@events.on_pre_prompt",
+		`$("/usr/local/bin/govman" hook-env --shell xonsh)`,
+		"# END GOVMAN",
+	} {
+		if !strings.Contains(setup, expected) {
+			t.Errorf("SetupCommands should contain %q", expected)
+		}
+	}
+}
+
 func TestPowerShell(t *testing.T) {
 	shell := &PowerShell{}
 
@@ -727,6 +879,71 @@ func TestCmdShell(t *testing.T) {
 	os.Stderr = oldStderr
 }
 
+func TestWrapperPrintsUseOutput(t *testing.T) {
+	testCases := []struct {
+		shell    Shell
+		expected string
+	}{
+		{&BashShell{}, `if [[ -n "$output" ]]; then echo "$output"; fi`},
+		{&ZshShell{}, `if [[ -n "$output" ]]; then echo "$output"; fi`},
+		{&FishShell{}, "        for line in $output\n            echo $line\n        end"},
+		{&PowerShell{}, "$output | ForEach-Object { Write-Host $_ }"},
+		{&NuShell{}, "print --no-newline $result.stdout"},
+		{&XonshShell{}, `print(result.output, end="")`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.shell.Name(), func(t *testing.T) {
+			setup := strings.Join(tc.shell.SetupCommands("/usr/local/bin"), "\n")
+			if !strings.Contains(setup, tc.expected) {
+				t.Errorf("Expected the wrapper to print the output of 'govman use' with %q", tc.expected)
+			}
+			if strings.Contains(setup, "switched successfully") {
+				t.Error("Expected the wrapper not to replace the output of 'govman use' with its own message")
+			}
+		})
+	}
+}
+
+func TestBashWrapperOutput(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil || runtime.GOOS == "windows" {
+		t.Skip("bash is not available")
+	}
+
+	// A stand-in for govman: 'use' prints log messages and 'env' prints the commands the wrapper evaluates
+	binDir := t.TempDir()
+	stub := `#!/bin/sh
+case "$1" in
+use) echo "Success: Now using Go $2 for this session" ;;
+env) echo "export GOVMAN_VERSION=$2" ;;
+esac
+`
+	os.WriteFile(filepath.Join(binDir, "govman"), []byte(stub), 0755)
+
+	setup := strings.Join((&BashShell{}).SetupCommands(binDir), "\n")
+	script := setup + "\ngovman use 1.22.1\necho \"active=$GOVMAN_VERSION\"\n"
+
+	cmd := exec.Command(bash, "--norc", "--noprofile", "-c", script)
+	cmd.Env = append(os.Environ(), "HOME="+t.TempDir())
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Wrapper failed: %v\n%s", err, output)
+	}
+
+	if !strings.Contains(string(output), "Success: Now using Go 1.22.1 for this session") {
+		t.Errorf("Expected the output of 'govman use', got:\n%s", output)
+	}
+	if !strings.Contains(string(output), "active=1.22.1") {
+		t.Errorf("Expected the wrapper to evaluate 'govman env', got:\n%s", output)
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "export ") || strings.Contains(line, "eval ") {
+			t.Errorf("Expected no shell commands to be echoed, got %q", line)
+		}
+	}
+}
+
 func TestInitializeShell(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -787,7 +1004,7 @@ func TestInitializeShell(t *testing.T) {
 			defer func() { os.Setenv("HOME", originalHome) }()
 			os.Setenv("HOME", tempDir)
 
-			err := InitializeShell(shell, tempDir, false)
+			_, err := InitializeShell(shell, tempDir, Options{})
 
 			if tc.expectError && err == nil {
 				t.Error("Expected error but got none")
@@ -812,7 +1029,7 @@ func TestInitializeShellCmd(t *testing.T) {
 	// Use a temporary directory to avoid conflicts
 	tempDir := t.TempDir()
 
-	err := InitializeShell(shell, tempDir, false)
+	_, err := InitializeShell(shell, tempDir, Options{})
 
 	if err != nil {
 		t.Errorf("Expected no error but got: %v", err)
@@ -1147,7 +1364,7 @@ func TestInitializeUnixShellReadError(t *testing.T) {
 	}
 
 	// Try to initialize - should fail due to permission error
-	err := initializeUnixShell(shell, tempDir, false)
+	_, err := initializeUnixShell(shell, tempDir, Options{})
 	if err == nil {
 		t.Error("Expected error due to permission denied reading config file")
 	}
@@ -1189,7 +1406,7 @@ func TestInitializePowerShellReadError(t *testing.T) {
 	}
 
 	// Try to initialize - should fail due to permission error
-	err := initializePowerShell(shell, tempDir, false)
+	_, err := initializePowerShell(shell, tempDir, Options{})
 	if err == nil {
 		t.Error("Expected error due to permission denied reading profile")
 	}
@@ -1206,7 +1423,7 @@ func TestInitializeCmdShellTemplateErrors(t *testing.T) {
 	// Test with a path that contains null byte - this should cause an error
 	invalidPath := filepath.Join(tempDir, "path with\x00null")
 
-	err := initializeCmdShell(shell, invalidPath, false)
+	_, err := initializeCmdShell(shell, invalidPath, Options{})
 	if err == nil {
 		t.Error("Expected error when creating wrapper with invalid path")
 	}
@@ -1452,7 +1669,7 @@ func TestInitializeShellErrors(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := InitializeShell(tc.shell, tc.binPath, false)
+			_, err := InitializeShell(tc.shell, tc.binPath, Options{})
 			if tc.expectError {
 				if err == nil {
 					t.Error("Expected error but got none")
@@ -1481,19 +1698,19 @@ func TestInitializeShellForce(t *testing.T) {
 	shell := &BashShell{}
 
 	// First initialization
-	err := InitializeShell(shell, tempDir, false)
+	_, err := InitializeShell(shell, tempDir, Options{})
 	if err != nil {
 		t.Fatalf("First initialization failed: %v", err)
 	}
 
 	// Second initialization without force should fail
-	err = InitializeShell(shell, tempDir, false)
+	_, err = InitializeShell(shell, tempDir, Options{})
 	if err == nil {
 		t.Error("Expected error for second initialization without force")
 	}
 
 	// Second initialization with force should succeed
-	err = InitializeShell(shell, tempDir, true)
+	_, err = InitializeShell(shell, tempDir, Options{Force: true})
 	if err != nil {
 		t.Errorf("Second initialization with force failed: %v", err)
 	}
@@ -1829,7 +2046,7 @@ func TestInitializeShellWithExistingConfig(t *testing.T) {
 					os.WriteFile(configFile, []byte(tc.existingCfg), 0644)
 				}
 
-				err := InitializeShell(tc.shell, tempDir, tc.force)
+				_, err := InitializeShell(tc.shell, tempDir, Options{Force: tc.force})
 				if tc.expectError {
 					if err == nil {
 						t.Errorf("Expected error but got none")
@@ -1855,7 +2072,7 @@ func TestInitializeShellWithExistingConfig(t *testing.T) {
 					os.WriteFile(configFile, []byte(tc.existingCfg), 0644)
 				}
 
-				err := InitializeShell(tc.shell, tempDir, tc.force)
+				_, err := InitializeShell(tc.shell, tempDir, Options{Force: tc.force})
 				if tc.expectError {
 					if err == nil {
 						t.Errorf("Expected error but got none")
@@ -1883,7 +2100,7 @@ func TestInitializeShellWithExistingConfig(t *testing.T) {
 					os.WriteFile(configFile, []byte(tc.existingCfg), 0644)
 				}
 
-				err := InitializeShell(tc.shell, tempDir, tc.force)
+				_, err := InitializeShell(tc.shell, tempDir, Options{Force: tc.force})
 				if tc.expectError {
 					if err == nil {
 						t.Errorf("Expected error but got none")
@@ -1936,7 +2153,7 @@ func TestInitializeShellWithExistingConfig(t *testing.T) {
 					}
 				}
 
-				err := InitializeShell(tc.shell, tempDir, tc.force)
+				_, err := InitializeShell(tc.shell, tempDir, Options{Force: tc.force})
 				if tc.expectError {
 					if err == nil {
 						t.Errorf("Expected error but got none")
@@ -1956,7 +2173,7 @@ func TestInitializeShellWithExistingConfig(t *testing.T) {
 				wrapperPath := filepath.Join(tempDir, "govman_wrapper.bat")
 				os.WriteFile(wrapperPath, []byte("@echo off"), 0644)
 
-				err := InitializeShell(tc.shell, tempDir, tc.force)
+				_, err := InitializeShell(tc.shell, tempDir, Options{Force: tc.force})
 				if tc.expectError {
 					if err == nil {
 						t.Errorf("Expected error but got none")
@@ -0,0 +1,122 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Subshell is an interactive shell process prepared by NewSubshell.
+type Subshell struct {
+	Path string      // Executable to run
+	Args []string    // Arguments, without the executable
+	Env  []EnvChange // Extra environment for the shell
+
+	tempDir string // Startup files removed by Cleanup
+}
+
+// NewSubshell prepares an interactive instance of shell whose prompt starts with marker.
+// The user's own startup files are still loaded. Shells without a known way to change the
+// prompt start unmodified. Call Cleanup once the shell exits.
+func NewSubshell(shell Shell, marker string) (*Subshell, error) {
+	sub := &Subshell{Path: Executable(shell)}
+
+	switch shell.Name() {
+	case "bash":
+		// --rcfile replaces .bashrc, so load the user's configuration from it first
+		rcFile := fmt.Sprintf("if [ -f %[1]s ]; then . %[1]s; fi\nPS1=%[2]s\"$PS1\"\n",
+			quotePOSIX(shell.ConfigFile()), quotePOSIX(marker))
+		if err := sub.writeStartupFile("bashrc", rcFile); err != nil {
+			return nil, err
+		}
+		sub.Args = []string{"--rcfile", filepath.Join(sub.tempDir, "bashrc"), "-i"}
+	case "zsh":
+		// zsh reads its startup files from ZDOTDIR; chain to the user's files from a temporary one
+		userDir := os.Getenv("ZDOTDIR")
+		if userDir == "" {
+			userDir, _ = userHomeDir()
+		}
+		zshrc := fmt.Sprintf("ZDOTDIR=%[1]s\nif [[ -f \"$ZDOTDIR/.zshrc\" ]]; then source \"$ZDOTDIR/.zshrc\"; fi\nPROMPT=%[2]s\"$PROMPT\"\n",
+			quotePOSIX(userDir), quotePOSIX(marker))
+		if err := sub.writeStartupFile(".zshrc", zshrc); err != nil {
+			return nil, err
+		}
+		// .zshenv must point ZDOTDIR back at the temporary directory so .zshrc above is read
+		zshenv := fmt.Sprintf("ZDOTDIR=%[1]s\nif [[ -f \"$ZDOTDIR/.zshenv\" ]]; then source \"$ZDOTDIR/.zshenv\"; fi\nZDOTDIR=%[2]s\n",
+			quotePOSIX(userDir), quotePOSIX(sub.tempDir))
+		if err := sub.writeStartupFile(".zshenv", zshenv); err != nil {
+			return nil, err
+		}
+		sub.Env = []EnvChange{{Name: "ZDOTDIR", Value: sub.tempDir}}
+		sub.Args = []string{"-i"}
+	case "fish":
+		// -C runs after config.fish, so the user's prompt can be wrapped
+		sub.Args = []string{"-C", fmt.Sprintf(
+			"functions -c fish_prompt __govman_fish_prompt; function fish_prompt; printf '%%s' %s; __govman_fish_prompt; end",
+			quoteFish(marker))}
+	case "powershell":
+		// -NoExit keeps the session open after wrapping the profile's prompt
+		sub.Args = []string{"-NoLogo", "-NoExit", "-Command", fmt.Sprintf(
+			"$global:__GovmanPrompt = $function:prompt; function global:prompt { %s + (& $global:__GovmanPrompt) }",
+			quotePowerShell(marker))}
+	case "cmd":
+		prompt := os.Getenv("PROMPT")
+		if prompt == "" {
+			prompt = "$P$G"
+		}
+		sub.Env = []EnvChange{{Name: "PROMPT", Value: marker + prompt}}
+		sub.Args = []string{"/K"}
+	}
+
+	return sub, nil
+}
+
+// writeStartupFile writes a startup file into the subshell's temporary directory, creating it if needed.
+func (s *Subshell) writeStartupFile(name, content string) error {
+	if s.tempDir == "" {
+		dir, err := os.MkdirTemp("", "govman-shell-")
+		if err != nil {
+			return fmt.Errorf("failed to create shell startup directory: %w", err)
+		}
+		s.tempDir = dir
+	}
+
+	if err := os.WriteFile(filepath.Join(s.tempDir, name), []byte(content), 0600); err != nil {
+		s.Cleanup()
+		return fmt.Errorf("failed to write shell startup file: %w", err)
+	}
+
+	return nil
+}
+
+// Cleanup removes the temporary startup files created for the subshell.
+func (s *Subshell) Cleanup() {
+	if s.tempDir != "" {
+		os.RemoveAll(s.tempDir)
+		s.tempDir = ""
+	}
+}
+
+// Executable returns the program that starts shell, preferring the path in $SHELL when it names the same shell.
+func Executable(shell Shell) string {
+	var candidates []string
+	switch shell.Name() {
+	case "powershell":
+		candidates = []string{"pwsh", "powershell"}
+	case "cmd":
+		candidates = []string{"cmd.exe"}
+	default:
+		if shellPath := os.Getenv("SHELL"); shellPath != "" && filepath.Base(shellPath) == shell.Name() {
+			return shellPath
+		}
+		candidates = []string{shell.Name()}
+	}
+
+	for _, candidate := range candidates {
+		if path, err := execLookPath(candidate); err == nil {
+			return path
+		}
+	}
+
+	return candidates[0]
+}
@@ -0,0 +1,122 @@
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewSubshell(t *testing.T) {
+	home := setupDeinitTest(t)
+	t.Setenv("ZDOTDIR", "")
+	t.Setenv("PROMPT", "")
+
+	testCases := []struct {
+		shell    Shell
+		args     []string // Expected arguments, checked by prefix
+		env      string   // Expected extra variable
+		contains string   // Expected text in the arguments or startup files
+	}{
+		{shell: &BashShell{}, args: []string{"--rcfile"}, contains: "PS1='(go1.22.1) '\"$PS1\""},
+		{shell: &ZshShell{}, args: []string{"-i"}, env: "ZDOTDIR", contains: "PROMPT='(go1.22.1) '\"$PROMPT\""},
+		{shell: &FishShell{}, args: []string{"-C"}, contains: "printf '%s' '(go1.22.1) '"},
+		{shell: &PowerShell{}, args: []string{"-NoLogo", "-NoExit", "-Command"}, contains: "'(go1.22.1) ' + (& $global:__GovmanPrompt)"},
+		{shell: &CmdShell{}, args: []string{"/K"}, env: "PROMPT"},
+		{shell: &NuShell{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.shell.Name(), func(t *testing.T) {
+			sub, err := NewSubshell(tc.shell, "(go1.22.1) ")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(sub.Args) < len(tc.args) || strings.Join(sub.Args[:len(tc.args)], " ") != strings.Join(tc.args, " ") {
+				t.Errorf("Expected arguments starting with %v, got %v", tc.args, sub.Args)
+			}
+			if tc.env != "" && (len(sub.Env) != 1 || sub.Env[0].Name != tc.env) {
+				t.Errorf("Expected %s in the environment, got %+v", tc.env, sub.Env)
+			}
+
+			text := strings.Join(sub.Args, " ")
+			if sub.tempDir != "" {
+				files, _ := filepath.Glob(filepath.Join(sub.tempDir, "*"))
+				dotFiles, _ := filepath.Glob(filepath.Join(sub.tempDir, ".*"))
+				for _, file := range append(files, dotFiles...) {
+					content, _ := os.ReadFile(file)
+					text += "\n" + string(content)
+				}
+			}
+			if !strings.Contains(text, tc.contains) {
+				t.Errorf("Expected %q in the startup commands, got:\n%s", tc.contains, text)
+			}
+
+			tempDir := sub.tempDir
+			sub.Cleanup()
+			if tempDir != "" {
+				if _, err := os.Stat(tempDir); !os.IsNotExist(err) {
+					t.Errorf("Expected %s to be removed", tempDir)
+				}
+			}
+		})
+	}
+
+	t.Run("bash loads the user's bashrc", func(t *testing.T) {
+		sub, err := NewSubshell(&BashShell{}, "(go1.22.1) ")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer sub.Cleanup()
+
+		content, _ := os.ReadFile(sub.Args[1])
+		if !strings.Contains(string(content), quotePOSIX(filepath.Join(home, ".bashrc"))) {
+			t.Errorf("Expected the rc file to source .bashrc, got:\n%s", content)
+		}
+	})
+
+	t.Run("cmd keeps the current prompt", func(t *testing.T) {
+		t.Setenv("PROMPT", "$T$G")
+		sub, _ := NewSubshell(&CmdShell{}, "(go1.22.1) ")
+		if sub.Env[0].Value != "(go1.22.1) $T$G" {
+			t.Errorf("Expected the marker before the prompt, got %s", sub.Env[0].Value)
+		}
+	})
+}
+
+func TestExecutable(t *testing.T) {
+	originalLookPath := execLookPath
+	defer func() { execLookPath = originalLookPath }()
+
+	available := map[string]string{"powershell": "/usr/bin/powershell", "zsh": "/usr/bin/zsh"}
+	execLookPath = func(file string) (string, error) {
+		if path, ok := available[file]; ok {
+			return path, nil
+		}
+		return "", exec.ErrNotFound
+	}
+
+	testCases := []struct {
+		name     string
+		shell    Shell
+		envShell string
+		expected string
+	}{
+		{name: "$SHELL matches", shell: &BashShell{}, envShell: "/opt/bin/bash", expected: "/opt/bin/bash"},
+		{name: "$SHELL is another shell", shell: &ZshShell{}, envShell: "/bin/bash", expected: "/usr/bin/zsh"},
+		{name: "Windows PowerShell fallback", shell: &PowerShell{}, expected: "/usr/bin/powershell"},
+		{name: "Not found", shell: &FishShell{}, expected: "fish"},
+		{name: "Command Prompt", shell: &CmdShell{}, expected: "cmd.exe"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("SHELL", tc.envShell)
+			if result := Executable(tc.shell); result != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, result)
+			}
+		})
+	}
+}
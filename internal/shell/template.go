@@ -0,0 +1,243 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// templateShells holds the user-defined shells registered with RegisterTemplates, by name
+var templateShells = map[string]*TemplateShell{}
+
+// TemplateShell is a Shell whose configuration is rendered from a user-provided text/template file.
+// The file defines the named templates "config_file" and "setup", and optionally "path",
+// "display_name" and "comment".
+type TemplateShell struct {
+	name string
+	tmpl *template.Template
+}
+
+// templateData is the data available to shell templates
+type templateData struct {
+	Name    string // Shell name (the template file name without .tmpl)
+	BinPath string // govman bin directory
+	Path    string // Directory to put on PATH (PathCommand only)
+	Govman  string // Path of the govman executable
+	Home    string // User home directory
+	OS      string // Operating system (runtime.GOOS)
+}
+
+// templateFuncs are the helpers available to shell templates
+var templateFuncs = template.FuncMap{
+	"quotePOSIX":      quotePOSIX,
+	"quoteFish":       quoteFish,
+	"quotePowerShell": quotePowerShell,
+}
+
+// TemplateDir returns the directory holding user-defined shell templates, next to the bin directory binPath.
+func TemplateDir(binPath string) string {
+	return filepath.Join(filepath.Dir(binPath), "shells")
+}
+
+// LoadTemplateShell parses a shell template file named <name>.tmpl.
+// Returns an error if the file cannot be parsed or lacks the required templates.
+func LoadTemplateShell(path string) (*TemplateShell, error) {
+	name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return nil, fmt.Errorf("invalid shell name %q", name)
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse shell template %s: %w", path, err)
+	}
+
+	for _, required := range []string{"config_file", "setup"} {
+		if tmpl.Lookup(required) == nil {
+			return nil, fmt.Errorf("shell template %s does not define %q", path, required)
+		}
+	}
+
+	shell := &TemplateShell{name: name, tmpl: tmpl}
+
+	// Render every template once so mistakes surface when loading, not when writing files
+	for _, defined := range []string{"config_file", "setup", "path", "display_name", "comment"} {
+		if tmpl.Lookup(defined) == nil {
+			continue
+		}
+		if _, err := shell.render(defined, "/govman/bin", "/govman/bin"); err != nil {
+			return nil, fmt.Errorf("invalid shell template %s: %w", path, err)
+		}
+	}
+
+	return shell, nil
+}
+
+// RegisterTemplates loads every <name>.tmpl in dir and registers it as a shell, replacing any
+// built-in shell of the same name. A missing directory is not an error; invalid templates are
+// skipped and reported together in the returned error.
+func RegisterTemplates(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return fmt.Errorf("failed to list shell templates: %w", err)
+	}
+
+	var errs []error
+	for _, path := range paths {
+		shell, err := LoadTemplateShell(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		templateShells[strings.ToLower(shell.Name())] = shell
+	}
+
+	return errors.Join(errs...)
+}
+
+// LookupTemplate returns the user-defined shell registered as name, or nil if there is none.
+func LookupTemplate(name string) Shell {
+	if shell, ok := templateShells[strings.ToLower(name)]; ok {
+		return shell
+	}
+	return nil
+}
+
+// withTemplates replaces built-in shells with registered templates of the same name and
+// appends the remaining templates in name order.
+func withTemplates(shells []Shell) []Shell {
+	used := make(map[string]bool)
+
+	result := make([]Shell, 0, len(shells)+len(templateShells))
+	for _, shell := range shells {
+		if templateShell, ok := templateShells[shell.Name()]; ok {
+			used[shell.Name()] = true
+			result = append(result, templateShell)
+			continue
+		}
+		result = append(result, shell)
+	}
+
+	var names []string
+	for name := range templateShells {
+		if !used[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		result = append(result, templateShells[name])
+	}
+
+	return result
+}
+
+// render executes the named template with data for binPath and path.
+func (s *TemplateShell) render(name, binPath, path string) (string, error) {
+	home, _ := userHomeDir()
+	data := templateData{
+		Name:    s.name,
+		BinPath: binPath,
+		Path:    path,
+		Govman:  govmanExecutable(binPath),
+		Home:    home,
+		OS:      currentGOOS,
+	}
+
+	var buf strings.Builder
+	if err := s.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+
+	return strings.Trim(buf.String(), "\r\n"), nil
+}
+
+// Name returns the template file name without its .tmpl extension.
+func (s *TemplateShell) Name() string {
+	return s.name
+}
+
+// DisplayName returns the "display_name" template, or the shell name if it is not defined.
+func (s *TemplateShell) DisplayName() string {
+	if s.tmpl.Lookup("display_name") != nil {
+		if name, err := s.render("display_name", "", ""); err == nil && name != "" {
+			return strings.TrimSpace(name)
+		}
+	}
+	return s.name
+}
+
+// IsAvailable reports whether a command named after the shell is present in the system PATH.
+func (s *TemplateShell) IsAvailable() bool {
+	return isCommandAvailable(s.name)
+}
+
+// ConfigFile returns the rendered "config_file" template, with a leading ~ expanded to the home directory.
+func (s *TemplateShell) ConfigFile() string {
+	configFile, err := s.render("config_file", "", "")
+	if err != nil {
+		return ""
+	}
+	configFile = strings.TrimSpace(configFile)
+
+	if configFile == "~" || strings.HasPrefix(configFile, "~/") {
+		if home, err := userHomeDir(); err == nil {
+			configFile = filepath.Join(home, strings.TrimPrefix(configFile, "~"))
+		}
+	}
+
+	return configFile
+}
+
+// PathCommand returns the rendered "path" template, or a POSIX export if it is not defined.
+func (s *TemplateShell) PathCommand(path string) string {
+	if s.tmpl.Lookup("path") == nil {
+		return fmt.Sprintf(`export PATH="%s:$PATH"`, escapeBashPath(path))
+	}
+
+	command, err := s.render("path", "", path)
+	if err != nil {
+		return ""
+	}
+	return command
+}
+
+// SetupCommands returns the rendered "setup" template lines. Unless the template already
+// includes them, the lines are wrapped in the GOVMAN marker comments (using the "comment"
+// template as the comment prefix, "#" by default) so that init --force and deinit can find them.
+func (s *TemplateShell) SetupCommands(binPath string) []string {
+	setup, err := s.render("setup", binPath, binPath)
+	if err != nil {
+		return nil
+	}
+	commands := strings.Split(strings.ReplaceAll(setup, "\r\n", "\n"), "\n")
+
+	if strings.Contains(setup, "GOVMAN - Go Version Manager") {
+		return commands
+	}
+
+	comment := "#"
+	if s.tmpl.Lookup("comment") != nil {
+		if prefix, err := s.render("comment", binPath, binPath); err == nil && strings.TrimSpace(prefix) != "" {
+			comment = strings.TrimSpace(prefix)
+		}
+	}
+
+	commands = append([]string{comment + " GOVMAN - Go Version Manager"}, commands...)
+	return append(commands, comment+" END GOVMAN")
+}
+
+// ExecutePathCommand outputs the rendered PATH command.
+func (s *TemplateShell) ExecutePathCommand(path string) error {
+	if err := validateBinPath(path); err != nil {
+		return err
+	}
+
+	fmt.Println(s.PathCommand(path))
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTemplate writes a shell template file into dir and returns its path
+func writeTemplate(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name+".tmpl")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	return path
+}
+
+// resetTemplates clears the template registry after a test
+func resetTemplates(t *testing.T) {
+	t.Helper()
+	original := templateShells
+	templateShells = map[string]*TemplateShell{}
+	t.Cleanup(func() { templateShells = original })
+}
+
+func TestLoadTemplateShell(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		name        string
+		content     string
+		expectError string
+	}{
+		{"valid", `{{define "config_file"}}~/.rc{{end}}{{define "setup"}}x{{end}}`, ""},
+		{"nosetup", `{{define "config_file"}}~/.rc{{end}}`, `does not define "setup"`},
+		{"noconfig", `{{define "setup"}}x{{end}}`, `does not define "config_file"`},
+		{"syntax", `{{define "setup"}}{{.BinPath{{end}}`, "failed to parse"},
+		{"field", `{{define "config_file"}}~/.rc{{end}}{{define "setup"}}{{.Unknown}}{{end}}`, "invalid shell template"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			shell, err := LoadTemplateShell(writeTemplate(t, dir, tc.name, tc.content))
+			if tc.expectError == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if shell.Name() != tc.name {
+					t.Errorf("Expected name %s, got %s", tc.name, shell.Name())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectError, err)
+			}
+		})
+	}
+}
+
+func TestTemplateShell(t *testing.T) {
+	home := setupDeinitTest(t)
+	dir := t.TempDir()
+
+	shell, err := LoadTemplateShell(writeTemplate(t, dir, "ksh", `
+{{define "display_name"}}KornShell{{end}}
+{{define "config_file"}}~/.kshrc{{end}}
+{{define "path"}}export PATH={{quotePOSIX .Path}}:"$PATH"{{end}}
+{{define "setup"}}
+export PATH={{quotePOSIX .BinPath}}:"$PATH"
+alias gv={{quotePOSIX .Govman}}
+{{end}}
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if shell.DisplayName() != "KornShell" {
+		t.Errorf("Expected display name KornShell, got %s", shell.DisplayName())
+	}
+	if expected := filepath.Join(home, ".kshrc"); shell.ConfigFile() != expected {
+		t.Errorf("Expected %s, got %s", expected, shell.ConfigFile())
+	}
+	if cmd := shell.PathCommand("/opt/it's"); cmd != `export PATH='/opt/it'\''s':"$PATH"` {
+		t.Errorf("Unexpected PathCommand %s", cmd)
+	}
+
+	expected := []string{
+		"# GOVMAN - Go Version Manager",
+		`export PATH='/govman/bin':"$PATH"`,
+		`alias gv='/govman/bin/govman'`,
+		"# END GOVMAN",
+	}
+	if commands := shell.SetupCommands("/govman/bin"); strings.Join(commands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(commands, "\n"))
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		minimal, err := LoadTemplateShell(writeTemplate(t, dir, "elvish", `{{define "config_file"}}{{.Home}}/rc.elv{{end}}{{define "comment"}}--{{end}}{{define "setup"}}set paths = [{{.BinPath}} $@paths]{{end}}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if minimal.DisplayName() != "elvish" {
+			t.Errorf("Expected display name to default to the shell name, got %s", minimal.DisplayName())
+		}
+		if cmd := minimal.PathCommand("/opt/go/bin"); cmd != `export PATH="/opt/go/bin:$PATH"` {
+			t.Errorf("Expected POSIX PathCommand by default, got %s", cmd)
+		}
+
+		// The comment prefix must still be recognised when removing the block
+		setup := "keep\n" + strings.Join(minimal.SetupCommands("/govman/bin"), "\n") + "\n"
+		if !strings.HasPrefix(setup, "keep\n-- GOVMAN - Go Version Manager\n") {
+			t.Errorf("Unexpected setup:\n%s", setup)
+		}
+		if cleaned := removeExistingConfig(setup); cleaned != "keep" {
+			t.Errorf("Expected the block to be removed, got %q", cleaned)
+		}
+	})
+}
+
+func TestRegisterTemplates(t *testing.T) {
+	setupDeinitTest(t)
+	resetTemplates(t)
+	dir := t.TempDir()
+
+	writeTemplate(t, dir, "bash", `{{define "config_file"}}~/.config/bash/rc{{end}}{{define "setup"}}export PATH={{quotePOSIX .BinPath}}:"$PATH"{{end}}`)
+	writeTemplate(t, dir, "ksh", `{{define "config_file"}}~/.kshrc{{end}}{{define "setup"}}x{{end}}`)
+	writeTemplate(t, dir, "broken", `{{define "setup"}}x{{end}}`)
+
+	if err := RegisterTemplates(dir); err == nil || !strings.Contains(err.Error(), "broken.tmpl") {
+		t.Errorf("Expected error for broken.tmpl, got %v", err)
+	}
+
+	if err := RegisterTemplates(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("Expected a missing directory to be ignored, got %v", err)
+	}
+
+	shell, err := ForName("KSH")
+	if err != nil || shell.ConfigFile() == "" {
+		t.Fatalf("Expected ksh template, got %v (%v)", shell, err)
+	}
+
+	// A template replaces the built-in shell of the same name
+	if shell, _ := ForName("bash"); !strings.HasSuffix(shell.ConfigFile(), filepath.Join(".config", "bash", "rc")) {
+		t.Errorf("Expected bash template to override the built-in shell, got %s", shell.ConfigFile())
+	}
+
+	var names []string
+	for _, shell := range All() {
+		if _, ok := shell.(*TemplateShell); ok {
+			names = append(names, shell.Name())
+		}
+	}
+	if strings.Join(names, ",") != "bash,ksh" {
+		t.Errorf("Expected All to include the bash and ksh templates, got %v", names)
+	}
+	if len(All()) != 8 {
+		t.Errorf("Expected 7 built-in shells plus ksh, got %d", len(All()))
+	}
+}
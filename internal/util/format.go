@@ -2,6 +2,8 @@ package util
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -49,3 +51,47 @@ func FormatDuration(d time.Duration) string {
 	minutes := int(d.Minutes()) % 60
 	return fmt.Sprintf("%dh%dm", hours, minutes)
 }
+
+// ParseBytes parses a human-readable size such as "512MB", "2.5 GiB", or "1048576" into bytes.
+// Units are binary (1 KB = 1024 bytes), matching FormatBytes. Returns the size or an error.
+func ParseBytes(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	s = strings.TrimSuffix(strings.Replace(s, "IB", "B", 1), "B")
+
+	multiplier := int64(1)
+	if s != "" {
+		if i := strings.IndexByte("KMGTPE", s[len(s)-1]); i >= 0 {
+			multiplier = int64(1) << (10 * (i + 1))
+			s = s[:len(s)-1]
+		}
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size %q: expected a value such as 500MB or 2GB", value)
+	}
+
+	return int64(number * float64(multiplier)), nil
+}
+
+// ParseAge parses a duration that may also use days ("30d") or weeks ("2w") in addition to
+// the units accepted by time.ParseDuration. Returns the duration or an error.
+func ParseAge(value string) (time.Duration, error) {
+	s := strings.TrimSpace(value)
+
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid age %q: expected a value such as 30d or 12h", value)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q: expected a value such as 30d or 12h", value)
+	}
+	return d, nil
+}
@@ -170,3 +170,71 @@ func TestFormatDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBytes(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{input: "1048576", expected: 1048576},
+		{input: "512B", expected: 512},
+		{input: "10KB", expected: 10 * 1024},
+		{input: "500MB", expected: 500 * 1024 * 1024},
+		{input: "2gb", expected: 2 * 1024 * 1024 * 1024},
+		{input: "1.5 GiB", expected: 1536 * 1024 * 1024},
+		{input: "3G", expected: 3 * 1024 * 1024 * 1024},
+		{input: "", wantErr: true},
+		{input: "MB", wantErr: true},
+		{input: "-1GB", wantErr: true},
+		{input: "lots", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := ParseBytes(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("ParseBytes(%q) expected error, got %d", tc.input, result)
+				}
+				return
+			}
+			if err != nil || result != tc.expected {
+				t.Errorf("ParseBytes(%q) = %d, %v; want %d", tc.input, result, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{input: "30d", expected: 30 * 24 * time.Hour},
+		{input: "2w", expected: 14 * 24 * time.Hour},
+		{input: "1.5d", expected: 36 * time.Hour},
+		{input: "12h", expected: 12 * time.Hour},
+		{input: "90m", expected: 90 * time.Minute},
+		{input: "", wantErr: true},
+		{input: "d", wantErr: true},
+		{input: "-1d", wantErr: true},
+		{input: "soon", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := ParseAge(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("ParseAge(%q) expected error, got %v", tc.input, result)
+				}
+				return
+			}
+			if err != nil || result != tc.expected {
+				t.Errorf("ParseAge(%q) = %v, %v; want %v", tc.input, result, err, tc.expected)
+			}
+		})
+	}
+}